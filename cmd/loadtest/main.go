@@ -0,0 +1,216 @@
+// 壓力測試工具：對本機伺服器註冊大量帳號並同時連線，每桌由一位房主建立房間、其餘玩家加入後開局，
+// 依簡單的機器人邏輯出牌與防守，最後回報指令延遲分位數、掉線數與伺服器的 CPU、記憶體用量。
+// 伺服器需以 --stats 啟動才能取得負載統計
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type config struct {
+	server       string
+	clients      int
+	perTable     int
+	games        int
+	ramp         time.Duration
+	timeout      time.Duration
+	prefix       string
+	password     string
+	turnTimeout  int
+	defenseLimit int
+}
+
+type authRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type authResponse struct {
+	Token string `json:"token"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+func main() {
+	cfg := config{}
+	flag.StringVar(&cfg.server, "server", "http://localhost:8080", "伺服器位址")
+	flag.IntVar(&cfg.clients, "clients", 200, "同時連線的玩家數")
+	flag.IntVar(&cfg.perTable, "per-table", 8, "每桌真人數（1–8），不足的座位由伺服器機器人補滿")
+	flag.IntVar(&cfg.games, "games", 1, "每桌連續進行的對局數，第二局起以再戰開局")
+	flag.DurationVar(&cfg.ramp, "ramp", 10*time.Second, "各桌開始連線的時間分散於此區間內")
+	flag.DurationVar(&cfg.timeout, "timeout", 15*time.Minute, "整體時限，逾時仍未完成的桌次視為未完成")
+	flag.StringVar(&cfg.prefix, "prefix", fmt.Sprintf("load%d", time.Now().Unix()%100000), "測試帳號名稱前綴")
+	flag.StringVar(&cfg.password, "password", "loadtest", "測試帳號密碼")
+	flag.IntVar(&cfg.turnTimeout, "turn-timeout", 10, "房間回合時限（秒），玩家無牌可出時由系統代打")
+	flag.IntVar(&cfg.defenseLimit, "defense-timeout", 5, "房間防守時限（秒）")
+	flag.Parse()
+
+	if cfg.clients <= 0 || cfg.perTable < 1 || cfg.perTable > 8 || cfg.games < 1 {
+		log.Fatal("clients 需為正數、per-table 需介於 1–8、games 至少為 1")
+	}
+	cfg.server = strings.TrimRight(cfg.server, "/")
+
+	before, statsErr := fetchStats(cfg.server)
+	if statsErr != nil {
+		log.Printf("無法取得伺服器負載統計（請以 --stats 啟動伺服器）: %v", statsErr)
+	}
+
+	log.Printf("註冊 %d 個帳號…", cfg.clients)
+	tokens, err := registerAccounts(cfg)
+	if err != nil {
+		log.Fatalf("註冊帳號失敗: %v", err)
+	}
+
+	rec := newRecorder()
+	tables := make([]*table, 0, (cfg.clients+cfg.perTable-1)/cfg.perTable)
+	for start := 0; start < len(tokens); start += cfg.perTable {
+		end := min(start+cfg.perTable, len(tokens))
+		tables = append(tables, newTable(len(tables), cfg, tokens[start:end], rec))
+	}
+
+	log.Printf("開始 %d 桌、%d 條連線", len(tables), cfg.clients)
+	started := time.Now()
+	deadline := time.After(cfg.timeout)
+	var wg sync.WaitGroup
+	for i, t := range tables {
+		delay := time.Duration(0)
+		if len(tables) > 1 {
+			delay = cfg.ramp * time.Duration(i) / time.Duration(len(tables)-1)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(delay)
+			t.run()
+		}()
+	}
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-deadline:
+		log.Printf("已達時限 %s，結束測試", cfg.timeout)
+	}
+	elapsed := time.Since(started)
+
+	after, afterErr := fetchStats(cfg.server)
+	for _, t := range tables {
+		t.close()
+	}
+
+	report := buildReport(tables, rec, elapsed)
+	if statsErr == nil && afterErr == nil {
+		report.server = &serverUsage{before: before, after: after}
+	}
+	report.print(os.Stdout)
+}
+
+// registerAccounts 以少量並行註冊帳號；帳號已存在時改為登入，方便以相同前綴重跑
+func registerAccounts(cfg config) ([]string, error) {
+	tokens := make([]string, cfg.clients)
+	errs := make(chan error, cfg.clients)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				token, err := authenticate(cfg, fmt.Sprintf("%s_%d", cfg.prefix, i))
+				if err != nil {
+					errs <- err
+					continue
+				}
+				tokens[i] = token
+			}
+		}()
+	}
+	for i := 0; i < cfg.clients; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func authenticate(cfg config, username string) (string, error) {
+	resp, err := postAuth(cfg.server+"/api/register", username, cfg.password)
+	if err != nil {
+		return "", err
+	}
+	if resp.Code == "user_exists" {
+		resp, err = postAuth(cfg.server+"/api/login", username, cfg.password)
+		if err != nil {
+			return "", err
+		}
+	}
+	if resp.Token == "" {
+		return "", fmt.Errorf("%s: %s (%s)", username, resp.Error, resp.Code)
+	}
+	return resp.Token, nil
+}
+
+func postAuth(url, username, password string) (authResponse, error) {
+	body, err := json.Marshal(authRequest{Username: username, Password: password})
+	if err != nil {
+		return authResponse{}, err
+	}
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return authResponse{}, err
+	}
+	defer res.Body.Close()
+	var resp authResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return authResponse{}, fmt.Errorf("解析 %s 回應失敗: %w", url, err)
+	}
+	return resp, nil
+}
+
+// serverStats 對應伺服器 /debug/stats 的內容
+type serverStats struct {
+	Connections    int     `json:"connections"`
+	Rooms          int     `json:"rooms"`
+	RunningGames   int     `json:"runningGames"`
+	Coalesced      int64   `json:"coalescedMessages"`
+	OverflowEvents int64   `json:"overflowEvents"`
+	DroppedClients int64   `json:"droppedClients"`
+	Goroutines     int     `json:"goroutines"`
+	HeapBytes      uint64  `json:"heapBytes"`
+	SysBytes       uint64  `json:"sysBytes"`
+	CPUSeconds     float64 `json:"cpuSeconds"`
+	UptimeSeconds  float64 `json:"uptimeSeconds"`
+}
+
+func fetchStats(server string) (serverStats, error) {
+	res, err := http.Get(server + "/debug/stats")
+	if err != nil {
+		return serverStats{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return serverStats{}, errors.New(res.Status)
+	}
+	var stats serverStats
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return serverStats{}, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"zombierush/internal/jsonpatch"
+)
+
+// protocolVersion 為壓測連線宣告的協定版本，與網頁前端相同，會收到狀態差異
+const protocolVersion = 3
+
+// table 為一桌：第一位玩家擔任房主建立房間，其餘玩家以房間 ID 連線加入
+type table struct {
+	id      int
+	cfg     config
+	players []*player
+
+	mu       sync.Mutex
+	finished bool
+}
+
+func newTable(id int, cfg config, tokens []string, rec *recorder) *table {
+	t := &table{id: id, cfg: cfg}
+	for i, token := range tokens {
+		t.players = append(t.players, &player{
+			table:   t,
+			host:    i == 0,
+			token:   token,
+			rec:     rec,
+			seat:    -1,
+			rng:     rand.New(rand.NewSource(int64(id*8 + i))),
+			pending: make(map[string]pendingRequest),
+			states:  make(map[int64]any),
+			welcome: make(chan struct{}),
+			done:    make(chan struct{}),
+			closed:  make(chan struct{}),
+		})
+	}
+	return t
+}
+
+func (t *table) run() {
+	host := t.players[0]
+	if err := host.connect(""); err != nil {
+		log.Printf("第 %d 桌房主連線失敗: %v", t.id, err)
+		return
+	}
+	host.request("room_create", map[string]any{"name": fmt.Sprintf("壓測 %d", t.id)})
+	if !host.waitWelcome(30 * time.Second) {
+		log.Printf("第 %d 桌建立房間逾時", t.id)
+		return
+	}
+	host.await(host.request("room_settings", map[string]any{
+		"turnTimeout":    t.cfg.turnTimeout,
+		"defenseTimeout": t.cfg.defenseLimit,
+	}), 30*time.Second)
+
+	for _, p := range t.players[1:] {
+		if err := p.connect(host.roomID()); err != nil {
+			log.Printf("第 %d 桌玩家連線失敗: %v", t.id, err)
+			continue
+		}
+		if p.waitWelcome(30 * time.Second) {
+			p.await(p.request("room_ready", map[string]any{"ready": true}), 30*time.Second)
+		}
+	}
+	host.request("start_game", map[string]any{"force": true})
+
+	for _, p := range t.players {
+		select {
+		case <-p.done:
+		case <-p.closed:
+		}
+	}
+	t.mu.Lock()
+	t.finished = host.gamesPlayed() >= t.cfg.games
+	t.mu.Unlock()
+}
+
+func (t *table) completed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finished
+}
+
+func (t *table) close() {
+	for _, p := range t.players {
+		p.shutdown()
+	}
+}
+
+// player 為一條壓測連線，依收到的提示以簡單的機器人邏輯出牌
+type player struct {
+	table *table
+	host  bool
+	token string
+	rec   *recorder
+	rng   *rand.Rand
+
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]pendingRequest
+	nextID  int
+	seat    int
+	room    string
+	hand    []cardView
+	// states 為尚未確認被取代的公開狀態版本，差異可能以其中任一版為基準
+	states     map[int64]any
+	view       publicView
+	games      int
+	votedFor   int
+	startedFor int
+	leaving    bool
+
+	welcome     chan struct{}
+	welcomeOnce sync.Once
+	done        chan struct{}
+	doneOnce    sync.Once
+	closed      chan struct{}
+}
+
+type pendingRequest struct {
+	at      time.Time
+	replied chan struct{}
+}
+
+type cardView struct {
+	ID    int    `json:"id"`
+	Kind  int    `json:"kind"`
+	Suit  string `json:"suit"`
+	Value int    `json:"value"`
+}
+
+// publicView 為公開狀態中壓測邏輯需要的欄位
+type publicView struct {
+	Status     string `json:"status"`
+	PublicGame *struct {
+		Snapshot struct {
+			Players []struct {
+				ID    int  `json:"id"`
+				Alive bool `json:"alive"`
+			} `json:"players"`
+		} `json:"snapshot"`
+	} `json:"publicGame"`
+	PostGame *struct {
+		QuorumReached bool `json:"quorumReached"`
+	} `json:"postGame"`
+}
+
+type inbound struct {
+	Type    string          `json:"type"`
+	Version int64           `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func (p *player) connect(roomID string) error {
+	u, err := url.Parse(p.table.cfg.server)
+	if err != nil {
+		return err
+	}
+	u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+	u.Path = "/ws"
+	query := url.Values{"auth": {p.token}, "protocol": {strconv.Itoa(protocolVersion)}}
+	if roomID != "" {
+		query.Set("room", roomID)
+	}
+	u.RawQuery = query.Encode()
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		p.rec.connectFailed()
+		close(p.closed)
+		return err
+	}
+	p.conn = conn
+	p.rec.connected()
+	go p.readLoop()
+	return nil
+}
+
+func (p *player) waitWelcome(timeout time.Duration) bool {
+	select {
+	case <-p.welcome:
+		return true
+	case <-p.closed:
+		return false
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (p *player) roomID() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.room
+}
+
+func (p *player) gamesPlayed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.games
+}
+
+// shutdown 主動關閉連線，之後的讀取錯誤不計為掉線
+func (p *player) shutdown() {
+	if p.conn == nil {
+		return
+	}
+	p.mu.Lock()
+	p.leaving = true
+	p.mu.Unlock()
+	p.writeMu.Lock()
+	_ = p.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+	p.writeMu.Unlock()
+	_ = p.conn.Close()
+	<-p.closed
+}
+
+// request 以請求 ID 送出指令，收到 ack 或 nack 時計入延遲；回傳的通道於收到回覆時關閉
+func (p *player) request(msgType string, payload any) <-chan struct{} {
+	replied := make(chan struct{})
+	data, err := json.Marshal(payload)
+	if err != nil {
+		close(replied)
+		return replied
+	}
+	p.mu.Lock()
+	p.nextID++
+	id := strconv.Itoa(p.nextID)
+	p.pending[id] = pendingRequest{at: time.Now(), replied: replied}
+	p.mu.Unlock()
+	p.write(map[string]any{"type": msgType, "payload": json.RawMessage(data), "requestId": id})
+	return replied
+}
+
+// await 等待指令回覆，連線中斷或逾時回傳 false
+func (p *player) await(replied <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-replied:
+		return true
+	case <-p.closed:
+		return false
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (p *player) write(msg any) {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_ = p.conn.WriteJSON(msg)
+}
+
+func (p *player) readLoop() {
+	defer close(p.closed)
+	for {
+		_, data, err := p.conn.ReadMessage()
+		if err != nil {
+			p.mu.Lock()
+			unexpected := !p.leaving
+			p.mu.Unlock()
+			if unexpected {
+				p.rec.dropped()
+			}
+			return
+		}
+		p.rec.received(len(data))
+		var msg inbound
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		p.handle(msg)
+	}
+}
+
+func (p *player) handle(msg inbound) {
+	switch msg.Type {
+	case "ack", "nack":
+		var reply struct {
+			RequestID string `json:"requestId"`
+			Code      string `json:"code"`
+		}
+		if json.Unmarshal(msg.Payload, &reply) != nil {
+			return
+		}
+		p.mu.Lock()
+		sent, ok := p.pending[reply.RequestID]
+		delete(p.pending, reply.RequestID)
+		p.mu.Unlock()
+		if ok {
+			p.rec.replied(time.Since(sent.at), reply.Code)
+			close(sent.replied)
+		}
+	case "welcome":
+		var welcome struct {
+			RoomID    string `json:"roomId"`
+			SeatIndex int    `json:"seatIndex"`
+		}
+		if json.Unmarshal(msg.Payload, &welcome) != nil {
+			return
+		}
+		p.mu.Lock()
+		p.room = welcome.RoomID
+		p.seat = welcome.SeatIndex
+		p.mu.Unlock()
+		p.welcomeOnce.Do(func() { close(p.welcome) })
+	case "public_state":
+		var doc any
+		if json.Unmarshal(msg.Payload, &doc) != nil {
+			return
+		}
+		p.updatePublic(doc, msg.Version)
+	case "state_patch":
+		p.applyPatch(msg)
+	case "resync":
+		var resync struct {
+			Public  json.RawMessage `json:"public"`
+			Version int64           `json:"version"`
+			Private *struct {
+				Snapshot struct {
+					Hand []cardView `json:"hand"`
+				} `json:"snapshot"`
+			} `json:"private"`
+		}
+		var doc any
+		if json.Unmarshal(msg.Payload, &resync) != nil || json.Unmarshal(resync.Public, &doc) != nil {
+			return
+		}
+		if resync.Private != nil {
+			p.mu.Lock()
+			p.hand = resync.Private.Snapshot.Hand
+			p.mu.Unlock()
+		}
+		p.updatePublic(doc, resync.Version)
+	case "private_state":
+		var private struct {
+			Snapshot struct {
+				Hand []cardView `json:"hand"`
+			} `json:"snapshot"`
+		}
+		if json.Unmarshal(msg.Payload, &private) != nil {
+			return
+		}
+		p.mu.Lock()
+		p.hand = private.Snapshot.Hand
+		p.mu.Unlock()
+	case "turn_start":
+		var turn struct {
+			PlayerID int `json:"playerId"`
+		}
+		if json.Unmarshal(msg.Payload, &turn) == nil && turn.PlayerID == p.currentSeat() {
+			p.playTurn()
+		}
+	case "defense_prompt":
+		var prompt struct {
+			MaxSelectable int        `json:"maxSelectable"`
+			Options       []cardView `json:"options"`
+		}
+		if json.Unmarshal(msg.Payload, &prompt) == nil {
+			p.defend(prompt.Options, prompt.MaxSelectable)
+		}
+	}
+}
+
+func (p *player) currentSeat() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.seat
+}
+
+// applyPatch 套用以已確認版本為基準的差異；缺少基準時要求完整狀態。
+// 伺服器之後只會以不低於此基準的版本計算差異，較舊的版本可以丟棄
+func (p *player) applyPatch(msg inbound) {
+	var patch struct {
+		Target string                `json:"target"`
+		Base   int64                 `json:"base"`
+		Ops    []jsonpatch.Operation `json:"ops"`
+	}
+	if json.Unmarshal(msg.Payload, &patch) != nil || patch.Target != "public_state" {
+		return
+	}
+	p.mu.Lock()
+	base, ok := p.states[patch.Base]
+	for version := range p.states {
+		if version < patch.Base {
+			delete(p.states, version)
+		}
+	}
+	p.mu.Unlock()
+	if !ok {
+		p.rec.resynced()
+		p.write(map[string]any{"type": "resync"})
+		return
+	}
+	doc, err := jsonpatch.Apply(base, patch.Ops)
+	if err != nil {
+		p.rec.resynced()
+		p.write(map[string]any{"type": "resync"})
+		return
+	}
+	p.updatePublic(doc, msg.Version)
+}
+
+// updatePublic 保存新的公開狀態並確認版本，再依房間狀態推進流程
+func (p *player) updatePublic(doc any, version int64) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	var view publicView
+	if json.Unmarshal(data, &view) != nil {
+		return
+	}
+	p.mu.Lock()
+	previous := p.view.Status
+	p.view = view
+	if version > 0 {
+		p.states[version] = doc
+	}
+	if previous == "running" && view.Status == "finished" {
+		p.games++
+	}
+	games := p.games
+	rematch := view.Status == "finished" && games < p.table.cfg.games
+	vote := rematch && p.votedFor < games
+	if vote {
+		p.votedFor = games
+	}
+	start := rematch && p.host && p.startedFor < games && view.PostGame != nil && view.PostGame.QuorumReached
+	if start {
+		p.startedFor = games
+	}
+	p.mu.Unlock()
+
+	if version > 0 {
+		p.write(map[string]any{"type": "state_ack", "payload": map[string]any{"target": "public_state", "version": version}})
+	}
+	if view.Status == "finished" && games >= p.table.cfg.games {
+		p.doneOnce.Do(func() { close(p.done) })
+	}
+	if vote {
+		p.request("rematch_vote", map[string]any{"vote": "rematch"})
+	}
+	if start {
+		p.request("rematch_start", struct{}{})
+	}
+}
+
+// playTurn 以同花色最大的至多五張數字牌挑戰隨機一位存活玩家；無數字牌時交由回合逾時代打
+func (p *player) playTurn() {
+	p.mu.Lock()
+	seat := p.seat
+	hand := append([]cardView(nil), p.hand...)
+	targets := make([]int, 0)
+	if p.view.PublicGame != nil {
+		for _, other := range p.view.PublicGame.Snapshot.Players {
+			if other.Alive && other.ID != seat {
+				targets = append(targets, other.ID)
+			}
+		}
+	}
+	target := -1
+	if len(targets) > 0 {
+		target = targets[p.rng.Intn(len(targets))]
+	}
+	p.mu.Unlock()
+
+	bySuit := make(map[string][]cardView)
+	for _, card := range hand {
+		if card.Kind == 0 {
+			bySuit[card.Suit] = append(bySuit[card.Suit], card)
+		}
+	}
+	var best []cardView
+	bestScore := -1
+	for _, cards := range bySuit {
+		sort.Slice(cards, func(i, j int) bool { return cards[i].Value > cards[j].Value })
+		cards = cards[:min(len(cards), 5)]
+		score := 0
+		for _, card := range cards {
+			score += card.Value
+		}
+		if score > bestScore {
+			best, bestScore = cards, score
+		}
+	}
+	if target < 0 || len(best) == 0 {
+		return
+	}
+	ids := make([]int, len(best))
+	for i, card := range best {
+		ids[i] = card.ID
+	}
+	p.request("action_challenge", map[string]any{"targetId": target, "cardIds": ids})
+}
+
+// defend 以最大的數字牌防守，張數不超過上限
+func (p *player) defend(options []cardView, limit int) {
+	sort.Slice(options, func(i, j int) bool { return options[i].Value > options[j].Value })
+	ids := make([]int, 0, limit)
+	for _, card := range options[:min(len(options), limit)] {
+		ids = append(ids, card.ID)
+	}
+	p.request("action_defense", map[string]any{"cardIds": ids})
+}
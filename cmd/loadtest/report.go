@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// recorder 彙整所有連線的延遲與事件計數
+type recorder struct {
+	mu          sync.Mutex
+	latencies   []time.Duration
+	nacks       map[string]int
+	connections int
+	failed      int
+	drops       int
+	resyncs     int
+	messages    int
+	bytes       int
+}
+
+func newRecorder() *recorder {
+	return &recorder{nacks: make(map[string]int)}
+}
+
+func (r *recorder) connected() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.connections++
+}
+
+func (r *recorder) connectFailed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed++
+}
+
+func (r *recorder) dropped() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drops++
+}
+
+func (r *recorder) resynced() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resyncs++
+}
+
+func (r *recorder) received(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages++
+	r.bytes += size
+}
+
+// replied 記錄指令自送出至收到 ack／nack 的時間；code 非空表示 nack
+func (r *recorder) replied(latency time.Duration, code string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if code != "" {
+		r.nacks[code]++
+	}
+}
+
+type serverUsage struct {
+	before, after serverStats
+}
+
+type report struct {
+	elapsed     time.Duration
+	tables      int
+	completed   int
+	connections int
+	failed      int
+	drops       int
+	resyncs     int
+	messages    int
+	bytes       int
+	latencies   []time.Duration
+	nacks       map[string]int
+	server      *serverUsage
+}
+
+func buildReport(tables []*table, rec *recorder, elapsed time.Duration) *report {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rep := &report{
+		elapsed:     elapsed,
+		tables:      len(tables),
+		connections: rec.connections,
+		failed:      rec.failed,
+		drops:       rec.drops,
+		resyncs:     rec.resyncs,
+		messages:    rec.messages,
+		bytes:       rec.bytes,
+		latencies:   append([]time.Duration(nil), rec.latencies...),
+		nacks:       rec.nacks,
+	}
+	for _, t := range tables {
+		if t.completed() {
+			rep.completed++
+		}
+	}
+	sort.Slice(rep.latencies, func(i, j int) bool { return rep.latencies[i] < rep.latencies[j] })
+	return rep
+}
+
+// percentile 以最近排名法取分位數，latencies 需已排序
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	rank := int(float64(len(latencies))*p+0.999999) - 1
+	return latencies[max(0, min(rank, len(latencies)-1))]
+}
+
+func (rep *report) print(w io.Writer) {
+	fmt.Fprintf(w, "耗時：%s\n", rep.elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "桌次：%d 桌，完成 %d 桌\n", rep.tables, rep.completed)
+	fmt.Fprintf(w, "連線：成功 %d 條，失敗 %d 條，中途斷線 %d 條，重新同步 %d 次\n", rep.connections, rep.failed, rep.drops, rep.resyncs)
+	fmt.Fprintf(w, "收到訊息：%d 則，共 %.1f MiB\n", rep.messages, float64(rep.bytes)/(1<<20))
+	if len(rep.latencies) > 0 {
+		fmt.Fprintf(w, "指令延遲（%d 筆）：p50 %s，p90 %s，p99 %s，最大 %s\n",
+			len(rep.latencies),
+			percentile(rep.latencies, 0.50).Round(time.Microsecond),
+			percentile(rep.latencies, 0.90).Round(time.Microsecond),
+			percentile(rep.latencies, 0.99).Round(time.Microsecond),
+			rep.latencies[len(rep.latencies)-1].Round(time.Microsecond))
+	}
+	if len(rep.nacks) > 0 {
+		codes := make([]string, 0, len(rep.nacks))
+		for code := range rep.nacks {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		fmt.Fprint(w, "被拒絕的指令：")
+		for i, code := range codes {
+			if i > 0 {
+				fmt.Fprint(w, "，")
+			}
+			fmt.Fprintf(w, "%s %d", code, rep.nacks[code])
+		}
+		fmt.Fprintln(w)
+	}
+	if rep.server == nil {
+		fmt.Fprintln(w, "伺服器：未取得負載統計（啟動伺服器時加上 --stats）")
+		return
+	}
+	before, after := rep.server.before, rep.server.after
+	cpu := after.CPUSeconds - before.CPUSeconds
+	wall := after.UptimeSeconds - before.UptimeSeconds
+	fmt.Fprintf(w, "伺服器送出佇列：合併 %d 則狀態訊息，超過上限 %d 次，因持續壅塞關閉 %d 條連線\n",
+		after.Coalesced-before.Coalesced, after.OverflowEvents-before.OverflowEvents, after.DroppedClients-before.DroppedClients)
+	if wall > 0 {
+		fmt.Fprintf(w, "伺服器 CPU：%.1f 秒，平均 %.2f 核\n", cpu, cpu/wall)
+	}
+	fmt.Fprintf(w, "伺服器記憶體（結束前）：heap %.1f MiB，sys %.1f MiB，goroutine %d，房間 %d，進行中對局 %d\n",
+		float64(after.HeapBytes)/(1<<20), float64(after.SysBytes)/(1<<20), after.Goroutines, after.Rooms, after.RunningGames)
+}
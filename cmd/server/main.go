@@ -2,16 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
-	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
+	"runtime"
+	"runtime/metrics"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 
+	"zombierush/internal/i18n"
 	"zombierush/internal/server"
 	serverstore "zombierush/internal/server/store"
 )
@@ -38,10 +41,22 @@ type profileResponse struct {
 	Username string `json:"username"`
 }
 
+// statsResponse 為 /debug/stats 的內容：Hub 負載與行程的 CPU、記憶體用量
+type statsResponse struct {
+	server.Stats
+	Goroutines    int     `json:"goroutines"`
+	HeapBytes     uint64  `json:"heapBytes"`
+	SysBytes      uint64  `json:"sysBytes"`
+	CPUSeconds    float64 `json:"cpuSeconds"`
+	UptimeSeconds float64 `json:"uptimeSeconds"`
+}
+
 func main() {
 	addr := flag.String("addr", ":8080", "HTTP 服務監聽位址")
 	webDir := flag.String("web", "web", "前端靜態資源目錄")
 	dataDir := flag.String("data", "data", "資料存放目錄")
+	matchWait := flag.Duration("match-wait", 30*time.Second, "快速配對等待多久後以機器人補滿")
+	enableStats := flag.Bool("stats", false, "開放 /debug/stats 負載統計（供 cmd/loadtest 使用）")
 	flag.Parse()
 
 	dbPath := filepath.Join(*dataDir, "zombierush.db")
@@ -56,29 +71,27 @@ func main() {
 	}()
 
 	hub := server.NewHub()
+	hub.SetMatchmakingWait(*matchWait)
+	hub.SetArchive(store)
 
 	http.HandleFunc("/api/register", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			writeError(w, http.StatusMethodNotAllowed, "僅支援 POST")
+			writeError(w, r, http.StatusMethodNotAllowed, errorCodeMethodNotAllowed, i18n.M("error.method_not_allowed", "method", http.MethodPost))
 			return
 		}
 		var req authRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeError(w, http.StatusBadRequest, "請提供帳號與密碼")
+			writeError(w, r, http.StatusBadRequest, server.ErrorCodeBadPayload, i18n.M("error.credentials_required"))
 			return
 		}
 		user, err := store.CreateUser(req.Username, req.Password)
 		if err != nil {
-			status := http.StatusBadRequest
-			if strings.Contains(err.Error(), "已存在") {
-				status = http.StatusConflict
-			}
-			writeError(w, status, err.Error())
+			writeFailure(w, r, err)
 			return
 		}
 		token, err := store.CreateSession(user.ID, 0)
 		if err != nil {
-			writeError(w, http.StatusInternalServerError, "建立會話失敗")
+			writeError(w, r, http.StatusInternalServerError, errorCodeInternal, i18n.M("error.session_create_failed"))
 			return
 		}
 		writeJSON(w, http.StatusOK, authResponse{Token: token, Username: user.Username})
@@ -86,22 +99,22 @@ func main() {
 
 	http.HandleFunc("/api/login", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			writeError(w, http.StatusMethodNotAllowed, "僅支援 POST")
+			writeError(w, r, http.StatusMethodNotAllowed, errorCodeMethodNotAllowed, i18n.M("error.method_not_allowed", "method", http.MethodPost))
 			return
 		}
 		var req authRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeError(w, http.StatusBadRequest, "請提供帳號與密碼")
+			writeError(w, r, http.StatusBadRequest, server.ErrorCodeBadPayload, i18n.M("error.credentials_required"))
 			return
 		}
 		user, err := store.Authenticate(req.Username, req.Password)
 		if err != nil {
-			writeError(w, http.StatusUnauthorized, err.Error())
+			writeFailure(w, r, err)
 			return
 		}
 		token, err := store.CreateSession(user.ID, 0)
 		if err != nil {
-			writeError(w, http.StatusInternalServerError, "建立會話失敗")
+			writeError(w, r, http.StatusInternalServerError, errorCodeInternal, i18n.M("error.session_create_failed"))
 			return
 		}
 		writeJSON(w, http.StatusOK, authResponse{Token: token, Username: user.Username})
@@ -109,39 +122,70 @@ func main() {
 
 	http.HandleFunc("/api/profile", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			writeError(w, http.StatusMethodNotAllowed, "僅支援 GET")
+			writeError(w, r, http.StatusMethodNotAllowed, errorCodeMethodNotAllowed, i18n.M("error.method_not_allowed", "method", http.MethodGet))
 			return
 		}
 		token := parseAuthHeader(r)
 		if token == "" {
-			writeError(w, http.StatusUnauthorized, "缺少會話資訊")
+			writeFailure(w, r, serverstore.ErrSessionRequired)
 			return
 		}
 		user, err := store.GetUserBySession(token)
 		if err != nil {
-			writeError(w, http.StatusUnauthorized, err.Error())
+			writeFailure(w, r, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, profileResponse{Username: user.Username})
 	})
 
+	http.HandleFunc("/api/inbox", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, errorCodeMethodNotAllowed, i18n.M("error.method_not_allowed", "method", http.MethodGet))
+			return
+		}
+		token := parseAuthHeader(r)
+		if token == "" {
+			writeFailure(w, r, serverstore.ErrSessionRequired)
+			return
+		}
+		user, err := store.GetUserBySession(token)
+		if err != nil {
+			writeFailure(w, r, err)
+			return
+		}
+		items, err := hub.Inbox(user.ID)
+		if err != nil {
+			writeFailure(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, server.InboxPayload{Items: items})
+	})
+
+	if *enableStats {
+		started := time.Now()
+		http.HandleFunc("/debug/stats", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, collectStats(hub, started))
+		})
+	}
+
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		authToken := strings.TrimSpace(r.URL.Query().Get("auth"))
 		if authToken == "" {
 			authToken = parseAuthHeader(r)
 		}
 		if authToken == "" {
-			http.Error(w, "未登入", http.StatusUnauthorized)
+			writeFailure(w, r, serverstore.ErrSessionRequired)
 			return
 		}
 
 		user, err := store.GetUserBySession(authToken)
 		if err != nil {
-			http.Error(w, "會話無效", http.StatusUnauthorized)
+			writeFailure(w, r, err)
 			return
 		}
 
 		roomID := strings.TrimSpace(r.URL.Query().Get("room"))
+		inviteCode := strings.TrimSpace(r.URL.Query().Get("invite"))
 		displayName := strings.TrimSpace(r.URL.Query().Get("name"))
 		if displayName == "" {
 			displayName = user.Username
@@ -150,8 +194,10 @@ func main() {
 			displayName = displayName[:24]
 		}
 		seatToken := strings.TrimSpace(r.URL.Query().Get("token"))
-		if seatToken == "" {
-			seatToken = fmt.Sprintf("seat-%d", time.Now().UnixNano())
+		protocol, err := server.NegotiateProtocol(r.URL.Query().Get("protocol"))
+		if err != nil {
+			writeFailure(w, r, err)
+			return
 		}
 
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -161,10 +207,12 @@ func main() {
 		}
 
 		client := server.NewWebClient(conn, hub, user.ID, user.Username, displayName, seatToken)
-		hub.RegisterLobbyClient(client)
-		if roomID != "" {
-			if err := hub.JoinRoom(roomID, client); err != nil {
-				_ = conn.WriteJSON(server.ServerMessage{Type: "error", Payload: server.ErrorPayload{Message: err.Error()}})
+		client.SetLocale(requestLocale(r))
+		client.SetProtocol(protocol)
+		resumed := hub.Connect(client)
+		if !resumed && (roomID != "" || inviteCode != "") {
+			if err := hub.JoinRoom(roomID, client, server.JoinOptions{InviteCode: inviteCode}); err != nil {
+				client.SendError(err)
 			}
 		}
 
@@ -198,8 +246,44 @@ func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 	}
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
+// HTTP 專用的錯誤代碼；其餘代碼沿用儲存層與協定層
+const (
+	errorCodeMethodNotAllowed = "method_not_allowed"
+	errorCodeInternal         = "internal"
+)
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, code string, message i18n.Message) {
+	writeJSON(w, status, map[string]string{"error": message.Localize(requestLocale(r)), "code": code})
+}
+
+// requestLocale 依 lang 參數或 Accept-Language 標頭決定回應語系
+func requestLocale(r *http.Request) i18n.Locale {
+	if lang := strings.TrimSpace(r.URL.Query().Get("lang")); lang != "" {
+		return i18n.Parse(lang)
+	}
+	return i18n.Parse(r.Header.Get("Accept-Language"))
+}
+
+// writeFailure 依錯誤代碼決定 HTTP 狀態碼；未帶代碼的錯誤視為伺服器內部錯誤
+func writeFailure(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, serverstore.ErrUserExists):
+		status = http.StatusConflict
+	case errors.Is(err, serverstore.ErrBadCredentials),
+		errors.Is(err, serverstore.ErrSessionRequired),
+		errors.Is(err, serverstore.ErrSessionExpired):
+		status = http.StatusUnauthorized
+	case errors.Is(err, serverstore.ErrUsernameRequired),
+		errors.Is(err, serverstore.ErrPasswordTooShort),
+		errors.Is(err, server.ErrUnsupportedProtocol):
+		status = http.StatusBadRequest
+	}
+	code := server.ErrorCode(err)
+	if status == http.StatusInternalServerError {
+		code = errorCodeInternal
+	}
+	writeJSON(w, status, map[string]string{"error": i18n.ErrorText(err, requestLocale(r)), "code": code})
 }
 
 func parseAuthHeader(r *http.Request) string {
@@ -216,3 +300,26 @@ func parseAuthHeader(r *http.Request) string {
 	}
 	return ""
 }
+
+// collectStats 彙整 Hub 與行程的負載；CPU 時間取自 runtime/metrics 的估計值（總量扣除閒置）
+func collectStats(hub *server.Hub, started time.Time) statsResponse {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	samples := []metrics.Sample{
+		{Name: "/cpu/classes/total:cpu-seconds"},
+		{Name: "/cpu/classes/idle:cpu-seconds"},
+	}
+	metrics.Read(samples)
+	cpu := 0.0
+	if samples[0].Value.Kind() == metrics.KindFloat64 && samples[1].Value.Kind() == metrics.KindFloat64 {
+		cpu = samples[0].Value.Float64() - samples[1].Value.Float64()
+	}
+	return statsResponse{
+		Stats:         hub.Stats(),
+		Goroutines:    runtime.NumGoroutine(),
+		HeapBytes:     mem.HeapAlloc,
+		SysBytes:      mem.Sys,
+		CPUSeconds:    cpu,
+		UptimeSeconds: time.Since(started).Seconds(),
+	}
+}
@@ -0,0 +1,47 @@
+// Package clock 抽象化伺服器的時間來源與排程；正式環境使用系統時鐘，測試可換成自行推進的 Fake
+package clock
+
+import "time"
+
+// Clock 提供目前時間、延遲執行與週期通知
+type Clock interface {
+	Now() time.Time
+	// AfterFunc 於 d 之後執行 f
+	AfterFunc(d time.Duration, f func()) Timer
+	// NewTicker 每隔 d 由 C 送出一次當時時間；接收端來不及讀取時略過該次
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer 為 AfterFunc 排定的工作，Stop 於尚未執行時取消並回傳 true
+type Timer interface {
+	Stop() bool
+}
+
+// Ticker 為週期通知
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real 回傳使用系統時間的時鐘
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
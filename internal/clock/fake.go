@@ -0,0 +1,168 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake 為測試用時鐘：時間只在呼叫 Advance 時前進，期間到期的工作依到期順序在呼叫端同步執行，
+// 因此 Advance 返回時機器人回合、逾時等排程都已處理完畢
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	seq     int
+	pending []*fakeEntry
+}
+
+type fakeEntry struct {
+	clock  *Fake
+	at     time.Time
+	seq    int
+	period time.Duration // 0 表示只執行一次
+	fire   func(now time.Time)
+	ch     chan time.Time
+}
+
+// NewFake 建立停在 start 的時鐘
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.schedule(d, 0, func(time.Time) { fn() })
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: NewTicker 的間隔必須為正數")
+	}
+	ch := make(chan time.Time, 1)
+	entry := f.schedule(d, d, func(now time.Time) {
+		select {
+		case ch <- now:
+		default:
+		}
+	})
+	entry.ch = ch
+	return fakeTicker{entry}
+}
+
+func (f *Fake) schedule(d, period time.Duration, fire func(time.Time)) *fakeEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	entry := &fakeEntry{clock: f, at: f.now.Add(d), seq: f.seq, period: period, fire: fire}
+	f.pending = append(f.pending, entry)
+	return entry
+}
+
+// Advance 將時間推進 d，途中到期的工作依序執行；工作中新排定且仍在範圍內的工作也會執行
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+	for f.fireNext(target) {
+	}
+	f.mu.Lock()
+	if target.After(f.now) {
+		f.now = target
+	}
+	f.mu.Unlock()
+}
+
+// Pending 回傳尚未執行的一次性工作數量，不含 Ticker
+func (f *Fake) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, entry := range f.pending {
+		if entry.period == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Next 回傳距離下一個一次性工作到期的時間；沒有待執行的工作時回傳 false
+func (f *Fake) Next() (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var next *fakeEntry
+	for _, entry := range f.pending {
+		if entry.period == 0 && (next == nil || entry.before(next)) {
+			next = entry
+		}
+	}
+	if next == nil {
+		return 0, false
+	}
+	return max(next.at.Sub(f.now), 0), true
+}
+
+// fireNext 執行不晚於 target 的最早一項工作，沒有可執行的工作時回傳 false
+func (f *Fake) fireNext(target time.Time) bool {
+	f.mu.Lock()
+	var next *fakeEntry
+	for _, entry := range f.pending {
+		if !entry.at.After(target) && (next == nil || entry.before(next)) {
+			next = entry
+		}
+	}
+	if next == nil {
+		f.mu.Unlock()
+		return false
+	}
+	if next.at.After(f.now) {
+		f.now = next.at
+	}
+	now := f.now
+	if next.period > 0 {
+		f.seq++
+		next.at = next.at.Add(next.period)
+		next.seq = f.seq
+	} else {
+		f.removeLocked(next)
+	}
+	f.mu.Unlock()
+
+	next.fire(now)
+	return true
+}
+
+func (f *Fake) removeLocked(target *fakeEntry) bool {
+	for i, entry := range f.pending {
+		if entry == target {
+			f.pending = append(f.pending[:i], f.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (e *fakeEntry) before(other *fakeEntry) bool {
+	if e.at.Equal(other.at) {
+		return e.seq < other.seq
+	}
+	return e.at.Before(other.at)
+}
+
+// Stop 取消尚未執行的工作；對 Ticker 而言為停止後續通知
+func (e *fakeEntry) Stop() bool {
+	e.clock.mu.Lock()
+	defer e.clock.mu.Unlock()
+	return e.clock.removeLocked(e)
+}
+
+type fakeTicker struct {
+	*fakeEntry
+}
+
+func (t fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t fakeTicker) Stop() { t.fakeEntry.Stop() }
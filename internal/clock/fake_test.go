@@ -0,0 +1,63 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeFiresInOrderDuringAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	var order []string
+	f.AfterFunc(2*time.Second, func() { order = append(order, "b") })
+	f.AfterFunc(time.Second, func() {
+		order = append(order, "a")
+		// 工作中排定且仍在推進範圍內的工作，同一次 Advance 會一併執行
+		f.AfterFunc(500*time.Millisecond, func() { order = append(order, "a2") })
+	})
+	stopped := f.AfterFunc(time.Second, func() { order = append(order, "stopped") })
+	if !stopped.Stop() {
+		t.Fatal("尚未執行的工作應可取消")
+	}
+
+	f.Advance(1500 * time.Millisecond)
+	if len(order) != 2 || order[0] != "a" || order[1] != "a2" {
+		t.Fatalf("推進 1.5 秒後的執行順序錯誤：%v", order)
+	}
+	if next, ok := f.Next(); !ok || next != 500*time.Millisecond {
+		t.Fatalf("下一項工作應在 500ms 後，實得 %v %v", next, ok)
+	}
+	f.Advance(time.Second)
+	if len(order) != 3 || order[2] != "b" {
+		t.Fatalf("推進後應執行 b：%v", order)
+	}
+	if f.Pending() != 0 {
+		t.Fatalf("不應有剩餘工作，實得 %d", f.Pending())
+	}
+	if want := start.Add(2500 * time.Millisecond); !f.Now().Equal(want) {
+		t.Fatalf("時間應為 %v，實得 %v", want, f.Now())
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	f := NewFake(time.Unix(0, 0))
+	ticker := f.NewTicker(time.Second)
+	f.Advance(3 * time.Second)
+	select {
+	case <-ticker.C():
+	default:
+		t.Fatal("推進後應收到通知")
+	}
+	select {
+	case <-ticker.C():
+		t.Fatal("未讀取的通知不應累積")
+	default:
+	}
+	ticker.Stop()
+	f.Advance(2 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("停止後不應再收到通知")
+	default:
+	}
+}
@@ -1,18 +1,25 @@
 package game
 
 import (
-	"fmt"
 	"sort"
+
+	"zombierush/internal/i18n"
 )
 
 // ChallengeOptions 用於描述一次挑戰行動的輸入
 type ChallengeOptions struct {
 	AttackerID    int
 	DefenderID    int
-	AttackerCards []int // 進攻方選擇的手牌索引
-	DefenderCards []int // 防守方選擇的手牌索引；若無可出牌可為空
+	AttackerCards []int // 進攻方選擇的牌編號（Card.ID）
+	DefenderCards []int // 防守方選擇的牌編號；若無可出牌可為空
 }
 
+// 感染相關的紀錄會暴露身分，伺服器只私下告知攻守雙方
+const (
+	NoteInfection   = "game.zombie_infection"
+	NoteZombieGrant = "game.zombie_granted"
+)
+
 // ChallengeOutcome 描述挑戰結果
 type ChallengeOutcome struct {
 	AttackerID int
@@ -31,7 +38,7 @@ type ChallengeOutcome struct {
 	ConvertedToHuman []int
 	StolenCard       *Card
 
-	Notes []string
+	Notes []i18n.Message
 }
 
 type playedSet struct {
@@ -44,22 +51,22 @@ type playedSet struct {
 
 func (g *Game) playerByID(id int) (*Player, error) {
 	if id < 0 || id >= len(g.Players) {
-		return nil, fmt.Errorf("找不到編號為 %d 的玩家", id)
+		return nil, ErrPlayerNotFound.WithDetail("error.player_not_found.id", "id", id)
 	}
 	player := g.Players[id]
 	if !player.Alive {
-		return nil, fmt.Errorf("玩家 %s 已被淘汰", player.Name)
+		return nil, ErrPlayerEliminated.WithDetail("error.player_eliminated.named", "player", player.Name)
 	}
 	return player, nil
 }
 
-func (g *Game) eliminatePlayer(p *Player, reason string, outcomeNotes *[]string, eliminated *[]int) {
+func (g *Game) eliminatePlayer(p *Player, reason i18n.Message, outcomeNotes *[]i18n.Message, eliminated *[]int) {
 	if !p.Alive {
 		return
 	}
 	p.Alive = false
 	*eliminated = append(*eliminated, p.ID)
-	note := fmt.Sprintf("玩家 %s 被淘汰（%s）", p.Name, reason)
+	note := i18n.M("game.eliminated", "player", p.Name, "reason", reason)
 	g.addLog(note)
 	if outcomeNotes != nil {
 		*outcomeNotes = append(*outcomeNotes, note)
@@ -69,7 +76,7 @@ func (g *Game) eliminatePlayer(p *Player, reason string, outcomeNotes *[]string,
 // Challenge 解析並結算一次挑戰
 func (g *Game) Challenge(opts ChallengeOptions) (*ChallengeOutcome, error) {
 	if opts.AttackerID == opts.DefenderID {
-		return nil, fmt.Errorf("挑戰目標不可為自己")
+		return nil, ErrSelfChallenge
 	}
 
 	attacker, err := g.playerByID(opts.AttackerID)
@@ -82,12 +89,12 @@ func (g *Game) Challenge(opts ChallengeOptions) (*ChallengeOutcome, error) {
 	}
 
 	if len(opts.AttackerCards) == 0 {
-		return nil, fmt.Errorf("攻擊者必須選擇要出的牌")
+		return nil, ErrNoCardsSelected.WithDetail("error.no_cards_selected.attacker")
 	}
 
 	outcome := &ChallengeOutcome{AttackerID: opts.AttackerID, DefenderID: opts.DefenderID}
 
-	attackerCards, err := removeCardsByIndices(attacker, opts.AttackerCards)
+	attackerCards, err := attacker.RemoveCardsByID(opts.AttackerCards)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +108,7 @@ func (g *Game) Challenge(opts ChallengeOptions) (*ChallengeOutcome, error) {
 		return nil, err
 	}
 
-	defenderCards, err := removeCardsByIndices(defender, opts.DefenderCards)
+	defenderCards, err := defender.RemoveCardsByID(opts.DefenderCards)
 	if err != nil {
 		attacker.Hand = append(attacker.Hand, attackerCards...)
 		sortHand(attacker)
@@ -151,19 +158,19 @@ func (g *Game) Challenge(opts ChallengeOptions) (*ChallengeOutcome, error) {
 		lid := loser.ID
 		outcome.WinnerID = &gid
 		outcome.LoserID = &lid
-		g.addLog(fmt.Sprintf("挑戰結果：%s 勝，%s 負", winner.Name, loser.Name))
+		g.addLog(i18n.M("game.challenge_result", "winner", winner.Name, "loser", loser.Name))
 
 		// 勝者抽取一張對手的數字牌
 		if stolen := stealRandomNumericCard(g, winner, loser); stolen != nil {
 			outcome.StolenCard = stolen
-			note := fmt.Sprintf("%s 從 %s 抽走 %s", winner.Name, loser.Name, stolen.String())
+			note := i18n.M("game.card_stolen", "winner", winner.Name, "loser", loser.Name, "card", *stolen)
 			outcome.Notes = append(outcome.Notes, note)
 			g.addLog(note)
 		}
 
 		// 如果失敗者手牌耗盡則淘汰
 		if loser.HandSize() == 0 && loser.Alive {
-			g.eliminatePlayer(loser, "手牌耗盡", &outcome.Notes, &outcome.Eliminated)
+			g.eliminatePlayer(loser, i18n.M("game.reason.hand_empty"), &outcome.Notes, &outcome.Eliminated)
 		}
 	}
 
@@ -178,7 +185,7 @@ func (g *Game) resolveChallenge(attacker, defender *Player, attackSet, defenseSe
 			// 疫苗反制
 			convertToHuman(g, attacker, outcome)
 			declareWinner(defender, attacker, outcome)
-			note := fmt.Sprintf("%s 使用疫苗反制，%s 被強制轉為人類", defender.Name, attacker.Name)
+			note := i18n.M("game.vaccine_counter", "defender", defender.Name, "attacker", attacker.Name)
 			outcome.Notes = append(outcome.Notes, note)
 			g.addLog(note)
 			return challengeResult{winner: defender, loser: attacker}
@@ -188,14 +195,14 @@ func (g *Game) resolveChallenge(attacker, defender *Player, attackSet, defenseSe
 		declareWinner(attacker, defender, outcome)
 		outcome.Infection = true
 		defender.SetIdentity(IdentityZombie)
-		infectionNote := fmt.Sprintf("僵屍牌出擊！%s 將 %s 感染為僵屍", attacker.Name, defender.Name)
+		infectionNote := i18n.M(NoteInfection, "attacker", attacker.Name, "defender", defender.Name)
 		outcome.Notes = append(outcome.Notes, infectionNote)
 		g.addLog(infectionNote)
 
 		if defender.CountKind(CardKindZombie) == 0 {
-			defender.AddCard(Card{Kind: CardKindZombie})
+			defender.AddCard(g.issueCard(Card{Kind: CardKindZombie}))
 			sortHand(defender)
-			grantNote := fmt.Sprintf("%s 成為僵屍並獲得一張僵屍牌", defender.Name)
+			grantNote := i18n.M(NoteZombieGrant, "player", defender.Name)
 			outcome.Notes = append(outcome.Notes, grantNote)
 			g.addLog(grantNote)
 		}
@@ -206,17 +213,17 @@ func (g *Game) resolveChallenge(attacker, defender *Player, attackSet, defenseSe
 	if attackSet.kind == CardKindShotgun {
 		if !defender.IsZombie() {
 			// 對人類無效，攻擊失敗
-			failNote := fmt.Sprintf("獵槍失效！%s 並非僵屍，%s 攻擊落空", defender.Name, attacker.Name)
+			failNote := i18n.M("game.shotgun_miss", "defender", defender.Name, "attacker", attacker.Name)
 			outcome.Notes = append(outcome.Notes, failNote)
 			g.addLog(failNote)
 			declareWinner(defender, attacker, outcome)
 			return challengeResult{winner: defender, loser: attacker}
 		}
 		// 命中僵屍，直接淘汰
-		hitNote := fmt.Sprintf("獵槍命中！%s 射殺僵屍 %s", attacker.Name, defender.Name)
+		hitNote := i18n.M("game.shotgun_hit", "attacker", attacker.Name, "defender", defender.Name)
 		outcome.Notes = append(outcome.Notes, hitNote)
 		g.addLog(hitNote)
-		g.eliminatePlayer(defender, "遭獵槍射擊", &outcome.Notes, &outcome.Eliminated)
+		g.eliminatePlayer(defender, i18n.M("game.reason.shot"), &outcome.Notes, &outcome.Eliminated)
 		declareWinner(attacker, defender, outcome)
 		return challengeResult{winner: attacker, loser: defender}
 	}
@@ -224,7 +231,7 @@ func (g *Game) resolveChallenge(attacker, defender *Player, attackSet, defenseSe
 	// 攻方為數字牌，處理防守
 	if defenseSet.kind == CardKindVaccine {
 		convertToHuman(g, attacker, outcome)
-		note := fmt.Sprintf("%s 使用疫苗逆轉，%s 被迫轉回人類", defender.Name, attacker.Name)
+		note := i18n.M("game.vaccine_reversal", "defender", defender.Name, "attacker", attacker.Name)
 		outcome.Notes = append(outcome.Notes, note)
 		g.addLog(note)
 		declareWinner(defender, attacker, outcome)
@@ -234,7 +241,7 @@ func (g *Game) resolveChallenge(attacker, defender *Player, attackSet, defenseSe
 	// 防守方無牌可出 -> 強制揭露
 	if len(defenseSet.cards) == 0 {
 		outcome.ForcedReveal = true
-		note := fmt.Sprintf("防守方 %s 無同花色牌可出", defender.Name)
+		note := i18n.M("game.no_matching_suit", "defender", defender.Name)
 		outcome.Notes = append(outcome.Notes, note)
 		g.addLog(note)
 		declareWinner(attacker, defender, outcome)
@@ -242,28 +249,28 @@ func (g *Game) resolveChallenge(attacker, defender *Player, attackSet, defenseSe
 	}
 
 	if !defenseSet.isNumeric {
-		return challengeResult{err: fmt.Errorf("防守方的出牌無效")}
+		return challengeResult{err: ErrInvalidDefense}
 	}
 
 	if attackSet.suit != defenseSet.suit {
-		return challengeResult{err: fmt.Errorf("防守方必須出相同花色")}
+		return challengeResult{err: ErrInvalidSuit.WithDetail("error.invalid_suit.defense")}
 	}
 
 	switch {
 	case attackSet.total > defenseSet.total:
-		note := fmt.Sprintf("%s 以 %d 點擊敗 %s 的 %d 點", attacker.Name, attackSet.total, defender.Name, defenseSet.total)
+		note := i18n.M("game.attack_wins", "attacker", attacker.Name, "attackTotal", attackSet.total, "defender", defender.Name, "defenseTotal", defenseSet.total)
 		outcome.Notes = append(outcome.Notes, note)
 		g.addLog(note)
 		declareWinner(attacker, defender, outcome)
 		return challengeResult{winner: attacker, loser: defender}
 	case defenseSet.total > attackSet.total:
-		note := fmt.Sprintf("%s 以 %d 點守下 %s 的 %d 點", defender.Name, defenseSet.total, attacker.Name, attackSet.total)
+		note := i18n.M("game.defense_holds", "defender", defender.Name, "defenseTotal", defenseSet.total, "attacker", attacker.Name, "attackTotal", attackSet.total)
 		outcome.Notes = append(outcome.Notes, note)
 		g.addLog(note)
 		declareWinner(defender, attacker, outcome)
 		return challengeResult{winner: defender, loser: attacker}
 	default:
-		note := fmt.Sprintf("雙方 %s 花色打成 %d 點平手", attackSet.suit, attackSet.total)
+		note := i18n.M("game.tie", "suit", attackSet.suit, "total", attackSet.total)
 		outcome.Notes = append(outcome.Notes, note)
 		g.addLog(note)
 		// 平手：兩邊收回各自牌
@@ -316,31 +323,24 @@ func sortHand(p *Player) {
 	})
 }
 
-func removeCardsByIndices(p *Player, indices []int) ([]Card, error) {
-	if len(indices) == 0 {
-		return nil, nil
-	}
-	return p.RemoveCards(indices)
-}
-
 func analyzePlayedSet(cards []Card, attacker bool) (playedSet, error) {
 	if len(cards) == 0 {
-		return playedSet{}, fmt.Errorf("必須選擇至少一張牌")
+		return playedSet{}, ErrNoCardsSelected
 	}
 
 	first := cards[0]
 	if first.Kind == CardKindNumber {
 		if len(cards) > 5 {
-			return playedSet{}, fmt.Errorf("一次最多只能打出五張數字牌")
+			return playedSet{}, ErrTooManyCards
 		}
 		suit := first.Suit
 		total := 0
 		for _, c := range cards {
 			if c.Kind != CardKindNumber {
-				return playedSet{}, fmt.Errorf("數字牌不可與特殊牌混出")
+				return playedSet{}, ErrMixedCards
 			}
 			if c.Suit != suit {
-				return playedSet{}, fmt.Errorf("多張數字牌必須為同一花色")
+				return playedSet{}, ErrInvalidSuit
 			}
 			total += c.Value
 		}
@@ -348,19 +348,19 @@ func analyzePlayedSet(cards []Card, attacker bool) (playedSet, error) {
 	}
 
 	if len(cards) != 1 {
-		return playedSet{}, fmt.Errorf("特殊牌一次只能出一張")
+		return playedSet{}, ErrTooManyCards.WithDetail("error.too_many_cards.special")
 	}
 
 	if !attacker && first.Kind == CardKindShotgun {
-		return playedSet{}, fmt.Errorf("防守方不可使用獵槍")
+		return playedSet{}, ErrShotgunNotAllowed
 	}
 
 	if !attacker && first.Kind == CardKindZombie {
-		return playedSet{}, fmt.Errorf("防守方不可使用僵屍牌")
+		return playedSet{}, ErrZombieCardNotAllowed
 	}
 
 	if attacker && first.Kind == CardKindVaccine {
-		return playedSet{}, fmt.Errorf("攻擊方不可使用疫苗")
+		return playedSet{}, ErrVaccineNotAllowed
 	}
 
 	return playedSet{cards: cards, kind: first.Kind}, nil
@@ -0,0 +1,69 @@
+package game
+
+import "zombierush/internal/i18n"
+
+// Error 為帶有穩定代碼的規則錯誤：Code 供程式判斷，Msg 為訊息目錄中顯示給玩家的文字
+type Error struct {
+	Code string
+	Msg  i18n.Message
+}
+
+func newError(code string) *Error {
+	return &Error{Code: code, Msg: i18n.M("error." + code)}
+}
+
+func (e *Error) Error() string { return e.Msg.String() }
+
+// Localize 依語系呈現錯誤訊息
+func (e *Error) Localize(loc i18n.Locale) string { return e.Msg.Localize(loc) }
+
+// ErrorCode 回傳穩定的錯誤代碼，供協定層直接轉送
+func (e *Error) ErrorCode() string { return e.Code }
+
+// Is 讓附帶細節的錯誤仍能以 errors.Is 與同代碼的哨兵錯誤比對
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetail 以相同代碼產生改用另一則訊息的錯誤，供需要附帶細節的場合使用
+func (e *Error) WithDetail(id string, kv ...any) error {
+	return &Error{Code: e.Code, Msg: i18n.M(id, kv...)}
+}
+
+var (
+	// ErrInvalidPlayerCount 表示玩家人數不正確
+	ErrInvalidPlayerCount = newError("invalid_player_count")
+	// ErrUnknownVariant 表示指定的規則變體不存在
+	ErrUnknownVariant = newError("unknown_variant")
+	// ErrNotYourTurn 表示尚未輪到該玩家行動
+	ErrNotYourTurn = newError("not_your_turn")
+	// ErrPlayerNotFound 表示找不到指定的玩家
+	ErrPlayerNotFound = newError("player_not_found")
+	// ErrPlayerEliminated 表示玩家已被淘汰
+	ErrPlayerEliminated = newError("player_eliminated")
+	// ErrSelfChallenge 表示挑戰目標為自己
+	ErrSelfChallenge = newError("self_challenge")
+	// ErrNoCardsSelected 表示未選擇任何牌
+	ErrNoCardsSelected = newError("no_cards_selected")
+	// ErrCardNotInHand 表示指定的牌不在手牌中
+	ErrCardNotInHand = newError("card_not_in_hand")
+	// ErrDuplicateCard 表示同一張牌被重複指定
+	ErrDuplicateCard = newError("duplicate_card")
+	// ErrTooManyCards 表示一次出牌數量超過上限
+	ErrTooManyCards = newError("too_many_cards")
+	// ErrMixedCards 表示數字牌與特殊牌混出
+	ErrMixedCards = newError("mixed_cards")
+	// ErrInvalidSuit 表示花色不符合規則
+	ErrInvalidSuit = newError("invalid_suit")
+	// ErrInvalidDefense 表示防守方的出牌無效
+	ErrInvalidDefense = newError("invalid_defense")
+	// ErrShotgunNotAllowed 表示防守方打出獵槍
+	ErrShotgunNotAllowed = newError("shotgun_not_allowed")
+	// ErrZombieCardNotAllowed 表示防守方打出僵屍牌
+	ErrZombieCardNotAllowed = newError("zombie_card_not_allowed")
+	// ErrVaccineNotAllowed 表示攻擊方打出疫苗
+	ErrVaccineNotAllowed = newError("vaccine_not_allowed")
+	// ErrSavedGameCorrupt 表示保存的對局資料無法還原
+	ErrSavedGameCorrupt = newError("saved_game_corrupt")
+)
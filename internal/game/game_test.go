@@ -1,6 +1,10 @@
 package game
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
 
 func TestNewGameSetup(t *testing.T) {
 	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
@@ -63,14 +67,14 @@ func TestZombieCardGuaranteesInfection(t *testing.T) {
 	defender := g.Players[1]
 	attacker.SetIdentity(IdentityZombie)
 	defender.SetIdentity(IdentityHuman)
-	attacker.Hand = []Card{{Kind: CardKindZombie}}
-	defender.Hand = []Card{{Kind: CardKindNumber, Suit: SuitSpade, Value: 5}}
+	attacker.Hand = []Card{{ID: 1, Kind: CardKindZombie}}
+	defender.Hand = []Card{{ID: 2, Kind: CardKindNumber, Suit: SuitSpade, Value: 5}}
 
 	outcome, err := g.Challenge(ChallengeOptions{
 		AttackerID:    attacker.ID,
 		DefenderID:    defender.ID,
-		AttackerCards: []int{0},
-		DefenderCards: []int{0},
+		AttackerCards: []int{1},
+		DefenderCards: []int{2},
 	})
 	if err != nil {
 		t.Fatalf("挑戰錯誤: %v", err)
@@ -96,14 +100,14 @@ func TestVaccineCountersZombieCard(t *testing.T) {
 	defender := g.Players[1]
 	attacker.SetIdentity(IdentityZombie)
 	defender.SetIdentity(IdentityHuman)
-	attacker.Hand = []Card{{Kind: CardKindZombie}}
-	defender.Hand = []Card{{Kind: CardKindVaccine}}
+	attacker.Hand = []Card{{ID: 1, Kind: CardKindZombie}}
+	defender.Hand = []Card{{ID: 2, Kind: CardKindVaccine}}
 
 	outcome, err := g.Challenge(ChallengeOptions{
 		AttackerID:    attacker.ID,
 		DefenderID:    defender.ID,
-		AttackerCards: []int{0},
-		DefenderCards: []int{0},
+		AttackerCards: []int{1},
+		DefenderCards: []int{2},
 	})
 	if err != nil {
 		t.Fatalf("挑戰錯誤: %v", err)
@@ -122,14 +126,14 @@ func TestMultiCardComparison(t *testing.T) {
 
 	attacker := g.Players[0]
 	defender := g.Players[1]
-	attacker.Hand = []Card{{Kind: CardKindNumber, Suit: SuitSpade, Value: 9}, {Kind: CardKindNumber, Suit: SuitSpade, Value: 8}}
-	defender.Hand = []Card{{Kind: CardKindNumber, Suit: SuitSpade, Value: 5}, {Kind: CardKindNumber, Suit: SuitSpade, Value: 4}}
+	attacker.Hand = []Card{{ID: 1, Kind: CardKindNumber, Suit: SuitSpade, Value: 9}, {ID: 2, Kind: CardKindNumber, Suit: SuitSpade, Value: 8}}
+	defender.Hand = []Card{{ID: 3, Kind: CardKindNumber, Suit: SuitSpade, Value: 5}, {ID: 4, Kind: CardKindNumber, Suit: SuitSpade, Value: 4}}
 
 	outcome, err := g.Challenge(ChallengeOptions{
 		AttackerID:    attacker.ID,
 		DefenderID:    defender.ID,
-		AttackerCards: []int{0, 1},
-		DefenderCards: []int{0, 1},
+		AttackerCards: []int{1, 2},
+		DefenderCards: []int{3, 4},
 	})
 	if err != nil {
 		t.Fatalf("挑戰錯誤: %v", err)
@@ -139,6 +143,71 @@ func TestMultiCardComparison(t *testing.T) {
 	}
 }
 
+func TestChallengeRejectsCardsNotInHand(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	g, _ := NewGame(names, 5)
+	attacker := g.Players[0]
+	defender := g.Players[1]
+	stale := defender.Hand[0].ID
+	before := attacker.HandSize()
+
+	_, err := g.Challenge(ChallengeOptions{
+		AttackerID:    attacker.ID,
+		DefenderID:    defender.ID,
+		AttackerCards: []int{stale},
+	})
+	if !errors.Is(err, ErrCardNotInHand) {
+		t.Fatalf("不在手牌中的牌編號應以 %s 拒絕，卻得到 %v", ErrCardNotInHand.Code, err)
+	}
+	if attacker.HandSize() != before {
+		t.Fatalf("拒絕後攻擊者手牌不應變動")
+	}
+}
+
+func TestPlayedSetErrorCodes(t *testing.T) {
+	cases := []struct {
+		cards    []Card
+		attacker bool
+		want     *Error
+	}{
+		{nil, true, ErrNoCardsSelected},
+		{[]Card{{Kind: CardKindNumber, Suit: SuitHeart, Value: 3}, {Kind: CardKindNumber, Suit: SuitSpade, Value: 4}}, true, ErrInvalidSuit},
+		{[]Card{{Kind: CardKindZombie}}, false, ErrZombieCardNotAllowed},
+		{[]Card{{Kind: CardKindVaccine}}, true, ErrVaccineNotAllowed},
+		{[]Card{{Kind: CardKindShotgun}, {Kind: CardKindVaccine}}, true, ErrTooManyCards},
+	}
+	for _, tc := range cases {
+		_, err := analyzePlayedSet(tc.cards, tc.attacker)
+		var coded *Error
+		if !errors.As(err, &coded) || coded.Code != tc.want.Code {
+			t.Fatalf("出牌 %v 應得到代碼 %s，卻得到 %v", tc.cards, tc.want.Code, err)
+		}
+	}
+}
+
+func TestCardIDsAreUnique(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	g, _ := NewGame(names, 6)
+	seen := make(map[int]struct{})
+	check := func(c Card) {
+		if c.ID == 0 {
+			t.Fatalf("牌 %s 缺少編號", c)
+		}
+		if _, dup := seen[c.ID]; dup {
+			t.Fatalf("牌編號 %d 重複", c.ID)
+		}
+		seen[c.ID] = struct{}{}
+	}
+	for _, p := range g.Players {
+		for _, c := range p.Hand {
+			check(c)
+		}
+	}
+	for _, c := range g.cardDeck {
+		check(c)
+	}
+}
+
 func TestShotgunOnlyAffectsZombies(t *testing.T) {
 	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
 	g, _ := NewGame(names, 4)
@@ -149,16 +218,16 @@ func TestShotgunOnlyAffectsZombies(t *testing.T) {
 	human.SetIdentity(IdentityHuman)
 	zombie.SetIdentity(IdentityZombie)
 
-	attacker.Hand = []Card{{Kind: CardKindShotgun}, {Kind: CardKindShotgun}}
-	human.Hand = []Card{{Kind: CardKindNumber, Suit: SuitClub, Value: 3}}
-	zombie.Hand = []Card{{Kind: CardKindNumber, Suit: SuitClub, Value: 4}}
+	attacker.Hand = []Card{{ID: 1, Kind: CardKindShotgun}, {ID: 2, Kind: CardKindShotgun}}
+	human.Hand = []Card{{ID: 3, Kind: CardKindNumber, Suit: SuitClub, Value: 3}}
+	zombie.Hand = []Card{{ID: 4, Kind: CardKindNumber, Suit: SuitClub, Value: 4}}
 
 	// 對人類 -> 失敗
 	outcome1, err := g.Challenge(ChallengeOptions{
 		AttackerID:    attacker.ID,
 		DefenderID:    human.ID,
-		AttackerCards: []int{0},
-		DefenderCards: []int{0},
+		AttackerCards: []int{1},
+		DefenderCards: []int{3},
 	})
 	if err != nil {
 		t.Fatalf("挑戰錯誤: %v", err)
@@ -171,8 +240,8 @@ func TestShotgunOnlyAffectsZombies(t *testing.T) {
 	outcome2, err := g.Challenge(ChallengeOptions{
 		AttackerID:    attacker.ID,
 		DefenderID:    zombie.ID,
-		AttackerCards: []int{0},
-		DefenderCards: []int{0},
+		AttackerCards: []int{2},
+		DefenderCards: []int{4},
 	})
 	if err != nil {
 		t.Fatalf("挑戰錯誤: %v", err)
@@ -184,3 +253,84 @@ func TestShotgunOnlyAffectsZombies(t *testing.T) {
 		t.Fatalf("僵屍應被獵槍淘汰")
 	}
 }
+
+func TestQuickVariantShortensGame(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	rules, err := RulesFor(VariantQuick)
+	if err != nil {
+		t.Fatalf("取得規則錯誤: %v", err)
+	}
+	g, err := NewGameWithRules(names, 5, rules)
+	if err != nil {
+		t.Fatalf("NewGameWithRules 應該成功，卻得到錯誤：%v", err)
+	}
+	if g.MaxRounds != quickGameRounds {
+		t.Fatalf("快速規則應為 %d 回合，實際 %d", quickGameRounds, g.MaxRounds)
+	}
+	if _, err := RulesFor("unknown"); err == nil {
+		t.Fatalf("未知規則變體應回報錯誤")
+	}
+}
+
+func TestSaveAndRestoreGame(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	g, err := NewGame(names, 11)
+	if err != nil {
+		t.Fatalf("NewGame 應該成功，卻得到錯誤：%v", err)
+	}
+	g.Round = 4
+	g.Players[2].Alive = false
+	g.Players[3].SetIdentity(IdentityZombie)
+
+	data, err := json.Marshal(g.Save())
+	if err != nil {
+		t.Fatalf("序列化失敗: %v", err)
+	}
+	var saved SavedGame
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("反序列化失敗: %v", err)
+	}
+	restored, err := RestoreGame(saved, 1)
+	if err != nil {
+		t.Fatalf("RestoreGame 應該成功，卻得到錯誤：%v", err)
+	}
+
+	if restored.Round != 4 || restored.MaxRounds != g.MaxRounds {
+		t.Fatalf("回合資訊未正確還原")
+	}
+	if restored.RemainingCardCount() != g.RemainingCardCount() {
+		t.Fatalf("牌堆數量應為 %d，實際 %d", g.RemainingCardCount(), restored.RemainingCardCount())
+	}
+	for i, p := range g.Players {
+		r := restored.Players[i]
+		if r.Alive != p.Alive || r.Identity() != p.Identity() || r.OriginalIdentity() != p.OriginalIdentity() {
+			t.Fatalf("玩家 %s 的狀態未正確還原", p.Name)
+		}
+		if len(r.Hand) != len(p.Hand) {
+			t.Fatalf("玩家 %s 的手牌數應為 %d，實際 %d", p.Name, len(p.Hand), len(r.Hand))
+		}
+		for j := range p.Hand {
+			if r.Hand[j] != p.Hand[j] {
+				t.Fatalf("玩家 %s 第 %d 張手牌不一致", p.Name, j)
+			}
+		}
+	}
+}
+
+func TestCorruptSaveErrorCodes(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	g, _ := NewGame(names, 11)
+
+	mismatched := g.Save()
+	mismatched.Players[1].ID = 5
+	unknown := g.Save()
+	unknown.Players[0].CurrentIdentity = 99
+	for _, saved := range []SavedGame{mismatched, unknown} {
+		if _, err := RestoreGame(saved, 1); !errors.Is(err, ErrSavedGameCorrupt) {
+			t.Fatalf("毀損的保存資料應以 %s 拒絕，卻得到 %v", ErrSavedGameCorrupt.Code, err)
+		}
+	}
+	if _, err := g.BuildPrivateSnapshot(len(names), ""); !errors.Is(err, ErrPlayerNotFound) {
+		t.Fatalf("不存在的玩家應以 %s 拒絕，卻得到 %v", ErrPlayerNotFound.Code, err)
+	}
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+
+	"zombierush/internal/i18n"
+)
+
+// SavedPlayer 為玩家狀態的可序列化形式；身份以整數保存以便還原
+type SavedPlayer struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	OriginalIdentity int    `json:"originalIdentity"`
+	CurrentIdentity  int    `json:"currentIdentity"`
+	Alive            bool   `json:"alive"`
+	Hand             []Card `json:"hand"`
+}
+
+// SavedGame 為整場遊戲的完整狀態，可寫入 JSON 並於日後還原；行動紀錄以預設語系保存，保存的對局因此不依賴訊息目錄
+type SavedGame struct {
+	Players    []SavedPlayer `json:"players"`
+	Deck       []Card        `json:"deck"`
+	Discarded  []Card        `json:"discarded"`
+	Round      int           `json:"round"`
+	MaxRounds  int           `json:"maxRounds"`
+	Variant    Variant       `json:"variant"`
+	Logs       []string      `json:"logs"`
+	LastCardID int           `json:"lastCardId"`
+}
+
+// Save 匯出遊戲狀態的深層副本
+func (g *Game) Save() SavedGame {
+	players := make([]SavedPlayer, len(g.Players))
+	for i, p := range g.Players {
+		players[i] = SavedPlayer{
+			ID:               p.ID,
+			Name:             p.Name,
+			OriginalIdentity: int(p.originalIdentity),
+			CurrentIdentity:  int(p.currentIdentity),
+			Alive:            p.Alive,
+			Hand:             append([]Card(nil), p.Hand...),
+		}
+	}
+	return SavedGame{
+		Players:    players,
+		Deck:       append([]Card(nil), g.cardDeck...),
+		Discarded:  append([]Card(nil), g.cardDiscarded...),
+		Round:      g.Round,
+		MaxRounds:  g.MaxRounds,
+		Variant:    g.Variant,
+		Logs:       g.Logs(i18n.Default),
+		LastCardID: g.lastCardID,
+	}
+}
+
+// RestoreGame 由保存的狀態重建遊戲；亂數來源無法保存，改以 seed 重新建立
+func RestoreGame(saved SavedGame, seed int64) (*Game, error) {
+	if len(saved.Players) != 8 {
+		return nil, ErrInvalidPlayerCount
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	g := &Game{
+		Players:       make([]*Player, len(saved.Players)),
+		cardDeck:      append([]Card(nil), saved.Deck...),
+		cardDiscarded: append([]Card(nil), saved.Discarded...),
+		Round:         saved.Round,
+		MaxRounds:     saved.MaxRounds,
+		Variant:       saved.Variant,
+		rng:           rand.New(rand.NewSource(seed)),
+		logs:          make([]i18n.Message, 0, len(saved.Logs)),
+		lastCardID:    saved.LastCardID,
+	}
+	for i, sp := range saved.Players {
+		if sp.ID != i {
+			return nil, ErrSavedGameCorrupt.WithDetail("error.saved_game_corrupt.player", "index", i, "id", sp.ID)
+		}
+		original, err := identityFromInt(sp.OriginalIdentity)
+		if err != nil {
+			return nil, err
+		}
+		current, err := identityFromInt(sp.CurrentIdentity)
+		if err != nil {
+			return nil, err
+		}
+		g.Players[i] = &Player{
+			ID:               sp.ID,
+			Name:             sp.Name,
+			originalIdentity: original,
+			currentIdentity:  current,
+			Alive:            sp.Alive,
+			Hand:             append([]Card(nil), sp.Hand...),
+		}
+	}
+	// 保存的紀錄已是預設語系的文字，還原後原樣呈現
+	for _, text := range saved.Logs {
+		g.logs = append(g.logs, i18n.M("game.saved_log", "text", text))
+	}
+	if g.lastCardID == 0 {
+		g.assignMissingCardIDs()
+	}
+	return g, nil
+}
+
+// assignMissingCardIDs 為舊版保存資料中尚無編號的牌補發編號
+func (g *Game) assignMissingCardIDs() {
+	for _, p := range g.Players {
+		for i := range p.Hand {
+			p.Hand[i] = g.issueCard(p.Hand[i])
+		}
+	}
+	for i := range g.cardDeck {
+		g.cardDeck[i] = g.issueCard(g.cardDeck[i])
+	}
+	for i := range g.cardDiscarded {
+		g.cardDiscarded[i] = g.issueCard(g.cardDiscarded[i])
+	}
+}
+
+func identityFromInt(v int) (Identity, error) {
+	switch Identity(v) {
+	case IdentityHuman, IdentityZombie:
+		return Identity(v), nil
+	default:
+		return 0, ErrSavedGameCorrupt.WithDetail("error.saved_game_corrupt.identity", "value", v)
+	}
+}
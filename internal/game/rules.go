@@ -0,0 +1,29 @@
+package game
+
+// Variant 表示規則變體
+type Variant string
+
+const (
+	VariantStandard Variant = "standard"
+	VariantQuick    Variant = "quick"
+)
+
+const quickGameRounds = 6
+
+// Rules 描述一局遊戲可調整的規則
+type Rules struct {
+	Variant   Variant
+	MaxRounds int
+}
+
+// RulesFor 取得指定變體的規則，空字串視為標準規則
+func RulesFor(variant Variant) (Rules, error) {
+	switch variant {
+	case VariantStandard, "":
+		return Rules{Variant: VariantStandard, MaxRounds: maxGameRounds}, nil
+	case VariantQuick:
+		return Rules{Variant: VariantQuick, MaxRounds: quickGameRounds}, nil
+	default:
+		return Rules{}, ErrUnknownVariant.WithDetail("error.unknown_variant.named", "variant", variant)
+	}
+}
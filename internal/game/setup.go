@@ -1,17 +1,11 @@
 package game
 
 import (
-	"errors"
 	"fmt"
 	"math/rand"
 	"time"
 )
 
-var (
-	// ErrInvalidPlayerCount 表示玩家人數不正確
-	ErrInvalidPlayerCount = errors.New("玩家人數必須為 8 人")
-)
-
 const (
 	initialHandSize            = 15
 	maxGameRounds              = 12
@@ -21,8 +15,14 @@ const (
 	initialShotgunPerPlayer    = 1
 )
 
-// NewGame 建立並初始化一場遊戲
+// NewGame 以標準規則建立並初始化一場遊戲
 func NewGame(names []string, seed int64) (*Game, error) {
+	rules, _ := RulesFor(VariantStandard)
+	return NewGameWithRules(names, seed, rules)
+}
+
+// NewGameWithRules 依指定規則建立並初始化一場遊戲
+func NewGameWithRules(names []string, seed int64, rules Rules) (*Game, error) {
 	if len(names) != 8 {
 		return nil, ErrInvalidPlayerCount
 	}
@@ -35,7 +35,8 @@ func NewGame(names []string, seed int64) (*Game, error) {
 
 	g := &Game{
 		Players:   make([]*Player, len(names)),
-		MaxRounds: maxGameRounds,
+		MaxRounds: rules.MaxRounds,
+		Variant:   rules.Variant,
 		rng:       rng,
 	}
 	identities := buildIdentityDeck()
@@ -55,6 +56,9 @@ func NewGame(names []string, seed int64) (*Game, error) {
 	}
 
 	g.cardDeck = buildNumberDeck(numericDeckCopies)
+	for i := range g.cardDeck {
+		g.cardDeck[i] = g.issueCard(g.cardDeck[i])
+	}
 	shuffleCards(rng, g.cardDeck)
 
 	// 發送數字牌
@@ -126,7 +130,7 @@ func distributeSpecialCards(g *Game) {
 	for _, p := range g.Players {
 		need := initialShotgunPerPlayer - p.CountKind(CardKindShotgun)
 		for i := 0; i < need; i++ {
-			p.AddCard(Card{Kind: CardKindShotgun})
+			p.AddCard(g.issueCard(Card{Kind: CardKindShotgun}))
 		}
 	}
 
@@ -140,7 +144,7 @@ func distributeSpecialCards(g *Game) {
 
 	// 分發疫苗
 	for i := 0; i < totalVaccineCards && i < len(indices); i++ {
-		g.Players[indices[i]].AddCard(Card{Kind: CardKindVaccine})
+		g.Players[indices[i]].AddCard(g.issueCard(Card{Kind: CardKindVaccine}))
 	}
 }
 
@@ -148,7 +152,7 @@ func equipInitialZombieCards(g *Game) {
 	for _, p := range g.Players {
 		if p.IsZombie() {
 			for i := 0; i < initialZombieCardPerPlayer; i++ {
-				p.AddCard(Card{Kind: CardKindZombie})
+				p.AddCard(g.issueCard(Card{Kind: CardKindZombie}))
 			}
 		}
 	}
@@ -1,6 +1,8 @@
 package game
 
-import "fmt"
+import (
+	"zombierush/internal/i18n"
+)
 
 // PublicPlayerSnapshot 用於前端展示公共資訊
 type PublicPlayerSnapshot struct {
@@ -19,6 +21,7 @@ type PublicSnapshot struct {
 
 // CardView 提供手牌的前端展示結構
 type CardView struct {
+	ID    int      `json:"id"`
 	Index int      `json:"index"`
 	Kind  CardKind `json:"kind"`
 	Suit  Suit     `json:"suit,omitempty"`
@@ -57,23 +60,24 @@ func (g *Game) BuildPublicSnapshot() PublicSnapshot {
 	}
 }
 
-// BuildPrivateSnapshot 為指定玩家製作詳細資訊
-func (g *Game) BuildPrivateSnapshot(playerID int) (PrivatePlayerSnapshot, error) {
+// BuildPrivateSnapshot 為指定玩家製作詳細資訊，牌面名稱依該玩家的語系呈現
+func (g *Game) BuildPrivateSnapshot(playerID int, loc i18n.Locale) (PrivatePlayerSnapshot, error) {
 	if playerID < 0 || playerID >= len(g.Players) {
-		return PrivatePlayerSnapshot{}, fmt.Errorf("無法建立玩家 %d 的視角", playerID)
+		return PrivatePlayerSnapshot{}, ErrPlayerNotFound.WithDetail("error.player_not_found.id", "id", playerID)
 	}
 	p := g.Players[playerID]
 	if p == nil {
-		return PrivatePlayerSnapshot{}, fmt.Errorf("玩家 %d 尚未初始化", playerID)
+		return PrivatePlayerSnapshot{}, ErrPlayerNotFound.WithDetail("error.player_not_found.id", "id", playerID)
 	}
 	handViews := make([]CardView, len(p.Hand))
 	for i, c := range p.Hand {
 		handViews[i] = CardView{
+			ID:    c.ID,
 			Index: i,
 			Kind:  c.Kind,
 			Suit:  c.Suit,
 			Value: c.Value,
-			Label: c.String(),
+			Label: c.Localize(loc),
 		}
 	}
 
@@ -1,8 +1,9 @@
 package game
 
 import (
-	"fmt"
 	"math/rand"
+
+	"zombierush/internal/i18n"
 )
 
 // Game 表示整場遊戲的狀態
@@ -12,19 +13,31 @@ type Game struct {
 	cardDiscarded []Card
 	Round         int
 	MaxRounds     int
+	Variant       Variant
 	rng           *rand.Rand
-	logs          []string
+	logs          []i18n.Message
+	lastCardID    int
+}
+
+// issueCard 為新產生的牌配發唯一編號
+func (g *Game) issueCard(card Card) Card {
+	g.lastCardID++
+	card.ID = g.lastCardID
+	return card
 }
 
-func (g *Game) addLog(entry string) {
+// addLog 記下行動紀錄；保留訊息本身，呈現時才依語系轉為文字
+func (g *Game) addLog(entry i18n.Message) {
 	g.logs = append(g.logs, entry)
 }
 
-// Logs 返回行動紀錄的副本
-func (g *Game) Logs() []string {
-	copySlice := make([]string, len(g.logs))
-	copy(copySlice, g.logs)
-	return copySlice
+// Logs 依語系返回行動紀錄
+func (g *Game) Logs(loc i18n.Locale) []string {
+	logs := make([]string, len(g.logs))
+	for i, entry := range g.logs {
+		logs[i] = entry.Localize(loc)
+	}
+	return logs
 }
 
 // AlivePlayers 回傳仍在場的玩家
@@ -79,11 +92,11 @@ func (g *Game) DetermineWinner() (humanWins bool, humans int, zombies int) {
 // Player 根據編號取得玩家
 func (g *Game) Player(id int) (*Player, error) {
 	if id < 0 || id >= len(g.Players) {
-		return nil, fmt.Errorf("找不到編號為 %d 的玩家", id)
+		return nil, ErrPlayerNotFound.WithDetail("error.player_not_found.id", "id", id)
 	}
 	player := g.Players[id]
 	if !player.Alive {
-		return nil, fmt.Errorf("玩家 %s 已被淘汰", player.Name)
+		return nil, ErrPlayerEliminated.WithDetail("error.player_eliminated.named", "player", player.Name)
 	}
 	return player, nil
 }
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+
+	"zombierush/internal/i18n"
 )
 
 // Suit 表示數字牌的花色
@@ -26,17 +28,27 @@ const (
 	IdentityZombie
 )
 
-func (i Identity) String() string {
+func (i Identity) key() string {
 	switch i {
 	case IdentityHuman:
-		return "人類"
+		return "human"
 	case IdentityZombie:
-		return "僵屍"
+		return "zombie"
 	default:
-		return "未知"
+		return "unknown"
 	}
 }
 
+func (i Identity) String() string {
+	return i.Localize(i18n.Default)
+}
+
+// Localize 依語系呈現身份名稱
+func (i Identity) Localize(loc i18n.Locale) string {
+	return i18n.M("identity." + i.key()).Localize(loc)
+}
+
+// MarshalJSON 輸出的名稱屬於協定的一部分，固定使用預設語系，不隨收訊者語系改變
 func (i Identity) MarshalJSON() ([]byte, error) {
 	return json.Marshal(i.String())
 }
@@ -51,41 +63,48 @@ const (
 	CardKindVaccine
 )
 
-func (k CardKind) String() string {
+func (k CardKind) key() string {
 	switch k {
 	case CardKindNumber:
-		return "數字牌"
+		return "number"
 	case CardKindZombie:
-		return "僵屍牌"
+		return "zombie"
 	case CardKindShotgun:
-		return "獵槍牌"
+		return "shotgun"
 	case CardKindVaccine:
-		return "疫苗牌"
+		return "vaccine"
 	default:
-		return "未知牌"
+		return "unknown"
 	}
 }
 
-// Card 表示手牌/特殊牌
+func (k CardKind) String() string {
+	return k.Localize(i18n.Default)
+}
+
+// Localize 依語系呈現牌型名稱
+func (k CardKind) Localize(loc i18n.Locale) string {
+	return i18n.M("card." + k.key()).Localize(loc)
+}
+
+// Card 表示手牌/特殊牌；ID 於產生時配發，整場遊戲內唯一
 type Card struct {
+	ID    int      `json:"id"`
 	Kind  CardKind `json:"kind"`
 	Suit  Suit     `json:"suit,omitempty"`
 	Value int      `json:"value,omitempty"`
 }
 
 func (c Card) String() string {
-	switch c.Kind {
-	case CardKindNumber:
+	return c.Localize(i18n.Default)
+}
+
+// Localize 依語系呈現牌面；數字牌以花色與點數表示，不需翻譯
+func (c Card) Localize(loc i18n.Locale) string {
+	if c.Kind == CardKindNumber {
 		return fmt.Sprintf("%s%d", c.Suit, c.Value)
-	case CardKindZombie:
-		return "僵屍牌"
-	case CardKindShotgun:
-		return "獵槍牌"
-	case CardKindVaccine:
-		return "疫苗牌"
-	default:
-		return "未知牌"
 	}
+	return c.Kind.Localize(loc)
 }
 
 func (c Card) IsNumeric() bool {
@@ -130,7 +149,7 @@ func (p *Player) AddCard(card Card) {
 // RemoveCardAt 移除指定索引的牌
 func (p *Player) RemoveCardAt(index int) (Card, error) {
 	if index < 0 || index >= len(p.Hand) {
-		return Card{}, fmt.Errorf("玩家 %s 的手牌索引超出範圍", p.Name)
+		return Card{}, ErrCardNotInHand.WithDetail("error.card_not_in_hand.index", "player", p.Name)
 	}
 	card := p.Hand[index]
 	p.Hand = append(p.Hand[:index], p.Hand[index+1:]...)
@@ -149,7 +168,7 @@ func (p *Player) RemoveCards(indices []int) ([]Card, error) {
 	seen := make(map[int]struct{})
 	for _, idx := range sorted {
 		if _, duplicated := seen[idx]; duplicated {
-			return nil, fmt.Errorf("重複的牌索引 %d", idx)
+			return nil, ErrDuplicateCard.WithDetail("error.duplicate_card.index", "index", idx)
 		}
 		seen[idx] = struct{}{}
 		card, err := p.RemoveCardAt(idx)
@@ -166,6 +185,32 @@ func (p *Player) RemoveCards(indices []int) ([]Card, error) {
 	return removed, nil
 }
 
+// CardIndex 回傳指定編號的牌在手牌中的索引，不在手牌中時回傳 -1
+func (p *Player) CardIndex(id int) int {
+	for i, c := range p.Hand {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveCardsByID 依牌編號移除多張牌；任一編號不在手牌中即整組拒絕，避免依過期手牌誤出
+func (p *Player) RemoveCardsByID(ids []int) ([]Card, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	indices := make([]int, 0, len(ids))
+	for _, id := range ids {
+		idx := p.CardIndex(id)
+		if idx < 0 {
+			return nil, ErrCardNotInHand.WithDetail("error.card_not_in_hand.id", "player", p.Name, "card", id)
+		}
+		indices = append(indices, idx)
+	}
+	return p.RemoveCards(indices)
+}
+
 // HandSize 回傳手牌數量
 func (p *Player) HandSize() int {
 	return len(p.Hand)
@@ -0,0 +1,215 @@
+package i18n
+
+// en 為英文訊息目錄
+var en = map[string]string{
+	"list.separator": ", ",
+
+	"identity.human":   "Human",
+	"identity.zombie":  "Zombie",
+	"identity.unknown": "Unknown",
+	"card.number":      "Number card",
+	"card.zombie":      "Zombie card",
+	"card.shotgun":     "Shotgun",
+	"card.vaccine":     "Vaccine",
+	"card.unknown":     "Unknown card",
+	"team.humans":      "the humans",
+	"team.zombies":     "the zombies",
+
+	"name.player":      "Player",
+	"name.room":        "Untitled room",
+	"name.bot":         "Bot {number}",
+	"name.seat":        "Seat {number}",
+	"name.stand_in":    "{player} (AI)",
+	"name.quick_match": "Quick match ({host})",
+
+	"game.eliminated":        "{player} was eliminated ({reason})",
+	"game.reason.hand_empty": "ran out of cards",
+	"game.reason.shot":       "shot with the shotgun",
+	"game.challenge_result":  "Challenge result: {winner} won, {loser} lost",
+	"game.card_stolen":       "{winner} took {card} from {loser}",
+	"game.vaccine_counter":   "{defender} countered with the vaccine; {attacker} was forced back to human",
+	"game.zombie_infection":  "Zombie card! {attacker} infected {defender}",
+	"game.zombie_granted":    "{player} became a zombie and received a zombie card",
+	"game.shotgun_miss":      "The shotgun missed! {defender} is not a zombie, so {attacker}'s attack failed",
+	"game.shotgun_hit":       "Shotgun hit! {attacker} shot the zombie {defender}",
+	"game.vaccine_reversal":  "{defender} used the vaccine; {attacker} was turned back into a human",
+	"game.no_matching_suit":  "Defender {defender} has no card of the same suit",
+	"game.attack_wins":       "{attacker} beat {defender}'s {defenseTotal} with {attackTotal}",
+	"game.defense_holds":     "{defender} held off {attacker}'s {attackTotal} with {defenseTotal}",
+	"game.tie":               "Both sides tied at {total} in {suit}",
+	"game.saved_log":         "{text}",
+
+	"log.afk":               "{player} stepped away; an AI is playing for them",
+	"log.afk_returned":      "{player} is back and has taken control again",
+	"log.kicked":            "{player} was removed by the host",
+	"log.banned":            "{player} was banned by the host",
+	"log.host_transferred":  "{player} is now the host",
+	"log.pause_proposed":    "{player} proposed pausing the game ({votes}/{needed})",
+	"log.resume_proposed":   "{player} proposed resuming the game ({votes}/{needed})",
+	"log.paused":            "The game is paused",
+	"log.resumed":           "The game has resumed",
+	"log.rematch_timeout":   "The rematch vote timed out; the room is back in the lobby",
+	"log.card_stolen":       "Card taken: {card}",
+	"log.player_eliminated": "{player} was eliminated",
+	"log.game_over":         "Game over: {winner} win",
+	"log.seats_shuffled":    "The host shuffled the seats",
+	"log.seat_taken_over":   "{player} took over seat {seat}",
+	"log.turn_timeout":      "{player} ran out of time; the system played for them",
+	"log.defense_timeout":   "{player} ran out of time to defend; the system defended for them",
+
+	"info.afk":                "You missed {missed} turns in a row, so an AI is playing for you; take any action to regain control",
+	"info.swap_requested":     "Seat swap requested with {player}",
+	"info.swap_declined":      "{player} declined the seat swap",
+	"info.takeover_requested": "Takeover request sent; waiting for the host to approve",
+	"info.takeover_declined":  "The host declined your takeover request",
+	"info.superseded":         "This account connected from another tab or device, so this page was disconnected",
+
+	"leave.adjourned":         "The game was adjourned; reopen it from \"My saved games\"",
+	"leave.spectators_closed": "The host turned off spectating",
+	"leave.kicked":            "You were removed from the room by the host",
+	"leave.banned":            "You were banned by the host and cannot rejoin this room",
+	"leave.rematch_declined":  "You chose to leave; the room is starting a rematch",
+	"leave.room_closed":       "The room was closed",
+
+	"error.invalid_player_count":          "A game needs exactly 8 players",
+	"error.unknown_variant":               "Unknown rule variant",
+	"error.unknown_variant.named":         "Unknown rule variant {variant}",
+	"error.not_your_turn":                 "It is not your turn",
+	"error.player_not_found":              "Player not found",
+	"error.player_not_found.id":           "No player with id {id}",
+	"error.player_eliminated":             "That player has been eliminated",
+	"error.player_eliminated.named":       "{player} has been eliminated",
+	"error.self_challenge":                "You cannot challenge yourself",
+	"error.no_cards_selected":             "Select at least one card",
+	"error.no_cards_selected.attacker":    "The attacker must choose cards to play",
+	"error.no_cards_selected.number":      "Select at least one number card",
+	"error.card_not_in_hand":              "That card is not in your hand",
+	"error.card_not_in_hand.index":        "Hand index out of range for {player}",
+	"error.card_not_in_hand.id":           "{player} has no card with id {card}",
+	"error.card_not_in_hand.bad_index":    "Hand index {index} is invalid",
+	"error.card_not_in_hand.stale":        "Card {card} is no longer in your hand; please choose again",
+	"error.duplicate_card":                "The same card was selected twice",
+	"error.duplicate_card.index":          "Card index {index} was selected twice",
+	"error.too_many_cards":                "You can play at most five number cards at once",
+	"error.too_many_cards.special":        "Only one special card can be played at a time",
+	"error.too_many_cards.defense":        "You can defend with at most five cards",
+	"error.mixed_cards":                   "Number cards cannot be mixed with special cards",
+	"error.invalid_suit":                  "Multiple number cards must share a suit",
+	"error.invalid_suit.defense":          "The defender must play the same suit",
+	"error.invalid_suit.named":            "Defense cards must be {suit}",
+	"error.invalid_defense":               "The defender's cards are not valid",
+	"error.shotgun_not_allowed":           "The defender cannot use the shotgun",
+	"error.zombie_card_not_allowed":       "The defender cannot use the zombie card",
+	"error.zombie_card_not_allowed.human": "Only zombies can play the zombie card",
+	"error.vaccine_not_allowed":           "The attacker cannot use the vaccine",
+	"error.vaccine_not_allowed.attack":    "The vaccine can only be used to defend",
+	"error.unknown_card_kind":             "Unknown card type",
+
+	"error.username_required":     "A username is required",
+	"error.password_too_short":    "The password must be at least 6 characters",
+	"error.user_exists":           "That username is already taken",
+	"error.bad_credentials":       "Incorrect username or password",
+	"error.session_required":      "Missing session token",
+	"error.session_expired":       "The session is invalid or has expired",
+	"error.game_not_found":        "Saved game not found",
+	"error.credentials_required":  "Please provide a username and password",
+	"error.method_not_allowed":    "Only {method} is supported",
+	"error.session_create_failed": "Could not create a session",
+
+	"error.not_in_room":                "You have not joined a room",
+	"error.already_in_room":            "Leave your current room first",
+	"error.room_required":              "A room id or invite code is required",
+	"error.room_required.id":           "A room id is required",
+	"error.room_not_found":             "Room not found",
+	"error.room_full":                  "The room is full",
+	"error.room_locked":                "The room is locked",
+	"error.seat_required":              "Choose a seat",
+	"error.invalid_seat":               "Invalid seat",
+	"error.not_seated":                 "You do not have a seat",
+	"error.banned":                     "You are banned from this room",
+	"error.host_only":                  "Only the host can do that",
+	"error.host_only.settings":         "Only the host can change room settings",
+	"error.host_only.add_bot":          "Only the host can add bots",
+	"error.host_only.remove_bot":       "Only the host can remove bots",
+	"error.host_only.kick":             "Only the host can remove players",
+	"error.host_only.transfer":         "Only the host can hand over hosting",
+	"error.host_only.lock":             "Only the host can lock the room",
+	"error.host_only.claims":           "Only the host can review takeover requests",
+	"error.host_only.shuffle":          "Only the host can shuffle seats",
+	"error.host_only.min_humans":       "Only the host can change the player threshold",
+	"error.host_only.start":            "Only the host can start the game",
+	"error.host_only.rematch":          "Only the host can start a rematch",
+	"error.host_only.return_lobby":     "Only the host can return the room to the lobby",
+	"error.host_only.adjourn":          "Only the host can adjourn the game",
+	"error.unknown_command":            "Unknown command",
+	"error.unknown_state":              "Unknown state type: {target}",
+	"error.game_not_running":           "The game has not started",
+	"error.spectator_cannot_act":       "Spectators cannot act",
+	"error.game_paused":                "The game is paused",
+	"error.private_room":               "Private rooms can only be joined with an invite code",
+	"error.wrong_password":             "Wrong room password",
+	"error.archive_disabled":           "Game saving is not enabled on this server",
+	"error.host_required":              "A room needs a host",
+	"error.unsupported_protocol":       "This client is out of date; please reload the page",
+	"error.not_participant":            "Only original participants can reopen this game",
+	"error.saved_game_corrupt":         "This saved game is corrupted and cannot be reopened",
+	"error.adjourn_not_running":        "Only a running game can be adjourned",
+	"error.adjourn_pending":            "Wait for the current challenge to resolve before adjourning",
+	"error.adjourn_no_humans":          "A game without human players cannot be adjourned",
+	"error.seat_not_human":             "Seat {seat} has no human player",
+	"error.kick_self":                  "You cannot remove yourself",
+	"error.host_must_be_human":         "Hosting can only be handed to a human player",
+	"error.already_host":               "That player is already the host",
+	"error.pause_not_running":          "Only a running game can be paused",
+	"error.spectator_cannot_vote":      "Spectators cannot vote",
+	"error.not_paused":                 "The game is not paused",
+	"error.ready_not_lobby":            "Ready status can only change in the lobby",
+	"error.min_humans_not_lobby":       "The player threshold can only change in the lobby",
+	"error.min_humans_range":           "The player threshold must be between 1 and {max}",
+	"error.not_enough_humans":          "Not enough human players ({humans}/{required})",
+	"error.players_not_ready":          "Some players are not ready: {players}",
+	"error.not_post_game":              "There is no post-game vote in progress",
+	"error.invalid_vote":               "Invalid vote",
+	"error.rematch_votes":              "Not enough rematch votes ({votes}/{required})",
+	"error.game_not_finished":          "The game has not finished",
+	"error.bots_lobby_only":            "Bots can only be added in the lobby",
+	"error.seat_no_bot":                "Seat {seat} has no bot",
+	"error.seat_token_mismatch":        "That seat token belongs to another account",
+	"error.seat_connected":             "That seat is already connected",
+	"error.already_seated":             "This account already has a seat in the room",
+	"error.game_in_progress":           "The game has already started or finished",
+	"error.game_already_started":       "The game is already running or finished",
+	"error.challenge_pending":          "A challenge is already pending",
+	"error.attacker_invalid":           "The attacker is in an invalid state",
+	"error.target_invalid":             "That player cannot be challenged",
+	"error.no_pending_challenge":       "There is no challenge to defend",
+	"error.not_defender":               "You are not the defender",
+	"error.seat_taken":                 "Seat {seat} is taken",
+	"error.swap_expired":               "The seat swap request has expired",
+	"error.seating_lobby_only":         "Seats can only change in the lobby",
+	"error.turn_timeout_range":         "The turn time limit must be 0 (no limit) or between {min} and {max} seconds",
+	"error.defense_timeout_range":      "The defense time limit must be 0 (no limit) or between {min} and {max} seconds",
+	"error.unknown_bot_difficulty":     "Unknown bot difficulty {difficulty}",
+	"error.unknown_visibility":         "Unknown room visibility {visibility}",
+	"error.afk_limit_range":            "The idle limit must be between 0 and {max}",
+	"error.move_hours_range":           "The correspondence time limit must be between 1 and {max} hours",
+	"error.settings_lobby_only":        "Room settings can only change in the lobby",
+	"error.correspondence_unavailable": "Correspondence games need game saving, which is not enabled on this server",
+	"error.spectators_disabled":        "This room does not allow spectators",
+	"error.already_seated_here":        "You already have a seat in this room",
+	"error.takeover_not_running":       "Only seats in a running game can be taken over",
+	"error.takeover_own_seat":          "You already have a seat here; join the room to return to it",
+	"error.seat_not_bot":               "Seat {seat} is not controlled by a bot",
+	"error.takeover_claimed":           "Another player has already asked to take over this seat",
+	"error.takeover_no_host":           "There is no host to review the request",
+	"error.takeover_expired":           "The takeover request has expired",
+	"error.takeover_unavailable":       "The seat can no longer be taken over",
+	"error.claimant_elsewhere":         "The requester has joined another room",
+	"error.claimant_seated":            "The requester already has a seat in this room",
+
+	"error.saved_game_corrupt.player":   "Corrupted save: position {index} holds player {id}",
+	"error.saved_game_corrupt.identity": "Corrupted save: unknown identity code {value}",
+	"error.saved_game_corrupt.seats":    "Corrupted save: the seat count does not match the players",
+	"error.saved_game_corrupt.attacker": "Corrupted save: attacker seat {seat} does not exist",
+	"error.saved_game_corrupt.defender": "Corrupted save: defender seat {seat} does not exist",
+}
@@ -0,0 +1,143 @@
+// Package i18n 提供伺服器訊息目錄：訊息以 ID 與具名參數表示，送出前才依收訊者的語系轉為文字。
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Locale 為語系代碼
+type Locale string
+
+const (
+	ZhTW Locale = "zh-TW"
+	En   Locale = "en"
+
+	// Default 為未指定或不支援的語系所使用的語系
+	Default = ZhTW
+)
+
+var catalogs = map[Locale]map[string]string{
+	ZhTW: zhTW,
+	En:   en,
+}
+
+// Localizer 由能依語系呈現的值實作，例如訊息、身分與牌
+type Localizer interface {
+	Localize(loc Locale) string
+}
+
+// Message 為訊息目錄中的一則訊息與其參數
+type Message struct {
+	ID   string
+	Args map[string]any
+}
+
+// M 以鍵值對建立訊息，例如 M("game.eliminated", "player", name)
+func M(id string, kv ...any) Message {
+	msg := Message{ID: id}
+	if len(kv) > 0 {
+		msg.Args = make(map[string]any, len(kv)/2)
+		for i := 0; i+1 < len(kv); i += 2 {
+			msg.Args[fmt.Sprint(kv[i])] = kv[i+1]
+		}
+	}
+	return msg
+}
+
+// Localize 依語系套用範本；該語系缺少此訊息時退回預設語系，仍找不到則回傳訊息 ID
+func (m Message) Localize(loc Locale) string {
+	template, ok := catalogs[loc][m.ID]
+	if !ok {
+		template, ok = catalogs[Default][m.ID]
+	}
+	if !ok {
+		return m.ID
+	}
+	if len(m.Args) == 0 {
+		return template
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(template[:start])
+		name := template[start+1 : end]
+		if value, ok := m.Args[name]; ok {
+			b.WriteString(Text(value, loc))
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// String 以預設語系呈現訊息
+func (m Message) String() string {
+	return m.Localize(Default)
+}
+
+// List 為依語系使用不同分隔符號串接的清單
+type List []string
+
+func (l List) Localize(loc Locale) string {
+	return strings.Join(l, M("list.separator").Localize(loc))
+}
+
+// Text 將參數值依語系轉為文字；可在地化的值依語系呈現，其餘以 fmt 格式化
+func Text(value any, loc Locale) string {
+	if v, ok := value.(Localizer); ok {
+		return v.Localize(loc)
+	}
+	return fmt.Sprint(value)
+}
+
+// ErrorText 依語系呈現錯誤：錯誤本身或其包裹的錯誤可在地化時使用訊息目錄，否則沿用原始文字
+func ErrorText(err error, loc Locale) string {
+	if err == nil {
+		return ""
+	}
+	var localized Localizer
+	if v, ok := err.(Localizer); ok {
+		localized = v
+	} else if !errors.As(err, &localized) {
+		return err.Error()
+	}
+	return localized.Localize(loc)
+}
+
+// Parse 解析 lang 參數或 Accept-Language 標頭，依序取第一個支援的語系，皆不支援時回傳 Default
+func Parse(value string) Locale {
+	for _, part := range strings.Split(value, ",") {
+		tag := strings.TrimSpace(part)
+		if i := strings.IndexByte(tag, ';'); i >= 0 {
+			tag = tag[:i]
+		}
+		tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+		switch {
+		case tag == "":
+			continue
+		case tag == "en" || strings.HasPrefix(tag, "en-"):
+			return En
+		case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+			return ZhTW
+		}
+	}
+	return Default
+}
+
+// Supported 回傳所有提供翻譯的語系
+func Supported() []Locale {
+	return []Locale{ZhTW, En}
+}
@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var placeholder = regexp.MustCompile(`\{[A-Za-z]+\}`)
+
+func TestCatalogsShareKeysAndPlaceholders(t *testing.T) {
+	for _, loc := range Supported() {
+		catalog := catalogs[loc]
+		for id, template := range catalogs[Default] {
+			translated, ok := catalog[id]
+			if !ok {
+				t.Fatalf("語系 %s 缺少訊息 %s", loc, id)
+			}
+			want := placeholder.FindAllString(template, -1)
+			got := placeholder.FindAllString(translated, -1)
+			sort.Strings(want)
+			sort.Strings(got)
+			if strings.Join(want, ",") != strings.Join(got, ",") {
+				t.Fatalf("語系 %s 的訊息 %s 參數不一致：%v / %v", loc, id, got, want)
+			}
+		}
+		if len(catalog) != len(catalogs[Default]) {
+			t.Fatalf("語系 %s 含有預設語系沒有的訊息", loc)
+		}
+	}
+}
+
+type fakeName string
+
+func (n fakeName) Localize(loc Locale) string { return string(n) + "@" + string(loc) }
+
+func TestMessageLocalize(t *testing.T) {
+	msg := M("game.eliminated", "player", "阿明", "reason", M("game.reason.shot"))
+	if got := msg.Localize(En); got != "阿明 was eliminated (shot with the shotgun)" {
+		t.Fatalf("英文訊息錯誤：%s", got)
+	}
+	if got := msg.String(); got != "玩家 阿明 被淘汰（遭獵槍射擊）" {
+		t.Fatalf("預設語系訊息錯誤：%s", got)
+	}
+	if got := M("log.afk", "player", fakeName("x")).Localize(En); !strings.HasPrefix(got, "x@en ") {
+		t.Fatalf("參數應依語系呈現：%s", got)
+	}
+	if got := M("players", "names", List{"A", "B"}).Localize(En); got != "players" {
+		t.Fatalf("未知訊息應回傳 ID：%s", got)
+	}
+	if got := M("error.players_not_ready", "players", List{"A", "B"}).Localize(En); got != "Some players are not ready: A, B" {
+		t.Fatalf("清單應使用語系分隔符號：%s", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]Locale{
+		"":                    Default,
+		"en":                  En,
+		"en-US,en;q=0.9":      En,
+		"fr-FR, en;q=0.5":     En,
+		"zh-Hant-TW,zh;q=0.9": ZhTW,
+		"ja":                  Default,
+	}
+	for input, want := range cases {
+		if got := Parse(input); got != want {
+			t.Fatalf("Parse(%q) = %s，應為 %s", input, got, want)
+		}
+	}
+}
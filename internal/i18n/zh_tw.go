@@ -0,0 +1,223 @@
+package i18n
+
+// zhTW 為繁體中文訊息目錄，也是預設語系與缺漏時的備援
+var zhTW = map[string]string{
+	"list.separator": "、",
+
+	// 身份、牌型與陣營
+	"identity.human":   "人類",
+	"identity.zombie":  "僵屍",
+	"identity.unknown": "未知",
+	"card.number":      "數字牌",
+	"card.zombie":      "僵屍牌",
+	"card.shotgun":     "獵槍牌",
+	"card.vaccine":     "疫苗牌",
+	"card.unknown":     "未知牌",
+	"team.humans":      "人類陣營",
+	"team.zombies":     "僵屍陣營",
+
+	// 未指定時的預設名稱
+	"name.player":      "玩家",
+	"name.room":        "未命名房間",
+	"name.bot":         "機器人{number}",
+	"name.seat":        "座位{number}",
+	"name.stand_in":    "{player} (AI)",
+	"name.quick_match": "快速配對（{host}）",
+
+	// 對局紀錄
+	"game.eliminated":        "玩家 {player} 被淘汰（{reason}）",
+	"game.reason.hand_empty": "手牌耗盡",
+	"game.reason.shot":       "遭獵槍射擊",
+	"game.challenge_result":  "挑戰結果：{winner} 勝，{loser} 負",
+	"game.card_stolen":       "{winner} 從 {loser} 抽走 {card}",
+	"game.vaccine_counter":   "{defender} 使用疫苗反制，{attacker} 被強制轉為人類",
+	"game.zombie_infection":  "僵屍牌出擊！{attacker} 將 {defender} 感染為僵屍",
+	"game.zombie_granted":    "{player} 成為僵屍並獲得一張僵屍牌",
+	"game.shotgun_miss":      "獵槍失效！{defender} 並非僵屍，{attacker} 攻擊落空",
+	"game.shotgun_hit":       "獵槍命中！{attacker} 射殺僵屍 {defender}",
+	"game.vaccine_reversal":  "{defender} 使用疫苗逆轉，{attacker} 被迫轉回人類",
+	"game.no_matching_suit":  "防守方 {defender} 無同花色牌可出",
+	"game.attack_wins":       "{attacker} 以 {attackTotal} 點擊敗 {defender} 的 {defenseTotal} 點",
+	"game.defense_holds":     "{defender} 以 {defenseTotal} 點守下 {attacker} 的 {attackTotal} 點",
+	"game.tie":               "雙方 {suit} 花色打成 {total} 點平手",
+	"game.saved_log":         "{text}",
+
+	// 房間紀錄與通知
+	"log.afk":               "玩家 {player} 暫時離開，由 AI 代打",
+	"log.afk_returned":      "玩家 {player} 已回來，取回控制權",
+	"log.kicked":            "玩家 {player} 已被房主請出房間",
+	"log.banned":            "玩家 {player} 已被房主封鎖",
+	"log.host_transferred":  "房主已轉交給 {player}",
+	"log.pause_proposed":    "玩家 {player} 提議暫停對局（{votes}/{needed}）",
+	"log.resume_proposed":   "玩家 {player} 提議繼續對局（{votes}/{needed}）",
+	"log.paused":            "對局已暫停",
+	"log.resumed":           "對局繼續",
+	"log.rematch_timeout":   "再戰投票逾時，房間返回待機",
+	"log.card_stolen":       "奪得手牌：{card}",
+	"log.player_eliminated": "玩家 {player} 被淘汰",
+	"log.game_over":         "對局結束，{winner}取得勝利",
+	"log.seats_shuffled":    "房主已隨機調整座位",
+	"log.seat_taken_over":   "玩家 {player} 接手了座位 {seat}",
+	"log.turn_timeout":      "玩家 {player} 行動逾時，由系統代為出牌",
+	"log.defense_timeout":   "玩家 {player} 防守逾時，由系統代為防守",
+
+	"info.afk":                "你已連續 {missed} 次未行動，暫由 AI 代打；任何操作即可取回控制",
+	"info.swap_requested":     "已向 {player} 提出換位請求",
+	"info.swap_declined":      "{player} 拒絕了換位請求",
+	"info.takeover_requested": "已送出接手申請，等待房主同意",
+	"info.takeover_declined":  "房主拒絕了接手申請",
+	"info.superseded":         "此帳號已在其他分頁或裝置連線，本頁連線已中斷",
+
+	"leave.adjourned":         "對局已封存，可於「我的保存對局」重新開啟",
+	"leave.spectators_closed": "房主已關閉觀戰",
+	"leave.kicked":            "你已被房主請出房間",
+	"leave.banned":            "你已被房主封鎖，無法再加入此房間",
+	"leave.rematch_declined":  "你已選擇離開，房間開始再戰",
+	"leave.room_closed":       "房間已關閉",
+
+	// 規則錯誤
+	"error.invalid_player_count":          "玩家人數必須為 8 人",
+	"error.unknown_variant":               "未知的規則變體",
+	"error.unknown_variant.named":         "未知的規則變體 {variant}",
+	"error.not_your_turn":                 "尚未輪到你行動",
+	"error.player_not_found":              "找不到指定的玩家",
+	"error.player_not_found.id":           "找不到編號為 {id} 的玩家",
+	"error.player_eliminated":             "玩家已被淘汰",
+	"error.player_eliminated.named":       "玩家 {player} 已被淘汰",
+	"error.self_challenge":                "挑戰目標不可為自己",
+	"error.no_cards_selected":             "必須選擇至少一張牌",
+	"error.no_cards_selected.attacker":    "攻擊者必須選擇要出的牌",
+	"error.no_cards_selected.number":      "必須選擇至少一張數字牌",
+	"error.card_not_in_hand":              "指定的牌不在手牌中",
+	"error.card_not_in_hand.index":        "玩家 {player} 的手牌索引超出範圍",
+	"error.card_not_in_hand.id":           "玩家 {player} 的手牌中沒有編號 {card} 的牌",
+	"error.card_not_in_hand.bad_index":    "手牌索引 {index} 無效",
+	"error.card_not_in_hand.stale":        "牌 {card} 已不在你的手牌中，請重新選擇",
+	"error.duplicate_card":                "重複指定同一張牌",
+	"error.duplicate_card.index":          "重複的牌索引 {index}",
+	"error.too_many_cards":                "一次最多只能打出五張數字牌",
+	"error.too_many_cards.special":        "特殊牌一次只能出一張",
+	"error.too_many_cards.defense":        "一次最多只能防禦五張牌",
+	"error.mixed_cards":                   "數字牌不可與特殊牌混出",
+	"error.invalid_suit":                  "多張數字牌必須為同一花色",
+	"error.invalid_suit.defense":          "防守方必須出相同花色",
+	"error.invalid_suit.named":            "防守牌需同花色 {suit}",
+	"error.invalid_defense":               "防守方的出牌無效",
+	"error.shotgun_not_allowed":           "防守方不可使用獵槍",
+	"error.zombie_card_not_allowed":       "防守方不可使用僵屍牌",
+	"error.zombie_card_not_allowed.human": "僵屍牌僅能由僵屍使用",
+	"error.vaccine_not_allowed":           "攻擊方不可使用疫苗",
+	"error.vaccine_not_allowed.attack":    "疫苗僅能在防守時使用",
+	"error.unknown_card_kind":             "未知牌型",
+
+	// 帳號與會話
+	"error.username_required":     "帳號不可為空",
+	"error.password_too_short":    "密碼長度至少 6 碼",
+	"error.user_exists":           "帳號已存在",
+	"error.bad_credentials":       "帳號或密碼錯誤",
+	"error.session_required":      "缺少會話 token",
+	"error.session_expired":       "會話無效或已過期",
+	"error.game_not_found":        "找不到保存的對局",
+	"error.credentials_required":  "請提供帳號與密碼",
+	"error.method_not_allowed":    "僅支援 {method}",
+	"error.session_create_failed": "建立會話失敗",
+
+	// 協定與房間錯誤
+	"error.not_in_room":                "尚未加入房間",
+	"error.already_in_room":            "請先離開目前房間",
+	"error.room_required":              "缺少房間 ID 或邀請碼",
+	"error.room_required.id":           "缺少房間 ID",
+	"error.room_not_found":             "房間不存在",
+	"error.room_full":                  "房間已滿",
+	"error.room_locked":                "房間已鎖定",
+	"error.seat_required":              "請指定座位",
+	"error.invalid_seat":               "無效座位",
+	"error.not_seated":                 "尚未入座",
+	"error.banned":                     "你已被此房間封鎖",
+	"error.host_only":                  "僅房主可執行此操作",
+	"error.host_only.settings":         "僅房主可調整房間設定",
+	"error.host_only.add_bot":          "僅房主可新增機器人",
+	"error.host_only.remove_bot":       "僅房主可移除機器人",
+	"error.host_only.kick":             "僅房主可移出玩家",
+	"error.host_only.transfer":         "僅房主可轉讓房主",
+	"error.host_only.lock":             "僅房主可鎖定房間",
+	"error.host_only.claims":           "僅房主可審核接手申請",
+	"error.host_only.shuffle":          "僅房主可打亂座位",
+	"error.host_only.min_humans":       "僅房主可調整人數門檻",
+	"error.host_only.start":            "僅房主可開始對戰",
+	"error.host_only.rematch":          "僅房主可開始再戰",
+	"error.host_only.return_lobby":     "僅房主可返回待機",
+	"error.host_only.adjourn":          "僅房主可封存對局",
+	"error.unknown_command":            "未知指令",
+	"error.unknown_state":              "未知的狀態種類：{target}",
+	"error.game_not_running":           "遊戲尚未開始",
+	"error.spectator_cannot_act":       "觀戰者無法行動",
+	"error.game_paused":                "對局已暫停",
+	"error.private_room":               "私人房間僅能透過邀請碼加入",
+	"error.wrong_password":             "房間密碼錯誤",
+	"error.archive_disabled":           "伺服器未啟用對局保存",
+	"error.host_required":              "缺少房主資訊",
+	"error.unsupported_protocol":       "用戶端版本過舊，請重新整理頁面",
+	"error.not_participant":            "僅原參與者可重新開啟此對局",
+	"error.saved_game_corrupt":         "保存的對局資料已毀損，無法重新開啟",
+	"error.adjourn_not_running":        "僅能封存進行中的對局",
+	"error.adjourn_pending":            "請等待目前的挑戰結算後再封存",
+	"error.adjourn_no_humans":          "沒有真人玩家的對局無法封存",
+	"error.seat_not_human":             "座位 {seat} 沒有真人玩家",
+	"error.kick_self":                  "不可將自己移出房間",
+	"error.host_must_be_human":         "僅能將房主交給真人玩家",
+	"error.already_host":               "該玩家已是房主",
+	"error.pause_not_running":          "僅能在對局進行中暫停",
+	"error.spectator_cannot_vote":      "觀戰者無法投票",
+	"error.not_paused":                 "對局未暫停",
+	"error.ready_not_lobby":            "僅能在待機狀態切換準備",
+	"error.min_humans_not_lobby":       "僅能在待機狀態調整人數門檻",
+	"error.min_humans_range":           "真人數門檻需介於 1 到 {max}",
+	"error.not_enough_humans":          "真人玩家不足（{humans}/{required}）",
+	"error.players_not_ready":          "尚有玩家未準備：{players}",
+	"error.not_post_game":              "目前不在賽後投票階段",
+	"error.invalid_vote":               "無效的投票選項",
+	"error.rematch_votes":              "再戰票數不足（{votes}/{required}）",
+	"error.game_not_finished":          "對局尚未結束",
+	"error.bots_lobby_only":            "僅能在待機狀態新增機器人",
+	"error.seat_no_bot":                "座位 {seat} 沒有機器人",
+	"error.seat_token_mismatch":        "座位憑證不屬於此帳號",
+	"error.seat_connected":             "該座位已有連線",
+	"error.already_seated":             "此帳號已在房間內入座",
+	"error.game_in_progress":           "遊戲已開始或結束，無法加入",
+	"error.game_already_started":       "遊戲已在進行或結束",
+	"error.challenge_pending":          "目前有待處理的挑戰",
+	"error.attacker_invalid":           "攻擊者狀態異常",
+	"error.target_invalid":             "目標不可挑戰",
+	"error.no_pending_challenge":       "目前沒有待防禦的挑戰",
+	"error.not_defender":               "非指定防守者",
+	"error.seat_taken":                 "座位 {seat} 已有人",
+	"error.swap_expired":               "換位請求已失效",
+	"error.seating_lobby_only":         "僅能在待機狀態調整座位",
+	"error.turn_timeout_range":         "回合時限需為 0（不限時）或介於 {min} 到 {max} 秒",
+	"error.defense_timeout_range":      "防守時限需為 0（不限時）或介於 {min} 到 {max} 秒",
+	"error.unknown_bot_difficulty":     "未知的機器人難度 {difficulty}",
+	"error.unknown_visibility":         "未知的房間可見度 {visibility}",
+	"error.afk_limit_range":            "閒置次數上限需介於 0 到 {max}",
+	"error.move_hours_range":           "通信對局時限需介於 1 到 {max} 小時",
+	"error.settings_lobby_only":        "僅能在待機狀態調整房間設定",
+	"error.correspondence_unavailable": "伺服器未啟用對局保存，無法使用通信對局",
+	"error.spectators_disabled":        "此房間不開放觀戰",
+	"error.already_seated_here":        "你已在此房間入座",
+	"error.takeover_not_running":       "僅能接手進行中對局的座位",
+	"error.takeover_own_seat":          "你在此房間已有座位，請直接加入以回到原座位",
+	"error.seat_not_bot":               "座位 {seat} 不是由機器人操作",
+	"error.takeover_claimed":           "已有其他玩家申請接手此座位",
+	"error.takeover_no_host":           "目前沒有房主可審核申請",
+	"error.takeover_expired":           "接手申請已失效",
+	"error.takeover_unavailable":       "座位已無法接手",
+	"error.claimant_elsewhere":         "申請者已加入其他房間",
+	"error.claimant_seated":            "申請者已在此房間入座",
+
+	// 保存資料毀損的細節
+	"error.saved_game_corrupt.player":   "保存資料毀損：位置 {index} 的玩家編號為 {id}",
+	"error.saved_game_corrupt.identity": "保存資料毀損：未知的身份代碼 {value}",
+	"error.saved_game_corrupt.seats":    "保存資料毀損：座位數與玩家數不一致",
+	"error.saved_game_corrupt.attacker": "保存資料毀損：攻擊座位 {seat} 不存在",
+	"error.saved_game_corrupt.defender": "保存資料毀損：防守座位 {seat} 不存在",
+}
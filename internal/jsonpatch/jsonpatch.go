@@ -0,0 +1,226 @@
+// Package jsonpatch 以 RFC 6902 JSON Patch 描述兩份 JSON 文件的差異，
+// 供伺服器只廣播狀態中變動的部分。文件皆為 encoding/json 解碼成 any 的結果
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation 為單一修補步驟；僅使用 add、remove、replace 三種操作
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Diff 計算把 from 變成 to 所需的操作。物件逐鍵比較，陣列逐索引比較並在尾端增減元素
+func Diff(from, to any) ([]Operation, error) {
+	ops := make([]Operation, 0)
+	if err := diff("", from, to, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func diff(path string, from, to any, ops *[]Operation) error {
+	switch a := from.(type) {
+	case map[string]any:
+		b, ok := to.(map[string]any)
+		if !ok {
+			return appendValue(ops, "replace", path, to)
+		}
+		for _, key := range sortedKeys(a) {
+			next, exists := b[key]
+			if !exists {
+				*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + escape(key)})
+				continue
+			}
+			if err := diff(path+"/"+escape(key), a[key], next, ops); err != nil {
+				return err
+			}
+		}
+		for _, key := range sortedKeys(b) {
+			if _, exists := a[key]; !exists {
+				if err := appendValue(ops, "add", path+"/"+escape(key), b[key]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case []any:
+		b, ok := to.([]any)
+		if !ok {
+			return appendValue(ops, "replace", path, to)
+		}
+		shared := min(len(a), len(b))
+		for i := 0; i < shared; i++ {
+			if err := diff(path+"/"+strconv.Itoa(i), a[i], b[i], ops); err != nil {
+				return err
+			}
+		}
+		for i := shared; i < len(b); i++ {
+			if err := appendValue(ops, "add", path+"/"+strconv.Itoa(i), b[i]); err != nil {
+				return err
+			}
+		}
+		// 由尾端往前移除，前面元素的索引才不會位移
+		for i := len(a) - 1; i >= shared; i-- {
+			*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		return nil
+	default:
+		if reflect.DeepEqual(from, to) {
+			return nil
+		}
+		return appendValue(ops, "replace", path, to)
+	}
+}
+
+func appendValue(ops *[]Operation, op, path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	*ops = append(*ops, Operation{Op: op, Path: path, Value: data})
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Apply 依序套用操作並回傳新文件；doc 本身不會被修改
+func Apply(doc any, ops []Operation) (any, error) {
+	result := clone(doc)
+	for _, op := range ops {
+		var value any
+		if op.Op != "remove" {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return nil, fmt.Errorf("jsonpatch: %s %s: %w", op.Op, op.Path, err)
+			}
+		}
+		next, err := apply(result, split(op.Path), op.Op, value)
+		if err != nil {
+			return nil, fmt.Errorf("jsonpatch: %s %s: %w", op.Op, op.Path, err)
+		}
+		result = next
+	}
+	return result, nil
+}
+
+func apply(node any, tokens []string, op string, value any) (any, error) {
+	if len(tokens) == 0 {
+		if op == "remove" {
+			return nil, nil
+		}
+		return value, nil
+	}
+	key, rest := tokens[0], tokens[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(rest) > 0 {
+			child, ok := n[key]
+			if !ok {
+				return nil, fmt.Errorf("路徑不存在：%s", key)
+			}
+			next, err := apply(child, rest, op, value)
+			if err != nil {
+				return nil, err
+			}
+			n[key] = next
+			return n, nil
+		}
+		switch op {
+		case "add":
+			n[key] = value
+		case "replace", "remove":
+			if _, ok := n[key]; !ok {
+				return nil, fmt.Errorf("路徑不存在：%s", key)
+			}
+			if op == "remove" {
+				delete(n, key)
+			} else {
+				n[key] = value
+			}
+		default:
+			return nil, fmt.Errorf("不支援的操作：%s", op)
+		}
+		return n, nil
+	case []any:
+		if key == "-" && len(rest) == 0 && op == "add" {
+			return append(n, value), nil
+		}
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index > len(n) || (index == len(n) && (op != "add" || len(rest) > 0)) {
+			return nil, fmt.Errorf("索引無效：%s", key)
+		}
+		if len(rest) > 0 {
+			next, err := apply(n[index], rest, op, value)
+			if err != nil {
+				return nil, err
+			}
+			n[index] = next
+			return n, nil
+		}
+		switch op {
+		case "add":
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = value
+		case "replace":
+			n[index] = value
+		case "remove":
+			n = append(n[:index], n[index+1:]...)
+		default:
+			return nil, fmt.Errorf("不支援的操作：%s", op)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("無法深入純值：%s", key)
+	}
+}
+
+// split 將 JSON Pointer 拆成各層鍵名並還原跳脫字元
+func split(path string) []string {
+	if path == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func escape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func clone(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(n))
+		for key, value := range n {
+			out[key] = clone(value)
+		}
+		return out
+	case []any:
+		out := make([]any, len(n))
+		for i, value := range n {
+			out[i] = clone(value)
+		}
+		return out
+	default:
+		return node
+	}
+}
@@ -0,0 +1,60 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var doc any
+	if err := json.Unmarshal([]byte(text), &doc); err != nil {
+		t.Fatalf("解碼失敗: %v", err)
+	}
+	return doc
+}
+
+func TestDiffApplyRoundTrip(t *testing.T) {
+	cases := []struct{ from, to string }{
+		{`{"a":1,"b":[1,2,3]}`, `{"a":1,"b":[1,2,3]}`},
+		{`{"a":1,"b":[1,2,3]}`, `{"a":2,"b":[1,5]}`},
+		{`{"seats":[{"name":"阿明","alive":true}]}`, `{"seats":[{"name":"阿明","alive":false},{"name":"Bot"}]}`},
+		{`{"a":{"x":1}}`, `{"a":null,"c/d~e":"新"}`},
+		{`{"a":[1]}`, `{"a":{"0":1}}`},
+		{`[1,2]`, `{"a":1}`},
+	}
+	for _, tc := range cases {
+		from, to := decode(t, tc.from), decode(t, tc.to)
+		ops, err := Diff(from, to)
+		if err != nil {
+			t.Fatalf("Diff(%s, %s) 失敗: %v", tc.from, tc.to, err)
+		}
+		got, err := Apply(from, ops)
+		if err != nil {
+			t.Fatalf("Apply(%s) 失敗: %v", tc.from, err)
+		}
+		if !reflect.DeepEqual(got, to) {
+			t.Fatalf("%s → %s 套用結果不符：%v", tc.from, tc.to, got)
+		}
+		if !reflect.DeepEqual(from, decode(t, tc.from)) {
+			t.Fatalf("Apply 不應修改原文件 %s", tc.from)
+		}
+	}
+}
+
+func TestDiffOnlyTouchesChangedFields(t *testing.T) {
+	ops, err := Diff(decode(t, `{"round":1,"seats":[{"name":"阿明"},{"name":"小美"}]}`), decode(t, `{"round":2,"seats":[{"name":"阿明"},{"name":"小美"}]}`))
+	if err != nil {
+		t.Fatalf("Diff 失敗: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Op != "replace" || ops[0].Path != "/round" || string(ops[0].Value) != "2" {
+		t.Fatalf("預期只替換 /round，實得 %+v", ops)
+	}
+}
+
+func TestApplyRejectsMissingPath(t *testing.T) {
+	if _, err := Apply(decode(t, `{"a":1}`), []Operation{{Op: "replace", Path: "/b", Value: json.RawMessage(`2`)}}); err == nil {
+		t.Fatal("替換不存在的路徑應回傳錯誤")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+const (
+	RoomVisibilityPublic  = "public"
+	RoomVisibilityPrivate = "private"
+
+	inviteCodeLength   = 6
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// JoinOptions 描述加入房間時附帶的憑證
+type JoinOptions struct {
+	Password   string
+	InviteCode string
+	Seat       *int // 指定座位，nil 表示自動入座
+}
+
+// RoomOptions 描述建立房間時的存取設定
+type RoomOptions struct {
+	Private  bool
+	Password string
+}
+
+func generateInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("產生邀請碼失敗: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = inviteCodeAlphabet[int(b)%len(inviteCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// newSeatToken 產生不可預測的座位憑證；憑證僅是重連捷徑，仍須由入座帳號出示
+func newSeatToken() string {
+	return "seat-" + rand.Text()
+}
+
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// checkAccessLocked 驗證新玩家是否能進入房間；持有邀請碼者可略過密碼
+func (r *Room) checkAccessLocked(opts JoinOptions) error {
+	if code := normalizeInviteCode(opts.InviteCode); code != "" && code == r.inviteCode {
+		return nil
+	}
+	if r.settings.Visibility == RoomVisibilityPrivate {
+		return reject("private_room")
+	}
+	if r.password != "" && subtle.ConstantTimeCompare([]byte(opts.Password), []byte(r.password)) != 1 {
+		return reject("wrong_password")
+	}
+	return nil
+}
+
+func (r *Room) buildAccessPayloadLocked() RoomAccessPayload {
+	return RoomAccessPayload{
+		Visibility:  r.settings.Visibility,
+		HasPassword: r.password != "",
+	}
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"zombierush/internal/game"
+	"zombierush/internal/i18n"
+	"zombierush/internal/server/store"
+)
+
+// GameArchive 保存封存中的對局，由 store.Store 實作
+type GameArchive interface {
+	SaveGame(game store.SavedGame) error
+	LoadGame(roomID string) (*store.SavedGame, error)
+	ListGames(userID int64) ([]store.SavedGame, error)
+	DueGames(before time.Time) ([]string, error)
+	DeleteGame(roomID string) error
+}
+
+// savedRoom 為房間與遊戲引擎的完整快照
+type savedRoom struct {
+	Name         string         `json:"name"`
+	Settings     RoomSettings   `json:"settings"`
+	MinHumans    int            `json:"minHumans"`
+	Banned       []int64        `json:"banned,omitempty"`
+	CurrentTurn  int            `json:"currentTurn"`
+	CurrentRound int            `json:"currentRound"`
+	Paused       bool           `json:"paused,omitempty"`
+	TurnDeadline int64          `json:"turnDeadline,omitempty"` // Unix 毫秒
+	Pending      *savedPending  `json:"pending,omitempty"`
+	Seats        []savedSeat    `json:"seats"`
+	Game         game.SavedGame `json:"game"`
+}
+
+// savedPending 為尚待防守的挑戰，僅通信對局會在此狀態下保存；攻擊牌以牌編號保存
+type savedPending struct {
+	AttackerSeat    int           `json:"attackerSeat"`
+	DefenderSeat    int           `json:"defenderSeat"`
+	AttackerCardIDs []int         `json:"attackerCardIds"`
+	AttackKind      game.CardKind `json:"attackKind"`
+	AttackSuit      game.Suit     `json:"attackSuit"`
+	Deadline        int64         `json:"deadline,omitempty"`
+}
+
+type savedSeat struct {
+	Name         string `json:"name"`
+	UserID       int64  `json:"userId,omitempty"`
+	BotName      string `json:"botName,omitempty"`
+	KnownZombies []int  `json:"knownZombies,omitempty"`
+}
+
+// SetArchive 啟用對局封存功能，並開始定期喚醒到期的通信對局；應在 SetClock 之後呼叫
+func (h *Hub) SetArchive(archive GameArchive) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.archive = archive
+	if h.sweepTimer != nil {
+		h.sweepTimer.Stop()
+	}
+	h.sweepTimer = h.clock.AfterFunc(0, h.sweepCorrespondence)
+}
+
+// AdjournRoom 由房主封存進行中的房間並將所有人送回大廳
+func (h *Hub) AdjournRoom(host *Client, room *Room) error {
+	h.mu.Lock()
+	archive := h.archive
+	h.mu.Unlock()
+	if archive == nil {
+		return errArchiveDisabled
+	}
+
+	clients, err := room.adjourn(host, archive)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeRoomLocked(room)
+	h.returnToLobbyLocked(room.id, clients, i18n.M("leave.adjourned"))
+	h.broadcastLobbyLocked()
+	return nil
+}
+
+// SendSavedGames 回傳玩家參與過的封存對局
+func (h *Hub) SendSavedGames(c *Client) error {
+	h.mu.Lock()
+	archive := h.archive
+	h.mu.Unlock()
+	if archive == nil {
+		return errArchiveDisabled
+	}
+	h.saves.waitAll()
+	records, err := archive.ListGames(c.userID)
+	if err != nil {
+		return err
+	}
+	games := make([]SavedGameSummary, 0, len(records))
+	for _, record := range records {
+		var saved savedRoom
+		if err := json.Unmarshal(record.State, &saved); err != nil {
+			continue
+		}
+		players := make([]string, 0, len(saved.Seats))
+		for _, seat := range saved.Seats {
+			players = append(players, seat.Name)
+		}
+		games = append(games, SavedGameSummary{
+			RoomID:  record.RoomID,
+			Name:    record.Name,
+			Round:   saved.CurrentRound,
+			Players: players,
+			SavedAt: record.SavedAt.UnixMilli(),
+		})
+	}
+	c.sendMessage(ServerMessage{Type: "saved_games", Payload: SavedGamesPayload{Games: games}})
+	return nil
+}
+
+// ResumeSavedGame 由原參與者重新開啟封存對局；房間以暫停狀態還原，其餘玩家依帳號回座。
+// 保存紀錄會保留到對局結束，期間再次選取同一場即直接回到已開啟的房間
+func (h *Hub) ResumeSavedGame(c *Client, roomID string) error {
+	if c.currentRoom() != nil {
+		return errAlreadyInRoom
+	}
+	h.mu.Lock()
+	archive := h.archive
+	_, running := h.rooms[roomID]
+	h.mu.Unlock()
+	if archive == nil {
+		return errArchiveDisabled
+	}
+	if running {
+		return h.JoinRoom(roomID, c, JoinOptions{})
+	}
+
+	h.saves.wait(roomID)
+	record, err := archive.LoadGame(roomID)
+	if err != nil {
+		return err
+	}
+	participant := false
+	for _, userID := range record.Participants {
+		if userID == c.userID {
+			participant = true
+			break
+		}
+	}
+	if !participant {
+		return reject("not_participant")
+	}
+	var saved savedRoom
+	if err := json.Unmarshal(record.State, &saved); err != nil {
+		log.Printf("封存對局 %s 資料毀損: %v", roomID, err)
+		return errSavedGameCorrupt
+	}
+	if saved.Settings.Correspondence {
+		// 通信對局不需重新開啟，連線即載入
+		return h.JoinRoom(roomID, c, JoinOptions{})
+	}
+	room, err := restoreRoom(roomID, saved, h, archive)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	if _, exists := h.rooms[roomID]; exists {
+		h.mu.Unlock()
+		room.stop()
+		return h.JoinRoom(roomID, c, JoinOptions{})
+	}
+	code, err := h.newInviteCodeLocked()
+	if err != nil {
+		h.mu.Unlock()
+		room.stop()
+		return err
+	}
+	room.inviteCode = code
+	h.rooms[roomID] = room
+	lobby := make([]*Client, 0, len(h.lobbyClients))
+	for other := range h.lobbyClients {
+		if other != c {
+			lobby = append(lobby, other)
+		}
+	}
+	h.mu.Unlock()
+	room.armIdleRelease()
+
+	for _, other := range lobby {
+		if room.hasParticipant(other.userID) {
+			other.sendMessage(ServerMessage{Type: "saved_game_reopened", Payload: SavedGameSummary{RoomID: roomID, Name: saved.Name, Round: saved.CurrentRound}})
+		}
+	}
+
+	return h.JoinRoom(roomID, c, JoinOptions{InviteCode: code})
+}
+
+// discardSavedGame 於重新開啟的對局結束後移除保存紀錄
+func (h *Hub) discardSavedGame(roomID string) {
+	h.mu.Lock()
+	archive := h.archive
+	h.mu.Unlock()
+	if archive == nil {
+		return
+	}
+	h.saves.wait(roomID)
+	if err := archive.DeleteGame(roomID); err != nil {
+		log.Printf("移除保存對局 %s 失敗: %v", roomID, err)
+	}
+}
+
+// adjourn 在同一個房間指令中暫停對局、寫入保存紀錄並清空房間，回傳需送回大廳的連線；
+// 保存與關閉之間不會插入其他指令，保存失敗時房間維持暫停而不關閉
+func (r *Room) adjourn(host *Client, archive GameArchive) ([]*Client, error) {
+	return attemptWith(r, func() ([]*Client, error) {
+		if err := r.ensureHostLocked(host, "error.host_only.adjourn"); err != nil {
+			return nil, err
+		}
+		if r.status != RoomStatusRunning || r.game == nil {
+			return nil, reject("adjourn_not_running")
+		}
+		if r.pendingChallenge != nil {
+			return nil, reject("adjourn_pending")
+		}
+		if !r.paused {
+			r.pauseLocked()
+		}
+		record, err := r.buildSnapshotLocked()
+		if err != nil {
+			return nil, err
+		}
+		if err := archive.SaveGame(record); err != nil {
+			return nil, err
+		}
+		return r.closeForAdjournLocked(), nil
+	})
+}
+
+// buildSnapshotLocked 序列化房間與遊戲的完整狀態，含計時中的期限與待防守的挑戰
+func (r *Room) buildSnapshotLocked() (store.SavedGame, error) {
+	saved := savedRoom{
+		Name:         r.name,
+		Settings:     r.settings,
+		MinHumans:    r.minHumans,
+		CurrentTurn:  r.currentTurn,
+		CurrentRound: r.currentRound,
+		Paused:       r.paused,
+		TurnDeadline: deadlineMillis(r.turnDeadline),
+		Seats:        make([]savedSeat, len(r.seats)),
+		Game:         r.game.Save(),
+	}
+	if pending := r.pendingChallenge; pending != nil {
+		saved.Pending = &savedPending{
+			AttackerSeat:    pending.AttackerSeat,
+			DefenderSeat:    pending.DefenderSeat,
+			AttackerCardIDs: append([]int(nil), pending.AttackerCardIDs...),
+			AttackKind:      pending.AttackKind,
+			AttackSuit:      pending.AttackSuit,
+			Deadline:        deadlineMillis(pending.deadline),
+		}
+	}
+	for userID := range r.banned {
+		saved.Banned = append(saved.Banned, userID)
+	}
+	participants := make([]int64, 0, len(r.seats))
+	for i, seat := range r.seats {
+		entry := savedSeat{Name: seat.displayBaseName(), UserID: seat.UserID}
+		if seat.UserID != 0 {
+			participants = append(participants, seat.UserID)
+		} else if seat.Bot != nil {
+			entry.BotName = seat.Bot.Name
+			for target := range seat.Bot.KnownZombies {
+				entry.KnownZombies = append(entry.KnownZombies, target)
+			}
+		}
+		saved.Seats[i] = entry
+	}
+	if len(participants) == 0 {
+		return store.SavedGame{}, reject("adjourn_no_humans")
+	}
+
+	state, err := json.Marshal(saved)
+	if err != nil {
+		return store.SavedGame{}, fmt.Errorf("序列化對局失敗: %w", err)
+	}
+	record := store.SavedGame{RoomID: r.id, Name: r.name, State: state, Participants: participants, SavedAt: r.clock.Now().UTC()}
+	if r.settings.Correspondence && !r.paused {
+		record.Deadline = r.nextDeadlineLocked()
+	}
+	return record, nil
+}
+
+// closeForAdjournLocked 清空房間內所有連線並回傳，由呼叫端送回大廳
+func (r *Room) closeForAdjournLocked() []*Client {
+	r.stopTimersLocked()
+	clients := r.evictSpectatorsLocked()
+	for _, seat := range r.seats {
+		if c := seat.Client; c != nil {
+			seat.Client = nil
+			c.setRoom(nil, -1)
+			clients = append(clients, c)
+		}
+	}
+	r.status = RoomStatusFinished
+	r.game = nil
+	return clients
+}
+
+func (r *Room) hasParticipant(userID int64) bool {
+	return query(r, func() bool {
+		for _, seat := range r.seats {
+			if seat.UserID != 0 && seat.UserID == userID {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// restoreRoom 由快照重建房間。封存對局的所有座位先由 AI 佔位並保持暫停，等玩家回座後再繼續；
+// 通信對局的真人座位維持離線，計時器由 rearmCorrespondence 於房間登記後重新啟動。
+// 快照驗證無誤並完成還原後才啟動房間迴圈與發布摘要
+func restoreRoom(id string, saved savedRoom, hub *Hub, archive GameArchive) (*Room, error) {
+	g, err := game.RestoreGame(saved.Game, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(saved.Seats) != len(g.Players) {
+		return nil, errSavedGameCorrupt.withDetail("error.saved_game_corrupt.seats")
+	}
+	if p := saved.Pending; p != nil {
+		if p.AttackerSeat < 0 || p.AttackerSeat >= len(g.Players) {
+			return nil, errSavedGameCorrupt.withDetail("error.saved_game_corrupt.attacker", "seat", p.AttackerSeat)
+		}
+		if p.DefenderSeat < 0 || p.DefenderSeat >= len(g.Players) {
+			return nil, errSavedGameCorrupt.withDetail("error.saved_game_corrupt.defender", "seat", p.DefenderSeat)
+		}
+	}
+	correspondence := saved.Settings.Correspondence
+
+	r := newRoom(id, saved.Name, len(saved.Seats), hub)
+	r.archive = archive
+	r.settings = saved.Settings
+	if !correspondence {
+		r.settings.Visibility = RoomVisibilityPrivate
+	}
+	if saved.MinHumans > 0 {
+		r.minHumans = saved.MinHumans
+	}
+	for _, userID := range saved.Banned {
+		r.banned[userID] = struct{}{}
+	}
+	for i, entry := range saved.Seats {
+		seat := r.seats[i]
+		seat.Name = entry.Name
+		seat.UserID = entry.UserID
+		seat.Player = g.Players[i]
+		if correspondence && entry.UserID != 0 {
+			continue
+		}
+		botName := entry.BotName
+		if botName == "" {
+			botName = i18n.M("name.stand_in", "player", seat.displayBaseName()).String()
+		}
+		bot := &BotPlayer{SeatIndex: i, Name: botName, KnownZombies: make(map[int]struct{})}
+		for _, target := range entry.KnownZombies {
+			bot.KnownZombies[target] = struct{}{}
+		}
+		seat.Bot = bot
+	}
+	r.game = g
+	r.status = RoomStatusRunning
+	r.currentTurn = saved.CurrentTurn
+	r.currentRound = saved.CurrentRound
+	if p := saved.Pending; p != nil {
+		r.pendingChallenge = &pendingChallenge{
+			AttackerSeat:    p.AttackerSeat,
+			DefenderSeat:    p.DefenderSeat,
+			AttackerCardIDs: p.AttackerCardIDs,
+			AttackKind:      p.AttackKind,
+			AttackSuit:      p.AttackSuit,
+		}
+	}
+	r.paused = !correspondence || saved.Paused
+	r.restored = true
+	r.start()
+	return r, nil
+}
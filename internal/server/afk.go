@@ -0,0 +1,66 @@
+package server
+
+import "zombierush/internal/i18n"
+
+const (
+	defaultAFKLimit = 2
+	maxAFKLimit     = 10
+)
+
+// recordMissLocked 累計座位逾時次數，達上限時在保持連線的情況下改由 AI 代打
+func (r *Room) recordMissLocked(seat *Seat) {
+	if seat.Client == nil || seat.Away {
+		return
+	}
+	seat.Missed++
+	limit := r.settings.AFKLimit
+	if limit <= 0 || seat.Missed < limit {
+		return
+	}
+	seat.Away = true
+	seat.Bot = &BotPlayer{SeatIndex: seat.Index, Name: i18n.M("name.stand_in", "player", seat.displayBaseName()).String(), KnownZombies: make(map[int]struct{})}
+	r.sendPrivateInfoLocked(seat.Client, i18n.M("info.afk", "missed", seat.Missed))
+	r.broadcastLogLocked(i18n.M("log.afk", "player", seat.displayName()))
+	r.broadcastLobbyLocked()
+	r.broadcastPublicStateLocked()
+}
+
+// passiveMessages 為不代表玩家親自操作的訊息：state_ack、resync 由客戶端自動送出，其餘只是查詢資料。
+// 除此之外的任何指令（即使被拒絕）都重設逾時次數並交還控制權，否則在線但未操作的玩家永遠不會被判定暫離
+var passiveMessages = map[string]bool{
+	"state_ack":        true,
+	"resync":           true,
+	"lobby_list":       true,
+	"inbox_list":       true,
+	"saved_games_list": true,
+}
+
+// markActive 於玩家送出操作指令時呼叫；重設逾時次數，並在代打中時交還控制權
+func (r *Room) markActive(c *Client) {
+	r.do(func() {
+		seat := r.getSeatLocked(c.seatIndex)
+		if seat == nil || seat.Client != c {
+			return
+		}
+		seat.Missed = 0
+		if !seat.Away {
+			return
+		}
+		seat.Away = false
+		seat.Bot = nil
+		r.broadcastLogLocked(i18n.M("log.afk_returned", "player", seat.displayName()))
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		r.resumeSeatLocked(seat)
+	})
+}
+
+func (r *Room) clearAwayLocked() {
+	for _, seat := range r.seats {
+		if seat.Away {
+			seat.Bot = nil
+		}
+		seat.Away = false
+		seat.Missed = 0
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"math/rand"
 	"sort"
 	"time"
 
@@ -14,12 +15,28 @@ type BotPlayer struct {
 	KnownZombies map[int]struct{}
 }
 
-func (r *Room) executeBotTurn(bot *BotPlayer) {
-	time.Sleep(1200 * time.Millisecond)
+// standInBot 建立暫代真人出牌的機器人，不沿用任何已知情報
+func standInBot(seat *Seat) *BotPlayer {
+	return &BotPlayer{SeatIndex: seat.Index, Name: seat.displayName(), KnownZombies: make(map[int]struct{})}
+}
+
+// botTurnDelay 為機器人出牌前的停頓，讓玩家看得清每一步
+const botTurnDelay = 1200 * time.Millisecond
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// scheduleBotTurnLocked 停頓後在房間迴圈中替機器人出牌
+func (r *Room) scheduleBotTurnLocked(bot *BotPlayer) {
+	r.clock.AfterFunc(botTurnDelay, func() {
+		r.do(func() {
+			// 等待期間座位可能已由真人重連或接手，或對局已暫停
+			if seat := r.getSeatLocked(bot.SeatIndex); seat == nil || seat.Bot != bot || r.paused {
+				return
+			}
+			r.playBotTurnLocked(bot)
+		})
+	})
+}
 
+func (r *Room) playBotTurnLocked(bot *BotPlayer) {
 	if r.status != RoomStatusRunning || r.currentTurn != bot.SeatIndex {
 		return
 	}
@@ -45,6 +62,8 @@ func (r *Room) executeBotTurn(bot *BotPlayer) {
 	var attackSuit game.Suit
 	targetIndex := -1
 
+	difficulty := r.settings.BotDifficulty
+
 	// 優先使用僵屍牌感染未知陣營的玩家
 	if idx := findCardKind(attacker, game.CardKindZombie); idx >= 0 {
 		candidates := make([]int, 0, len(targets))
@@ -89,9 +108,12 @@ func (r *Room) executeBotTurn(bot *BotPlayer) {
 		}
 	}
 
-	// 否則出最高的數字牌
+	// 否則出最高的數字牌；簡單難度改為隨機花色的少量出牌
 	if attackCards == nil {
 		bestSuit, bestIndices := selectStrongestNumericSet(attacker)
+		if difficulty == BotDifficultyEasy {
+			bestSuit, bestIndices = selectCasualNumericSet(attacker, r.rng)
+		}
 		if len(bestIndices) == 0 {
 			// 無牌可出，直接結束回合
 			r.advanceTurnLocked()
@@ -109,31 +131,31 @@ func (r *Room) executeBotTurn(bot *BotPlayer) {
 		return
 	}
 
+	attackIDs := cardIDsAt(attacker, attackCards)
 	if attackKind == game.CardKindNumber && defenderSeat.Player.HasSuit(attackSuit) {
 		if defenderSeat.Bot != nil {
-			defense := selectBotDefenseIndices(defenderSeat.Player, attackSuit, len(attackCards))
-			_ = r.resolveChallengeLocked(seat.Index, defenderSeat.Index, attackCards, defense)
+			defense := r.selectBotDefenseLocked(defenderSeat.Player, attackSuit, len(attackCards))
+			_ = r.resolveChallengeLocked(seat.Index, defenderSeat.Index, attackIDs, cardIDsAt(defenderSeat.Player, defense))
 		} else {
 			// 交由真人防守
-			r.pendingChallenge = &pendingChallenge{
-				AttackerSeat:  seat.Index,
-				DefenderSeat:  defenderSeat.Index,
-				AttackerCards: attackCards,
-				AttackKind:    game.CardKindNumber,
-				AttackSuit:    attackSuit,
-			}
-			r.sendDefensePromptLocked(
-				defenderSeat,
-				collectSuitOptions(defenderSeat.Player, attackSuit),
-				buildCardViewsFromIndices(attacker, attackCards),
-				&attackSuit,
-			)
-			r.broadcastPublicStateLocked()
+			r.beginDefenseLocked(seat, defenderSeat, attackIDs, attackSuit)
 		}
 		return
 	}
 
-	_ = r.resolveChallengeLocked(seat.Index, defenderSeat.Index, attackCards, nil)
+	_ = r.resolveChallengeLocked(seat.Index, defenderSeat.Index, attackIDs, nil)
+}
+
+// selectBotDefenseLocked 依房間設定的難度決定防守張數：簡單只出一張，困難出滿五張
+func (r *Room) selectBotDefenseLocked(player *game.Player, suit game.Suit, attackCount int) []int {
+	limit := attackCount
+	switch r.settings.BotDifficulty {
+	case BotDifficultyEasy:
+		limit = 1
+	case BotDifficultyHard:
+		limit = 5
+	}
+	return selectBotDefenseIndices(player, suit, limit)
 }
 
 func findCardKind(player *game.Player, kind game.CardKind) int {
@@ -145,18 +167,25 @@ func findCardKind(player *game.Player, kind game.CardKind) int {
 	return -1
 }
 
+// selectStrongestNumericSet 挑出點數總和最高的花色，最多五張；同分時取手牌中先出現的花色，
+// 同一手牌與種子下機器人的選擇固定
 func selectStrongestNumericSet(player *game.Player) (game.Suit, []int) {
 	suitMap := make(map[game.Suit][]int)
+	suitsHeld := make([]game.Suit, 0)
 	for idx, card := range player.Hand {
 		if card.Kind != game.CardKindNumber {
 			continue
 		}
+		if _, ok := suitMap[card.Suit]; !ok {
+			suitsHeld = append(suitsHeld, card.Suit)
+		}
 		suitMap[card.Suit] = append(suitMap[card.Suit], idx)
 	}
 	var bestSuit game.Suit
 	bestScore := -1
 	bestIndices := []int{}
-	for suit, indices := range suitMap {
+	for _, suit := range suitsHeld {
+		indices := suitMap[suit]
 		sort.SliceStable(indices, func(i, j int) bool {
 			return player.Hand[indices[i]].Value > player.Hand[indices[j]].Value
 		})
@@ -178,3 +207,27 @@ func selectStrongestNumericSet(player *game.Player) (game.Suit, []int) {
 	sort.Ints(bestIndices)
 	return bestSuit, bestIndices
 }
+
+// selectCasualNumericSet 隨機挑一個花色，最多出兩張
+func selectCasualNumericSet(player *game.Player, rng *rand.Rand) (game.Suit, []int) {
+	suitMap := make(map[game.Suit][]int)
+	suitsHeld := make([]game.Suit, 0)
+	for idx, card := range player.Hand {
+		if card.Kind != game.CardKindNumber {
+			continue
+		}
+		if _, ok := suitMap[card.Suit]; !ok {
+			suitsHeld = append(suitsHeld, card.Suit)
+		}
+		suitMap[card.Suit] = append(suitMap[card.Suit], idx)
+	}
+	if len(suitsHeld) == 0 {
+		return "", nil
+	}
+	suit := suitsHeld[rng.Intn(len(suitsHeld))]
+	indices := suitMap[suit]
+	if len(indices) > 2 {
+		indices = indices[:2]
+	}
+	return suit, append([]int(nil), indices...)
+}
@@ -5,9 +5,13 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"zombierush/internal/game"
+	"zombierush/internal/i18n"
 )
 
 const (
@@ -19,17 +23,39 @@ const (
 
 // Client 封裝一位連線玩家
 type Client struct {
-	conn      *websocket.Conn
-	hub       *Hub
-	room      *Room
+	conn *websocket.Conn
+	hub  *Hub
+
+	// mu 保護 room 與請求狀態：房間迴圈寫入 room，連線的讀取迴圈與 Hub 讀取
+	mu   sync.Mutex
+	room *Room
+	// seatIndex 只由所在房間的迴圈讀寫
 	seatIndex int
+
 	name      string
 	account   string
 	userID    int64
 	token     string
-	inLobby   bool
-	send      chan []byte
 	closeOnce sync.Once
+	// outbox 為送出佇列；overflowed 表示連線因佇列持續壅塞而被關閉，只計入一次統計
+	outbox     *outbox
+	overflowed atomic.Bool
+
+	// requestID 為目前處理中指令的請求 ID；指令回傳的錯誤記入 requestError 並改以 nack 回覆，受 mu 保護
+	requestID    string
+	requestError error
+
+	// locale 為此連線的語系，房間依此呈現紀錄與錯誤訊息
+	locale i18n.Locale
+
+	// protocol 為連線時協商的協定版本；lastSeq 為最後送給此連線的房間訊息序號，只在所在房間的迴圈中讀寫
+	protocol int
+	lastSeq  int64
+
+	// publicAcked 與 lobbyAcked 為客戶端確認持有的房間公開狀態與大廳列表版本，
+	// 前者只在所在房間的迴圈中讀寫，後者受 Hub 鎖保護
+	publicAcked int64
+	lobbyAcked  int64
 }
 
 // NewWebClient 建立客戶端
@@ -38,7 +64,7 @@ func NewWebClient(conn *websocket.Conn, hub *Hub, userID int64, account, display
 	if name == "" {
 		name = strings.TrimSpace(account)
 		if name == "" {
-			name = "玩家"
+			name = i18n.M("name.player").String()
 		}
 	}
 	if len(name) > 24 {
@@ -53,11 +79,61 @@ func NewWebClient(conn *websocket.Conn, hub *Hub, userID int64, account, display
 		account:   account,
 		userID:    userID,
 		token:     seatToken,
-		send:      make(chan []byte, 256),
-		inLobby:   true,
+		outbox:    newOutbox(hub.currentClock()),
+		locale:    i18n.Default,
+		protocol:  MinProtocolVersion,
 	}
 }
 
+// SetLocale 設定連線的語系，需在開始讀寫前呼叫
+func (c *Client) SetLocale(loc i18n.Locale) {
+	c.locale = loc
+}
+
+// currentRoom 回傳連線目前所在的房間
+func (c *Client) currentRoom() *Room {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.room
+}
+
+// setRoom 更新連線所在的房間與座位，只在房間迴圈中呼叫
+func (c *Client) setRoom(r *Room, seatIndex int) {
+	c.mu.Lock()
+	c.room = r
+	c.mu.Unlock()
+	c.seatIndex = seatIndex
+}
+
+// reserveRoom 於連線不在任何房間時將其標記為屬於 r 並回傳真；檢查與標記在同一把鎖內完成，
+// 配對與玩家自己的指令同時加入不同房間時只有一方成功
+func (c *Client) reserveRoom(r *Room) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.room != nil {
+		return false
+	}
+	c.room = r
+	return true
+}
+
+// releaseRoom 撤銷加入失敗時 reserveRoom 的標記
+func (c *Client) releaseRoom(r *Room) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.room == r {
+		c.room = nil
+	}
+}
+
+// localeOf 回傳連線的語系；座位沒有連線（例如機器人）時使用預設語系
+func localeOf(c *Client) i18n.Locale {
+	if c == nil {
+		return i18n.Default
+	}
+	return c.locale
+}
+
 func (c *Client) ReadPump() {
 	defer c.close()
 	c.conn.SetReadLimit(maxMessageSize)
@@ -83,30 +159,33 @@ func (c *Client) ReadPump() {
 }
 
 func (c *Client) WritePump() {
-	ticker := time.NewTicker(pingPeriod)
+	// ping 週期走 Hub 的時鐘；連線讀寫期限由 websocket 套件依系統時間判斷，維持使用 time.Now
+	ticker := c.hub.currentClock().NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.close()
 	}()
 	for {
 		select {
-		case message, ok := <-c.send:
-			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if !ok {
-				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
-			}
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			if _, err := w.Write(message); err != nil {
-				return
+		case <-c.outbox.ready:
+			messages, closed := c.outbox.take()
+			for _, msg := range messages {
+				data, err := json.Marshal(msg)
+				if err != nil {
+					continue
+				}
+				_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					return
+				}
 			}
-			if err := w.Close(); err != nil {
+			if closed {
+				_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				_ = c.conn.Close()
 				return
 			}
-		case <-ticker.C:
+		case <-ticker.C():
 			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
@@ -116,6 +195,18 @@ func (c *Client) WritePump() {
 }
 
 func (c *Client) handleMessage(msg ClientMessage) {
+	if room := c.currentRoom(); room != nil && !passiveMessages[msg.Type] {
+		room.markActive(c)
+	}
+	if msg.RequestID != "" {
+		c.handleRequest(msg)
+		return
+	}
+	c.dispatch(msg)
+}
+
+func (c *Client) dispatch(msg ClientMessage) {
+	room := c.currentRoom()
 	switch msg.Type {
 	case "lobby_list":
 		c.hub.RegisterLobbyClient(c)
@@ -125,14 +216,12 @@ func (c *Client) handleMessage(msg ClientMessage) {
 			c.sendError(err)
 			return
 		}
-		if payload.Name == "" {
-			payload.Name = "未命名房間"
-		}
-		if c.room != nil {
-			c.sendErrorErr("請先離開目前房間")
+		if room != nil {
+			c.sendError(errAlreadyInRoom)
 			return
 		}
-		if _, err := c.hub.CreateRoom(payload.Name, c); err != nil {
+		opts := RoomOptions{Private: payload.Private, Password: strings.TrimSpace(payload.Password)}
+		if _, err := c.hub.CreateRoom(payload.Name, c, opts); err != nil {
 			c.sendError(err)
 		}
 	case "room_join":
@@ -141,70 +230,368 @@ func (c *Client) handleMessage(msg ClientMessage) {
 			c.sendError(err)
 			return
 		}
-		if payload.RoomID == "" {
-			c.sendErrorErr("缺少房間 ID")
+		if payload.RoomID == "" && payload.InviteCode == "" {
+			c.sendError(errRoomRequired)
 			return
 		}
-		if c.room != nil {
-			c.sendErrorErr("請先離開目前房間")
+		if room != nil {
+			c.sendError(errAlreadyInRoom)
 			return
 		}
-		if err := c.hub.JoinRoom(payload.RoomID, c); err != nil {
+		opts := JoinOptions{InviteCode: payload.InviteCode, Password: payload.Password, Seat: payload.Seat}
+		if err := c.hub.JoinRoom(payload.RoomID, c, opts); err != nil {
+			c.sendError(err)
+		}
+	case "queue_join":
+		var payload QueueJoinPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		prefs := MatchPreferences{Variant: game.Variant(payload.Variant), Rated: payload.Rated, AllowBots: true}
+		if payload.AllowBots != nil {
+			prefs.AllowBots = *payload.AllowBots
+		}
+		if err := c.hub.Enqueue(c, prefs); err != nil {
+			c.sendError(err)
+		}
+	case "queue_cancel":
+		c.hub.CancelQueue(c)
+	case "room_spectate":
+		var payload SpectatePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.RoomID == "" && payload.InviteCode == "" {
+			c.sendError(errRoomRequired)
+			return
+		}
+		if room != nil {
+			c.sendError(errAlreadyInRoom)
+			return
+		}
+		opts := JoinOptions{InviteCode: payload.InviteCode, Password: payload.Password}
+		if err := c.hub.SpectateRoom(payload.RoomID, c, opts); err != nil {
+			c.sendError(err)
+		}
+	case "room_settings":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload RoomSettingsPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		evicted, err := room.applySettings(c, payload)
+		if err != nil {
+			c.sendError(err)
+			return
+		}
+		c.hub.returnToLobby(room.id, evicted, i18n.M("leave.spectators_closed"))
+		c.hub.refreshLobby()
+	case "state_ack":
+		var payload StateAckPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		c.handleStateAck(payload)
+	case "resync":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		if err := room.resync(c); err != nil {
 			c.sendError(err)
 		}
 	case "room_leave":
 		c.hub.LeaveRoom(c)
-		c.hub.RegisterLobbyClient(c)
 	case "room_add_bot":
-		if c.room == nil {
-			c.sendErrorErr("尚未加入房間")
-			return
-		}
-		if !c.room.isHost(c) {
-			c.sendErrorErr("僅房主可新增機器人")
+		if room == nil {
+			c.sendError(errNotInRoom)
 			return
 		}
 		var payload BotCommandPayload
 		_ = json.Unmarshal(msg.Payload, &payload)
-		if _, err := c.room.addBot(payload.Name); err != nil {
+		if _, err := room.addBot(c, payload.Name); err != nil {
 			c.sendError(err)
 		}
 	case "room_remove_bot":
-		if c.room == nil {
-			c.sendErrorErr("尚未加入房間")
+		if room == nil {
+			c.sendError(errNotInRoom)
 			return
 		}
-		if !c.room.isHost(c) {
-			c.sendErrorErr("僅房主可移除機器人")
+		var payload BotCommandPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
 			return
 		}
-		var payload BotCommandPayload
+		if payload.Seat == nil {
+			c.sendError(errSeatRequired)
+			return
+		}
+		if err := room.removeBot(c, *payload.Seat); err != nil {
+			c.sendError(err)
+		}
+	case "room_kick", "room_ban":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload SeatTargetPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.Seat == nil {
+			c.sendError(errSeatRequired)
+			return
+		}
+		ban := msg.Type == "room_ban"
+		target, err := room.kickSeat(c, *payload.Seat, ban)
+		if err != nil {
+			c.sendError(err)
+			return
+		}
+		reason := i18n.M("leave.kicked")
+		if ban {
+			reason = i18n.M("leave.banned")
+		}
+		c.hub.returnToLobby(room.id, []*Client{target}, reason)
+	case "room_transfer_host":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload SeatTargetPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.Seat == nil {
+			c.sendError(errSeatRequired)
+			return
+		}
+		if err := room.transferHost(c, *payload.Seat); err != nil {
+			c.sendError(err)
+		}
+	case "room_lock":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload LockRoomPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if err := room.setLocked(c, payload.Locked); err != nil {
+			c.sendError(err)
+			return
+		}
+		c.hub.refreshLobby()
+	case "seat_choose", "seat_swap_request":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload SeatTargetPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.Seat == nil {
+			c.sendError(errSeatRequired)
+			return
+		}
+		var err error
+		if msg.Type == "seat_choose" {
+			err = room.chooseSeat(c, *payload.Seat)
+		} else {
+			err = room.requestSwap(c, *payload.Seat)
+		}
+		if err != nil {
+			c.sendError(err)
+		}
+	case "seat_swap_response":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload SeatResponsePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.Seat == nil {
+			c.sendError(errSeatRequired)
+			return
+		}
+		if err := room.respondSwap(c, *payload.Seat, payload.Accept); err != nil {
+			c.sendError(err)
+		}
+	case "seat_claim":
+		var payload SeatClaimPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.Seat == nil {
+			c.sendError(errSeatRequired)
+			return
+		}
+		roomID := payload.RoomID
+		if room != nil {
+			roomID = room.id
+		}
+		if roomID == "" && payload.InviteCode == "" {
+			c.sendError(errRoomRequired)
+			return
+		}
+		opts := JoinOptions{InviteCode: payload.InviteCode, Password: payload.Password}
+		if err := c.hub.RequestSeatClaim(roomID, c, *payload.Seat, opts); err != nil {
+			c.sendError(err)
+		}
+	case "seat_claim_response":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload SeatResponsePayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 			c.sendError(err)
 			return
 		}
 		if payload.Seat == nil {
-			c.sendErrorErr("請指定座位")
+			c.sendError(errSeatRequired)
 			return
 		}
-		if err := c.room.removeBot(*payload.Seat); err != nil {
+		claimant, err := room.resolveClaim(c, *payload.Seat, payload.Accept)
+		if err != nil {
+			c.sendError(err)
+			return
+		}
+		if claimant != nil {
+			c.hub.unregisterLobbyClient(claimant)
+		}
+	case "seat_shuffle":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		if err := room.shuffleSeats(c); err != nil {
+			c.sendError(err)
+		}
+	case "room_ready":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload ReadyPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if err := room.setReady(c, payload.Ready); err != nil {
+			c.sendError(err)
+		}
+	case "room_min_humans":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		var payload MinHumansPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if err := room.setMinHumans(c, payload.MinHumans); err != nil {
 			c.sendError(err)
 		}
 	case "start_game":
-		if c.room == nil {
-			c.sendErrorErr("尚未加入房間")
+		if room == nil {
+			c.sendError(errNotInRoom)
 			return
 		}
-		if !c.room.isHost(c) {
-			c.sendErrorErr("僅房主可開始對戰")
+		var payload StartGamePayload
+		_ = json.Unmarshal(msg.Payload, &payload)
+		if err := room.StartGame(c, payload.Force); err != nil {
+			c.sendError(err)
+		}
+	case "rematch_vote":
+		if room == nil {
+			c.sendError(errNotInRoom)
 			return
 		}
-		if err := c.room.StartGame(); err != nil {
+		var payload RematchVotePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if err := room.castRematchVote(c, payload.Vote); err != nil {
+			c.sendError(err)
+		}
+	case "rematch_start":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		departed, err := room.startRematch(c)
+		c.hub.returnToLobby(room.id, departed, i18n.M("leave.rematch_declined"))
+		if err != nil {
+			c.sendError(err)
+		}
+	case "rematch_lobby":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		if err := room.returnToLobby(c); err != nil {
+			c.sendError(err)
+		}
+	case "game_pause", "game_resume":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		if err := room.votePause(c, msg.Type == "game_pause"); err != nil {
+			c.sendError(err)
+		}
+	case "game_adjourn":
+		if room == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		if err := c.hub.AdjournRoom(c, room); err != nil {
+			c.sendError(err)
+		}
+	case "saved_games_list":
+		if err := c.hub.SendSavedGames(c); err != nil {
+			c.sendError(err)
+		}
+	case "inbox_list":
+		if err := c.hub.SendInbox(c); err != nil {
+			c.sendError(err)
+		}
+	case "saved_game_resume":
+		var payload SavedGameTargetPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.sendError(err)
+			return
+		}
+		if payload.RoomID == "" {
+			c.sendError(errRoomRequired.withDetail("error.room_required.id"))
+			return
+		}
+		if err := c.hub.ResumeSavedGame(c, payload.RoomID); err != nil {
 			c.sendError(err)
 		}
 	case "action_challenge":
-		if c.room == nil {
-			c.sendErrorErr("尚未加入房間")
+		if room == nil {
+			c.sendError(errNotInRoom)
 			return
 		}
 		var payload ChallengePayload
@@ -212,12 +599,12 @@ func (c *Client) handleMessage(msg ClientMessage) {
 			c.sendError(err)
 			return
 		}
-		if err := c.room.handleChallenge(c, payload); err != nil {
+		if err := room.handleChallenge(c, payload); err != nil {
 			c.sendError(err)
 		}
 	case "action_defense":
-		if c.room == nil {
-			c.sendErrorErr("尚未加入房間")
+		if room == nil {
+			c.sendError(errNotInRoom)
 			return
 		}
 		var payload DefensePayload
@@ -225,41 +612,46 @@ func (c *Client) handleMessage(msg ClientMessage) {
 			c.sendError(err)
 			return
 		}
-		if err := c.room.handleDefenseResponse(c, payload); err != nil {
+		if err := room.handleDefenseResponse(c, payload); err != nil {
 			c.sendError(err)
 		}
 	default:
-		c.sendErrorErr("未知指令")
+		c.sendError(errUnknownCommand)
 	}
 }
 
+// SendError 將錯誤排入送出佇列，供連線建立時加入房間失敗等指令以外的錯誤使用
+func (c *Client) SendError(err error) {
+	c.sendError(err)
+}
+
 func (c *Client) sendError(err error) {
-	if err == nil {
+	if err == nil || c.recordRequestError(err) {
 		return
 	}
-	c.sendMessage(ServerMessage{Type: "error", Payload: ErrorPayload{Message: err.Error()}})
+	c.sendMessage(ServerMessage{Type: "error", Payload: NewErrorPayload(err, c.locale)})
 }
 
-func (c *Client) sendErrorErr(msg string) {
-	c.sendMessage(ServerMessage{Type: "error", Payload: ErrorPayload{Message: msg}})
+func (c *Client) sendMessage(msg ServerMessage) {
+	c.enqueue(msg)
 }
 
-func (c *Client) sendMessage(msg ServerMessage) {
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return
-	}
-	select {
-	case c.send <- data:
-	default:
-		go c.close()
-	}
+// closeGracefully 停止接收新訊息，由 WritePump 送完已排入的訊息後關閉連線
+func (c *Client) closeGracefully() {
+	c.closeOnce.Do(func() {
+		c.outbox.close()
+		if c.hub != nil {
+			c.hub.RemoveClient(c)
+		}
+	})
 }
 
 func (c *Client) close() {
 	c.closeOnce.Do(func() {
-		close(c.send)
-		_ = c.conn.Close()
+		c.outbox.close()
+		if c.conn != nil {
+			_ = c.conn.Close()
+		}
 		if c.hub != nil {
 			c.hub.RemoveClient(c)
 		}
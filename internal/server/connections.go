@@ -0,0 +1,75 @@
+package server
+
+import "zombierush/internal/i18n"
+
+// Connect 登記新連線。同一帳號同時只保留一條連線：舊分頁的座位或觀戰位置由新連線接手，
+// 舊分頁收到 superseded 通知後關閉。回傳真表示新連線已回到房間，不需再依網址加入
+func (h *Hub) Connect(c *Client) bool {
+	h.mu.Lock()
+	old := h.accounts[c.userID]
+	h.accounts[c.userID] = c
+	if old != nil {
+		delete(h.lobbyClients, old)
+		if h.removeFromQueueLocked(old) {
+			h.sendQueueStatusLocked()
+		}
+	}
+	h.mu.Unlock()
+
+	resumed := false
+	if old != nil {
+		if room := old.currentRoom(); room != nil {
+			resumed = room.handOver(old, c)
+		}
+		old.sendMessage(ServerMessage{Type: "superseded", Payload: LogPayload{Message: i18n.M("info.superseded").Localize(old.locale)}})
+		old.closeGracefully()
+	}
+	if !resumed {
+		h.RegisterLobbyClient(c)
+	}
+	return resumed
+}
+
+// forgetConnectionLocked 於連線結束時移除帳號登記；已被新連線取代者不影響登記
+func (h *Hub) forgetConnectionLocked(c *Client) {
+	if h.accounts[c.userID] == c {
+		delete(h.accounts, c.userID)
+	}
+}
+
+// handOver 將舊連線的座位或觀戰位置轉給同帳號的新連線
+func (r *Room) handOver(old, c *Client) bool {
+	return query(r, func() bool {
+		if r.isSpectatorLocked(old) {
+			delete(r.spectators, old)
+			old.setRoom(nil, -1)
+			r.spectators[c] = struct{}{}
+			c.setRoom(r, -1)
+			r.sendWelcomeLocked(c)
+			r.broadcastPublicStateLocked()
+			return true
+		}
+		seat := r.getSeatLocked(old.seatIndex)
+		if seat == nil || seat.Client != old {
+			return false
+		}
+		r.dropClaimsByLocked(old)
+		seat.Client = nil
+		old.setRoom(nil, -1)
+		r.reconnectSeatLocked(c, seat)
+		return true
+	})
+}
+
+// seatByUserLocked 回傳帳號在此房間持有的座位，確保每個帳號至多一個座位
+func (r *Room) seatByUserLocked(userID int64) *Seat {
+	if userID == 0 {
+		return nil
+	}
+	for _, seat := range r.seats {
+		if seat.UserID == userID {
+			return seat
+		}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"zombierush/internal/i18n"
+)
+
+// persistLocked 於通信對局每次輪替或提出挑戰後排入最新狀態的寫入，並提醒需要處理的玩家
+func (r *Room) persistLocked() {
+	if !r.settings.Correspondence || r.archive == nil || r.status != RoomStatusRunning || r.game == nil {
+		return
+	}
+	if err := r.saveLocked(); err != nil {
+		log.Printf("保存通信對局 %s 失敗: %v", r.id, err)
+		return
+	}
+
+	waiting := r.getSeatLocked(r.currentTurn)
+	if pending := r.pendingChallenge; pending != nil {
+		waiting = r.getSeatLocked(pending.DefenderSeat)
+	}
+	if waiting != nil && waiting.Bot == nil && waiting.UserID != 0 {
+		userID := waiting.UserID
+		r.hub.background(func() { r.hub.notifyInbox(userID) })
+	}
+}
+
+// saveLocked 建立快照並交給背景寫入，不等候資料庫
+func (r *Room) saveLocked() error {
+	record, err := r.buildSnapshotLocked()
+	if err != nil {
+		return err
+	}
+	r.hub.saveGame(r.archive, record)
+	return nil
+}
+
+// nextDeadlineLocked 回傳下次需要由系統代為行動的時間；輪到機器人或尚未計時的座位時為現在
+func (r *Room) nextDeadlineLocked() time.Time {
+	deadline := r.turnDeadline
+	if pending := r.pendingChallenge; pending != nil {
+		deadline = pending.deadline
+	}
+	if deadline.IsZero() {
+		return r.clock.Now()
+	}
+	return deadline
+}
+
+// rearmCorrespondence 依保存的期限重新啟動回合或防守計時；期限已過者會立即由系統代為行動
+func (r *Room) rearmCorrespondence(saved savedRoom) {
+	r.do(func() {
+		if r.status != RoomStatusRunning || r.paused {
+			return
+		}
+		if pending := r.pendingChallenge; pending != nil {
+			if seat := r.getSeatLocked(pending.DefenderSeat); seat != nil && seat.Bot != nil {
+				r.autoDefendLocked()
+				return
+			}
+			if p := saved.Pending; p == nil || p.Deadline == 0 {
+				r.armDefenseTimerLocked(pending)
+			} else {
+				r.armDefenseTimerUntilLocked(pending, time.UnixMilli(p.Deadline))
+			}
+			return
+		}
+
+		seat := r.getSeatLocked(r.currentTurn)
+		if seat == nil {
+			return
+		}
+		if seat.Bot != nil || saved.TurnDeadline == 0 {
+			r.notifyTurnLocked()
+			return
+		}
+		r.armTurnTimerUntilLocked(seat, time.UnixMilli(saved.TurnDeadline))
+	})
+}
+
+// unload 於通信對局無人連線時保存並停止計時，回傳真表示可自 Hub 移除
+func (r *Room) unload() bool {
+	return query(r, func() bool {
+		if !r.settings.Correspondence || r.status == RoomStatusLobby || len(r.spectators) > 0 {
+			return false
+		}
+		for _, seat := range r.seats {
+			if seat.Client != nil {
+				return false
+			}
+		}
+		if r.status == RoomStatusRunning && r.archive != nil {
+			if err := r.saveLocked(); err != nil {
+				log.Printf("保存通信對局 %s 失敗: %v", r.id, err)
+				return false
+			}
+		}
+		r.stopTimersLocked()
+		return true
+	})
+}
+
+// releaseIdleRoom 移除無人房間，仍在觀戰的玩家一併送回大廳；通信對局保存後卸載，待玩家連線時再載入。
+// 呼叫時不得持有 Hub 鎖
+func (h *Hub) releaseIdleRoom(room *Room) {
+	if room.unload() {
+		h.mu.Lock()
+		h.removeRoomLocked(room)
+		h.mu.Unlock()
+		return
+	}
+	if !room.isEmpty() {
+		return
+	}
+	spectators := room.evictSpectators()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeRoomLocked(room)
+	h.returnToLobbyLocked(room.id, spectators, i18n.M("leave.room_closed"))
+}
+
+// loadCorrespondenceRoom 由保存紀錄載入尚未在記憶體中的通信對局
+func (h *Hub) loadCorrespondenceRoom(roomID string) (*Room, bool) {
+	h.mu.Lock()
+	archive := h.archive
+	h.mu.Unlock()
+	if archive == nil {
+		return nil, false
+	}
+	h.saves.wait(roomID)
+	record, err := archive.LoadGame(roomID)
+	if err != nil {
+		return nil, false
+	}
+	var saved savedRoom
+	if err := json.Unmarshal(record.State, &saved); err != nil || !saved.Settings.Correspondence {
+		return nil, false
+	}
+	room, err := restoreRoom(roomID, saved, h, archive)
+	if err != nil {
+		log.Printf("載入通信對局 %s 失敗: %v", roomID, err)
+		return nil, false
+	}
+
+	h.mu.Lock()
+	if existing, ok := h.rooms[roomID]; ok {
+		h.mu.Unlock()
+		room.stop()
+		return existing, true
+	}
+	code, err := h.newInviteCodeLocked()
+	if err != nil {
+		h.mu.Unlock()
+		room.stop()
+		return nil, false
+	}
+	room.inviteCode = code
+	h.rooms[roomID] = room
+	h.mu.Unlock()
+
+	room.rearmCorrespondence(saved)
+	room.armIdleRelease()
+	return room, true
+}
+
+// sweepCorrespondence 載入期限已到、卻因無人連線而不在記憶體中的通信對局，讓逾時照常由系統代為行動；
+// 對局處理完畢後若仍無人連線，會在閒置逾時後再次卸載
+func (h *Hub) sweepCorrespondence() {
+	h.mu.Lock()
+	archive := h.archive
+	now := h.clock.Now()
+	h.sweepTimer = h.clock.AfterFunc(correspondenceSweepInterval, h.sweepCorrespondence)
+	h.mu.Unlock()
+	if archive == nil {
+		return
+	}
+
+	h.saves.waitAll()
+	roomIDs, err := archive.DueGames(now)
+	if err != nil {
+		log.Printf("查詢到期的通信對局失敗: %v", err)
+		return
+	}
+	for _, roomID := range roomIDs {
+		if _, loaded := h.RoomByID(roomID); loaded {
+			continue
+		}
+		h.loadCorrespondenceRoom(roomID)
+	}
+}
+
+// Inbox 列出玩家在通信對局中輪到行動或正被挑戰的事項
+func (h *Hub) Inbox(userID int64) ([]InboxItem, error) {
+	h.mu.Lock()
+	archive := h.archive
+	h.mu.Unlock()
+	if archive == nil {
+		return nil, errArchiveDisabled
+	}
+	h.saves.waitAll()
+	records, err := archive.ListGames(userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]InboxItem, 0)
+	for _, record := range records {
+		var saved savedRoom
+		if err := json.Unmarshal(record.State, &saved); err != nil || !saved.Settings.Correspondence || saved.Paused {
+			continue
+		}
+		item := InboxItem{RoomID: record.RoomID, RoomName: record.Name, Round: saved.CurrentRound}
+		if p := saved.Pending; p != nil {
+			if !seatOwnedBy(saved.Seats, p.DefenderSeat, userID) {
+				continue
+			}
+			item.Kind = InboxKindChallenge
+			item.Deadline = p.Deadline
+			if p.AttackerSeat >= 0 && p.AttackerSeat < len(saved.Seats) {
+				item.Attacker = saved.Seats[p.AttackerSeat].Name
+			}
+		} else {
+			if !seatOwnedBy(saved.Seats, saved.CurrentTurn, userID) {
+				continue
+			}
+			item.Kind = InboxKindTurn
+			item.Deadline = saved.TurnDeadline
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func seatOwnedBy(seats []savedSeat, idx int, userID int64) bool {
+	return idx >= 0 && idx < len(seats) && seats[idx].UserID == userID
+}
+
+// SendInbox 回傳玩家的通信對局待辦事項
+func (h *Hub) SendInbox(c *Client) error {
+	items, err := h.Inbox(c.userID)
+	if err != nil {
+		return err
+	}
+	c.sendMessage(ServerMessage{Type: "inbox", Payload: InboxPayload{Items: items}})
+	return nil
+}
+
+// notifyInbox 將最新待辦事項推送給該帳號在大廳中的連線
+func (h *Hub) notifyInbox(userID int64) {
+	h.mu.Lock()
+	var targets []*Client
+	for c := range h.lobbyClients {
+		if c.userID == userID {
+			targets = append(targets, c)
+		}
+	}
+	h.mu.Unlock()
+	if len(targets) == 0 {
+		return
+	}
+	items, err := h.Inbox(userID)
+	if err != nil {
+		return
+	}
+	for _, c := range targets {
+		c.sendMessage(ServerMessage{Type: "inbox", Payload: InboxPayload{Items: items}})
+	}
+}
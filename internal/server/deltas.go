@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"zombierush/internal/jsonpatch"
+)
+
+const (
+	// protocolStatePatches 為開始支援狀態差異的協定版本
+	protocolStatePatches = 3
+	// maxStateHistory 為保留作為差異基準的版本數；客戶端確認的版本過舊時改送完整狀態
+	maxStateHistory = 32
+)
+
+// stateHistory 保存最近送出的某類狀態（房間公開狀態或大廳房間列表），作為計算差異的基準
+type stateHistory struct {
+	kind    string
+	version int64
+	last    []byte
+	docs    map[int64]any
+}
+
+func newStateHistory(kind string) *stateHistory {
+	return &stateHistory{kind: kind, docs: make(map[int64]any)}
+}
+
+// stateUpdate 為一次狀態送出：完整內容只編碼一次，差異依收訊者確認的基準版本快取共用。
+// 收訊者持有確認過的基準版本，因此較新的完整狀態或差異都能取代佇列中尚未送出的舊版本
+type stateUpdate struct {
+	history *stateHistory
+	version int64
+	full    json.RawMessage
+	patches map[int64]json.RawMessage
+}
+
+// record 登記最新狀態；內容與上一版相同時沿用原版本，不會擠掉其他連線的基準
+func (h *stateHistory) record(payload any) (*stateUpdate, error) {
+	full, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if h.version == 0 || !bytes.Equal(full, h.last) {
+		var doc any
+		if err := json.Unmarshal(full, &doc); err != nil {
+			return nil, err
+		}
+		h.version++
+		h.docs[h.version] = doc
+		delete(h.docs, h.version-maxStateHistory)
+		h.last = full
+	}
+	return &stateUpdate{history: h, version: h.version, full: full, patches: make(map[int64]json.RawMessage)}, nil
+}
+
+// ack 記錄客戶端確認持有的版本；只接受尚在歷史中的較新版本
+func (h *stateHistory) ack(acked *int64, version int64) {
+	if version > *acked && version <= h.version {
+		*acked = version
+	}
+}
+
+// fullMessage 回傳帶有版本的完整狀態
+func (u *stateUpdate) fullMessage() ServerMessage {
+	return ServerMessage{Type: u.history.kind, Version: u.version, Payload: u.full, supersedes: u.history.kind}
+}
+
+// messageFor 依收訊者確認的版本送出差異；舊協定、尚未確認或差異不比完整狀態小時送完整狀態。
+// 收訊者已確認持有最新版本時回傳 false，不需送出
+func (u *stateUpdate) messageFor(c *Client, acked int64) (ServerMessage, bool) {
+	if c.protocol < protocolStatePatches || acked == 0 {
+		return u.fullMessage(), true
+	}
+	if acked == u.version {
+		return ServerMessage{}, false
+	}
+	patch, ok := u.patches[acked]
+	if !ok {
+		patch = u.buildPatch(acked)
+		u.patches[acked] = patch
+	}
+	if patch == nil {
+		return u.fullMessage(), true
+	}
+	return ServerMessage{Type: "state_patch", Version: u.version, Payload: patch, supersedes: u.history.kind}, true
+}
+
+func (u *stateUpdate) buildPatch(base int64) json.RawMessage {
+	from, ok := u.history.docs[base]
+	if !ok {
+		return nil
+	}
+	ops, err := jsonpatch.Diff(from, u.history.docs[u.version])
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(StatePatchPayload{Target: u.history.kind, Base: base, Ops: ops})
+	if err != nil || len(data) >= len(u.full) {
+		return nil
+	}
+	return data
+}
+
+// ackPublicState 記錄連線確認持有的房間公開狀態版本
+func (r *Room) ackPublicState(c *Client, version int64) error {
+	return r.attempt(func() error {
+		if c.currentRoom() != r {
+			return errNotInRoom
+		}
+		r.publicHistoryLocked(c.locale).ack(&c.publicAcked, version)
+		return nil
+	})
+}
+
+// ackLobbyState 記錄連線確認持有的大廳房間列表版本
+func (h *Hub) ackLobbyState(c *Client, version int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lobbyStates.ack(&c.lobbyAcked, version)
+}
+
+// handleStateAck 依確認的狀態種類轉交房間或大廳
+func (c *Client) handleStateAck(payload StateAckPayload) {
+	switch payload.Target {
+	case "public_state":
+		if c.currentRoom() == nil {
+			c.sendError(errNotInRoom)
+			return
+		}
+		if err := c.currentRoom().ackPublicState(c, payload.Version); err != nil {
+			c.sendError(err)
+		}
+	case "lobby_rooms":
+		c.hub.ackLobbyState(c, payload.Version)
+	default:
+		c.sendError(reject("unknown_state", "target", payload.Target))
+	}
+}
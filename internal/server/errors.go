@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+
+	"zombierush/internal/i18n"
+)
+
+// 通用錯誤代碼；引擎與儲存層錯誤會沿用各自的代碼
+const (
+	ErrorCodeRejected   = "rejected"
+	ErrorCodeBadPayload = "bad_payload"
+)
+
+// protocolError 為協定層的錯誤，code 為穩定代碼，msg 為訊息目錄中顯示給玩家的文字
+type protocolError struct {
+	code string
+	msg  i18n.Message
+}
+
+func newProtocolError(code string) *protocolError {
+	return &protocolError{code: code, msg: i18n.M("error." + code)}
+}
+
+// reject 建立以訊息目錄 ID 為代碼的拒絕錯誤，如 reject("wrong_password") 的代碼為 wrong_password
+func reject(id string, kv ...any) error {
+	return &protocolError{code: id, msg: i18n.M("error."+id, kv...)}
+}
+
+func (e *protocolError) Error() string { return e.msg.String() }
+
+func (e *protocolError) ErrorCode() string { return e.code }
+
+func (e *protocolError) Localize(loc i18n.Locale) string { return e.msg.Localize(loc) }
+
+// Is 讓附帶細節的錯誤仍能以 errors.Is 與同代碼的哨兵錯誤比對
+func (e *protocolError) Is(target error) bool {
+	t, ok := target.(*protocolError)
+	return ok && t.code == e.code
+}
+
+func (e *protocolError) withDetail(id string, kv ...any) error {
+	return &protocolError{code: e.code, msg: i18n.M(id, kv...)}
+}
+
+var (
+	errNotInRoom          = newProtocolError("not_in_room")
+	errAlreadyInRoom      = newProtocolError("already_in_room")
+	errRoomRequired       = newProtocolError("room_required")
+	errRoomNotFound       = newProtocolError("room_not_found")
+	errRoomFull           = newProtocolError("room_full")
+	errSeatRequired       = newProtocolError("seat_required")
+	errInvalidSeat        = newProtocolError("invalid_seat")
+	errNotSeated          = newProtocolError("not_seated")
+	errBanned             = newProtocolError("banned")
+	errHostOnly           = newProtocolError("host_only")
+	errUnknownCommand     = newProtocolError("unknown_command")
+	errGameNotRunning     = newProtocolError("game_not_running")
+	errSpectatorCannotAct = newProtocolError("spectator_cannot_act")
+	errGamePaused         = newProtocolError("game_paused")
+	errArchiveDisabled    = newProtocolError("archive_disabled")
+	errHostRequired       = newProtocolError("host_required")
+	errSavedGameCorrupt   = newProtocolError("saved_game_corrupt")
+)
+
+// ErrUnsupportedProtocol 表示連線宣告的協定版本過舊或無法辨識
+var ErrUnsupportedProtocol = newProtocolError("unsupported_protocol")
+
+// ErrorCode 取出錯誤的穩定代碼：引擎、儲存層與協定錯誤皆提供 ErrorCode 方法，
+// 無法解析的指令內容為 bad_payload，其餘未帶代碼的錯誤歸為 rejected
+func ErrorCode(err error) string {
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return ErrorCodeBadPayload
+	}
+	return ErrorCodeRejected
+}
+
+// NewErrorPayload 將錯誤轉為帶有代碼、並依語系呈現訊息的 error 內容
+func NewErrorPayload(err error, loc i18n.Locale) ErrorPayload {
+	return ErrorPayload{Code: ErrorCode(err), Message: i18n.ErrorText(err, loc)}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"log"
+	"sync"
+
+	"zombierush/internal/server/store"
+)
+
+// gameWriter 在背景寫入通信對局的快照，房間迴圈不必等候資料庫。
+// 同一房間只保留最新一份尚未寫入的快照；讀取保存紀錄前先等候相關寫入完成，才不會讀到舊狀態
+type gameWriter struct {
+	mu      sync.Mutex
+	pending map[string]store.SavedGame
+	// busy 為正在寫入的房間，寫完該房間所有快照後關閉
+	busy map[string]chan struct{}
+}
+
+func newGameWriter() *gameWriter {
+	return &gameWriter{
+		pending: make(map[string]store.SavedGame),
+		busy:    make(map[string]chan struct{}),
+	}
+}
+
+// saveGame 排入房間快照，由背景依序寫入；可在房間迴圈中呼叫
+func (h *Hub) saveGame(archive GameArchive, record store.SavedGame) {
+	w := h.saves
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[record.RoomID] = record
+	if _, running := w.busy[record.RoomID]; running {
+		return
+	}
+	done := make(chan struct{})
+	w.busy[record.RoomID] = done
+	h.background(func() { w.drain(archive, record.RoomID, done) })
+}
+
+func (w *gameWriter) drain(archive GameArchive, roomID string, done chan struct{}) {
+	for {
+		w.mu.Lock()
+		record, ok := w.pending[roomID]
+		if !ok {
+			delete(w.busy, roomID)
+			close(done)
+			w.mu.Unlock()
+			return
+		}
+		delete(w.pending, roomID)
+		w.mu.Unlock()
+
+		if err := archive.SaveGame(record); err != nil {
+			log.Printf("保存通信對局 %s 失敗: %v", roomID, err)
+		}
+	}
+}
+
+// wait 等候指定房間的快照全部寫入
+func (w *gameWriter) wait(roomID string) {
+	w.mu.Lock()
+	done := w.busy[roomID]
+	w.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// waitAll 等候目前所有房間的快照寫入，列出保存紀錄前呼叫
+func (w *gameWriter) waitAll() {
+	w.mu.Lock()
+	pending := make([]chan struct{}, 0, len(w.busy))
+	for _, done := range w.busy {
+		pending = append(pending, done)
+	}
+	w.mu.Unlock()
+	for _, done := range pending {
+		<-done
+	}
+}
@@ -2,8 +2,13 @@ package server
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"zombierush/internal/clock"
+	"zombierush/internal/i18n"
 )
 
 // Hub 管理大廳與房間
@@ -11,19 +16,84 @@ type Hub struct {
 	mu           sync.Mutex
 	rooms        map[string]*Room
 	lobbyClients map[*Client]struct{}
+
+	queue     []*queueEntry
+	matchWait time.Duration
+
+	archive GameArchive
+	// saves 於背景寫入通信對局的快照；sweepTimer 定期喚醒到期的通信對局
+	saves      *gameWriter
+	sweepTimer clock.Timer
+
+	// accounts 記錄每個帳號目前有效的連線，同帳號僅保留最新一條
+	accounts map[int64]*Client
+
+	requests *requestLog
+
+	// lobbyStates 保存近期的大廳房間列表版本，作為差異廣播的基準
+	lobbyStates *stateHistory
+
+	// clock 為房間計時器、機器人排程、配對等待與請求去重使用的時鐘
+	clock clock.Clock
+	// lastRoomStamp 為最近一次配發房間 ID 的時間戳記，確保同一時刻建立的房間 ID 不重複
+	lastRoomStamp int64
+
+	// tasks 追蹤房間迴圈另起、尚未完成的 Hub 通知
+	tasks sync.WaitGroup
+
+	// 送出佇列的統計：被取代的訊息數、超過軟上限次數與因持續壅塞而關閉的連線數
+	coalesced atomic.Int64
+	overflows atomic.Int64
+	dropped   atomic.Int64
 }
 
 func NewHub() *Hub {
 	return &Hub{
 		rooms:        make(map[string]*Room),
 		lobbyClients: make(map[*Client]struct{}),
+		accounts:     make(map[int64]*Client),
+		requests:     newRequestLog(),
+		saves:        newGameWriter(),
+		lobbyStates:  newStateHistory("lobby_rooms"),
+		clock:        clock.Real(),
+		matchWait:    defaultMatchmakingWait,
 	}
 }
 
+// SetClock 更換伺服器使用的時鐘，測試可換成 clock.Fake 以立即推進計時；
+// 已建立的房間沿用原本的時鐘，應在開始服務前設定
+func (h *Hub) SetClock(clk clock.Clock) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if clk != nil {
+		h.clock = clk
+	}
+}
+
+// currentClock 回傳目前的時鐘
+func (h *Hub) currentClock() clock.Clock {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.clock
+}
+
+// background 另起 goroutine 執行 Hub 通知；房間迴圈內需要呼叫 Hub 時一律經由此處
+func (h *Hub) background(fn func()) {
+	h.tasks.Add(1)
+	go func() {
+		defer h.tasks.Done()
+		fn()
+	}()
+}
+
+// WaitIdle 等待房間另起的 Hub 通知全部完成，測試可藉此在檢查結果前同步
+func (h *Hub) WaitIdle() {
+	h.tasks.Wait()
+}
+
 func (h *Hub) RegisterLobbyClient(c *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	c.inLobby = true
 	h.lobbyClients[c] = struct{}{}
 	h.sendRoomListLocked(c)
 }
@@ -32,51 +102,86 @@ func (h *Hub) unregisterLobbyClient(c *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	delete(h.lobbyClients, c)
+	if h.removeFromQueueLocked(c) {
+		h.sendQueueStatusLocked()
+	}
+	h.broadcastLobbyLocked()
 }
 
+// sendRoomListLocked 送出完整的房間列表，供剛進入大廳或主動要求列表的連線重新建立基準
 func (h *Hub) sendRoomListLocked(c *Client) {
-	summaries := h.buildRoomSummariesLocked()
-	c.sendMessage(ServerMessage{Type: "lobby_rooms", Payload: LobbyRoomsPayload{Rooms: summaries}})
+	update, err := h.lobbyStates.record(LobbyRoomsPayload{Rooms: h.buildRoomSummariesLocked()})
+	if err != nil {
+		return
+	}
+	c.sendMessage(update.fullMessage())
 }
 
+// broadcastLobbyLocked 依各連線確認的版本送出房間列表的差異
 func (h *Hub) broadcastLobbyLocked() {
-	summaries := h.buildRoomSummariesLocked()
-	payload := ServerMessage{Type: "lobby_rooms", Payload: LobbyRoomsPayload{Rooms: summaries}}
+	update, err := h.lobbyStates.record(LobbyRoomsPayload{Rooms: h.buildRoomSummariesLocked()})
+	if err != nil {
+		return
+	}
 	for client := range h.lobbyClients {
-		client.sendMessage(payload)
+		if msg, ok := update.messageFor(client, client.lobbyAcked); ok {
+			client.sendMessage(msg)
+		}
 	}
 }
 
 func (h *Hub) buildRoomSummariesLocked() []RoomSummary {
 	rooms := make([]RoomSummary, 0, len(h.rooms))
 	for _, room := range h.rooms {
-		rooms = append(rooms, room.summary())
+		if listing := room.published(); listing.listed {
+			rooms = append(rooms, listing.summary)
+		}
 	}
+	// 依房間 ID（即建立順序）排列，列表內容穩定才能計算出精簡的差異
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].RoomID < rooms[j].RoomID })
 	return rooms
 }
 
-func (h *Hub) CreateRoom(name string, host *Client) (*Room, error) {
+func (h *Hub) CreateRoom(name string, host *Client, opts RoomOptions) (*Room, error) {
 	if host == nil {
-		return nil, fmt.Errorf("缺少房主資訊")
+		return nil, errHostRequired
+	}
+	if host.currentRoom() != nil {
+		return nil, errAlreadyInRoom
 	}
 	if name == "" {
-		name = "未命名房間"
+		name = i18n.M("name.room").Localize(host.locale)
+	}
+	h.mu.Lock()
+	roomID := h.newRoomIDLocked()
+	h.mu.Unlock()
+	// 存取設定與邀請碼須在房間迴圈啟動前寫入；邀請碼在持有鎖時配發並登記，避免重複
+	room := newRoom(roomID, name, defaultRoomCapacity, h)
+	if opts.Private {
+		room.settings.Visibility = RoomVisibilityPrivate
 	}
-	roomID := fmt.Sprintf("room-%d", time.Now().UnixNano())
-	room := NewRoom(roomID, name, 8, h)
+	room.password = opts.Password
 
 	h.mu.Lock()
+	code, err := h.newInviteCodeLocked()
+	if err != nil {
+		h.mu.Unlock()
+		return nil, err
+	}
+	room.inviteCode = code
+	room.archive = h.archive
+	room.start()
 	h.rooms[roomID] = room
 	delete(h.lobbyClients, host)
+	h.removeFromQueueLocked(host)
 	h.mu.Unlock()
 
 	host.token = ""
-	if err := room.Join(host); err != nil {
+	if err := room.Join(host, JoinOptions{InviteCode: code}); err != nil {
 		h.mu.Lock()
-		delete(h.rooms, roomID)
-		h.lobbyClients[host] = struct{}{}
-		h.sendRoomListLocked(host)
+		h.removeRoomLocked(room)
 		h.mu.Unlock()
+		h.restoreLobbyClient(host)
 		return nil, err
 	}
 
@@ -86,22 +191,83 @@ func (h *Hub) CreateRoom(name string, host *Client) (*Room, error) {
 	return room, nil
 }
 
-func (h *Hub) JoinRoom(roomID string, client *Client) error {
+// newRoomIDLocked 依時鐘配發房間 ID；時間未前進時（如假時鐘）遞增戳記，重啟後也不會與保存的對局重複
+func (h *Hub) newRoomIDLocked() string {
+	stamp := h.clock.Now().UnixNano()
+	if stamp <= h.lastRoomStamp {
+		stamp = h.lastRoomStamp + 1
+	}
+	h.lastRoomStamp = stamp
+	return fmt.Sprintf("room-%d", stamp)
+}
+
+// JoinRoom 以房間 ID 或邀請碼加入房間；不在記憶體中的通信對局會先由保存紀錄載入。
+// 已在房間內的連線一律拒絕，配對與玩家自己的指令因此不會讓同一連線進入兩個房間
+func (h *Hub) JoinRoom(roomID string, client *Client, opts JoinOptions) error {
+	if client.currentRoom() != nil {
+		return errAlreadyInRoom
+	}
 	h.mu.Lock()
 	room, ok := h.rooms[roomID]
+	if !ok && roomID == "" {
+		room, ok = h.roomByInviteLocked(opts.InviteCode)
+	}
+	h.mu.Unlock()
+	if !ok && roomID != "" {
+		room, ok = h.loadCorrespondenceRoom(roomID)
+	}
 	if !ok {
-		h.mu.Unlock()
-		return fmt.Errorf("房間不存在")
+		return errRoomNotFound
 	}
+	h.mu.Lock()
 	delete(h.lobbyClients, client)
+	h.removeFromQueueLocked(client)
 	h.mu.Unlock()
 
-	if err := room.Join(client); err != nil {
-		h.mu.Lock()
-		h.lobbyClients[client] = struct{}{}
-		h.sendRoomListLocked(client)
-		h.broadcastLobbyLocked()
+	if err := room.Join(client, opts); err != nil {
+		h.restoreLobbyClient(client)
+		h.releaseIdleRoom(room)
+		h.refreshLobby()
+		return err
+	}
+
+	h.mu.Lock()
+	h.broadcastLobbyLocked()
+	h.mu.Unlock()
+	return nil
+}
+
+// restoreLobbyClient 於加入房間失敗後將連線放回大廳；同時已進入其他房間者維持原狀
+func (h *Hub) restoreLobbyClient(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if client.currentRoom() != nil {
+		return
+	}
+	h.lobbyClients[client] = struct{}{}
+	h.sendRoomListLocked(client)
+}
+
+// SpectateRoom 以觀戰者身份進入房間
+func (h *Hub) SpectateRoom(roomID string, client *Client, opts JoinOptions) error {
+	if client.currentRoom() != nil {
+		return errAlreadyInRoom
+	}
+	h.mu.Lock()
+	room, ok := h.rooms[roomID]
+	if !ok && roomID == "" {
+		room, ok = h.roomByInviteLocked(opts.InviteCode)
+	}
+	if !ok {
 		h.mu.Unlock()
+		return errRoomNotFound
+	}
+	delete(h.lobbyClients, client)
+	h.removeFromQueueLocked(client)
+	h.mu.Unlock()
+
+	if err := room.addSpectator(client, opts); err != nil {
+		h.restoreLobbyClient(client)
 		return err
 	}
 
@@ -112,47 +278,115 @@ func (h *Hub) JoinRoom(roomID string, client *Client) error {
 }
 
 func (h *Hub) LeaveRoom(client *Client) {
-	if client == nil || client.room == nil {
+	if client == nil {
+		return
+	}
+	room := client.currentRoom()
+	if room == nil {
 		return
 	}
-	room := client.room
 	room.onClientLeft(client)
-
-	client.room = nil
-	client.seatIndex = -1
-	client.inLobby = true
+	h.releaseIdleRoom(room)
 
 	h.mu.Lock()
 	h.lobbyClients[client] = struct{}{}
 	h.sendRoomListLocked(client)
-	if room.isEmpty() {
-		delete(h.rooms, room.id)
-	}
 	h.broadcastLobbyLocked()
 	h.mu.Unlock()
 }
 
+// refreshLobby 重新推送房間列表給大廳中的玩家
+func (h *Hub) refreshLobby() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.broadcastLobbyLocked()
+}
+
+// returnToLobby 將已由房間移出的玩家送回大廳並告知原因
+func (h *Hub) returnToLobby(roomID string, clients []*Client, reason i18n.Message) {
+	if len(clients) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.returnToLobbyLocked(roomID, clients, reason)
+	h.broadcastLobbyLocked()
+}
+
+func (h *Hub) returnToLobbyLocked(roomID string, clients []*Client, reason i18n.Message) {
+	for _, c := range clients {
+		h.lobbyClients[c] = struct{}{}
+		c.sendMessage(ServerMessage{Type: "room_left", Payload: RoomLeftPayload{RoomID: roomID, Reason: reason.Localize(c.locale)}})
+		h.sendRoomListLocked(c)
+	}
+}
+
+// removeRoomLocked 將房間自 Hub 移除並結束其迴圈
+func (h *Hub) removeRoomLocked(room *Room) {
+	if h.rooms[room.id] == room {
+		delete(h.rooms, room.id)
+	}
+	room.stop()
+}
+
 func (h *Hub) RemoveClient(c *Client) {
 	if c == nil {
 		return
 	}
 
-	if c.room != nil {
-		room := c.room
+	if room := c.currentRoom(); room != nil {
 		room.onClientLeft(c)
-		c.room = nil
-		c.seatIndex = -1
 	}
 
 	h.mu.Lock()
 	delete(h.lobbyClients, c)
-	for id, room := range h.rooms {
-		if room.isEmpty() {
-			delete(h.rooms, id)
-		}
+	h.forgetConnectionLocked(c)
+	if h.removeFromQueueLocked(c) {
+		h.sendQueueStatusLocked()
 	}
-	h.broadcastLobbyLocked()
+	rooms := h.roomsLocked()
 	h.mu.Unlock()
+
+	// 逐一通知房間時不持有 Hub 鎖，避免一個忙碌的房間卡住整個大廳
+	for _, room := range rooms {
+		room.dropClaimsBy(c)
+		h.releaseIdleRoom(room)
+	}
+	h.refreshLobby()
+}
+
+// roomsLocked 回傳目前房間的快照，供釋放 Hub 鎖後逐一與房間迴圈往返
+func (h *Hub) roomsLocked() []*Room {
+	rooms := make([]*Room, 0, len(h.rooms))
+	for _, room := range h.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+func (h *Hub) newInviteCodeLocked() (string, error) {
+	for {
+		code, err := generateInviteCode()
+		if err != nil {
+			return "", err
+		}
+		if _, taken := h.roomByInviteLocked(code); !taken {
+			return code, nil
+		}
+	}
+}
+
+func (h *Hub) roomByInviteLocked(code string) (*Room, bool) {
+	code = normalizeInviteCode(code)
+	if code == "" {
+		return nil, false
+	}
+	for _, room := range h.rooms {
+		if room.inviteCode == code {
+			return room, true
+		}
+	}
+	return nil, false
 }
 
 func (h *Hub) RoomByID(id string) (*Room, bool) {
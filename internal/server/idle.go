@@ -0,0 +1,82 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+const (
+	// idleReleaseTimeout 為由保存紀錄載入的房間在無人連線時保留的時間；逾時後通信對局交由 Hub 卸載，
+	// 重新開啟的封存對局則再次封存後關閉
+	idleReleaseTimeout = 5 * time.Minute
+	// correspondenceSweepInterval 為 Hub 檢查到期通信對局的間隔
+	correspondenceSweepInterval = time.Minute
+)
+
+// armIdleRelease 於房間由保存紀錄載入後啟動閒置計時；屆時已有人連線則不做任何事，
+// 之後通信對局的最後一人離開時照常由 Hub 釋放，重新開啟的封存對局則由 onClientLeft 再次計時
+func (r *Room) armIdleRelease() {
+	r.do(r.armIdleReleaseLocked)
+}
+
+func (r *Room) armIdleReleaseLocked() {
+	if r.idleTimer != nil {
+		r.idleTimer.Stop()
+	}
+	r.idleTimer = r.clock.AfterFunc(idleReleaseTimeout, r.onIdleRelease)
+}
+
+func (r *Room) onIdleRelease() {
+	r.do(func() {
+		r.idleTimer = nil
+		if r.hasConnectedLocked() {
+			return
+		}
+		if r.settings.Correspondence {
+			r.hub.background(func() { r.hub.releaseIdleRoom(r) })
+			return
+		}
+		r.readjournLocked()
+		r.hub.background(func() { r.hub.removeIdleRoom(r) })
+	})
+}
+
+// readjournLocked 將重新開啟後無人回座的封存對局再次封存並關閉房間，待防守的挑戰一併保存；
+// 對局已結束時不覆寫保存紀錄
+func (r *Room) readjournLocked() {
+	if r.restored && r.status == RoomStatusRunning && r.game != nil && r.archive != nil {
+		if !r.paused {
+			r.pauseLocked()
+		}
+		if record, err := r.buildSnapshotLocked(); err != nil {
+			log.Printf("再次封存對局 %s 失敗: %v", r.id, err)
+		} else {
+			r.hub.saveGame(r.archive, record)
+		}
+	}
+	r.stopTimersLocked()
+	r.endPostGameLocked()
+	r.status = RoomStatusFinished
+	r.game = nil
+}
+
+// removeIdleRoom 將已在房間迴圈中關閉的閒置房間自 Hub 移除
+func (h *Hub) removeIdleRoom(room *Room) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeRoomLocked(room)
+	h.broadcastLobbyLocked()
+}
+
+// hasConnectedLocked 回傳房內是否仍有入座或觀戰中的連線
+func (r *Room) hasConnectedLocked() bool {
+	if len(r.spectators) > 0 {
+		return true
+	}
+	for _, seat := range r.seats {
+		if seat.Client != nil {
+			return true
+		}
+	}
+	return false
+}
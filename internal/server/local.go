@@ -0,0 +1,38 @@
+package server
+
+import "encoding/json"
+
+// 同行程連線：不經 websocket，由呼叫端直接送入指令並取出送出佇列，供 servertest 等測試工具使用
+
+// NewLocalClient 建立不經網路的連線；呼叫端須持續以 Flush 取出訊息，否則佇列持續壅塞時連線會被關閉
+func NewLocalClient(hub *Hub, userID int64, account, displayName string) *Client {
+	return NewWebClient(nil, hub, userID, account, displayName, "")
+}
+
+// SetSeatToken 設定連線出示的座位憑證，對應 /ws 的 token 參數，需在 Hub.Connect 前呼叫
+func (c *Client) SetSeatToken(token string) {
+	c.token = token
+}
+
+// Flush 取出送出佇列中的訊息並編碼為與 websocket 相同的 JSON；closed 為真表示連線已關閉
+func (c *Client) Flush() (messages [][]byte, closed bool) {
+	items, closed := c.outbox.take()
+	for _, msg := range items {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			continue
+		}
+		messages = append(messages, data)
+	}
+	return messages, closed
+}
+
+// Receive 處理一則客戶端訊息，與 websocket 讀入的訊息走相同流程
+func (c *Client) Receive(msg ClientMessage) {
+	c.handleMessage(msg)
+}
+
+// Disconnect 模擬連線中斷
+func (c *Client) Disconnect() {
+	c.close()
+}
@@ -0,0 +1,75 @@
+package server
+
+// 每個房間由單一 goroutine 依序執行指令：玩家操作、機器人回合、計時器與進出房間皆排入同一個佇列，
+// 房間狀態只在這個迴圈中讀寫。名稱以 Locked 結尾的方法都假設已在房間迴圈中執行，
+// 迴圈內不得同步呼叫 Hub 或再次呼叫 do，需要通知 Hub 時改以 hub.background 另起 goroutine。
+// 大廳列表所需的房間摘要由迴圈在每個指令後發布，Hub 直接讀取發布的結果，不必等候房間迴圈
+
+const roomCommandBuffer = 64
+
+// run 為房間迴圈，直到 stop 後結束
+func (r *Room) run() {
+	defer close(r.done)
+	for {
+		select {
+		case cmd := <-r.commands:
+			cmd()
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// stop 結束房間迴圈；尚未執行的指令一律放棄，等待中的呼叫端會得到房間已關閉的結果
+func (r *Room) stop() {
+	r.stopOnce.Do(func() { close(r.quit) })
+}
+
+// do 將 fn 排入房間迴圈並等待執行完畢；房間已關閉而未執行時回傳 false
+func (r *Room) do(fn func()) bool {
+	finished := make(chan struct{})
+	cmd := func() {
+		defer close(finished)
+		fn()
+		r.publishListingLocked()
+	}
+	select {
+	case r.commands <- cmd:
+	case <-r.done:
+		return false
+	}
+	select {
+	case <-finished:
+		return true
+	case <-r.done:
+		// 迴圈在執行完指令後才會結束，finished 已關閉即表示指令有執行
+		select {
+		case <-finished:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// attempt 在房間迴圈中執行可能失敗的操作；房間已關閉時回傳 errRoomNotFound
+func (r *Room) attempt(fn func() error) error {
+	err := error(errRoomNotFound)
+	r.do(func() { err = fn() })
+	return err
+}
+
+// query 在房間迴圈中取值；房間已關閉時回傳零值
+func query[T any](r *Room, fn func() T) T {
+	var result T
+	r.do(func() { result = fn() })
+	return result
+}
+
+// attemptWith 同 attempt，供另有回傳值的操作使用
+func attemptWith[T any](r *Room, fn func() (T, error)) (T, error) {
+	var result T
+	err := error(errRoomNotFound)
+	r.do(func() { result, err = fn() })
+	return result, err
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"time"
+
+	"zombierush/internal/clock"
+	"zombierush/internal/game"
+	"zombierush/internal/i18n"
+)
+
+const defaultMatchmakingWait = 30 * time.Second
+
+// MatchPreferences 描述玩家的快速配對條件
+type MatchPreferences struct {
+	Variant   game.Variant
+	Rated     bool
+	AllowBots bool
+}
+
+// queueEntry 表示一位排隊中的玩家；tried 為加入失敗過的房間（例如已被封鎖），之後配對不再選入
+type queueEntry struct {
+	client     *Client
+	prefs      MatchPreferences
+	enqueuedAt time.Time
+	timer      clock.Timer
+	tried      map[*Room]struct{}
+}
+
+// matchAssignment 為一次配對的結果：加入既有房間或以 members 開新桌
+type matchAssignment struct {
+	room    *Room
+	members []*queueEntry
+}
+
+// SetMatchmakingWait 設定配對等待多久後以機器人補滿
+func (h *Hub) SetMatchmakingWait(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if d > 0 {
+		h.matchWait = d
+	}
+}
+
+// Enqueue 將大廳中的玩家加入快速配對佇列
+func (h *Hub) Enqueue(c *Client, prefs MatchPreferences) error {
+	rules, err := game.RulesFor(prefs.Variant)
+	if err != nil {
+		return err
+	}
+	prefs.Variant = rules.Variant
+
+	h.mu.Lock()
+	if c.currentRoom() != nil {
+		h.mu.Unlock()
+		return errAlreadyInRoom
+	}
+	h.removeFromQueueLocked(c)
+	entry := &queueEntry{client: c, prefs: prefs, enqueuedAt: h.clock.Now()}
+	if prefs.AllowBots {
+		entry.timer = h.clock.AfterFunc(h.matchWait, h.processQueue)
+	}
+	h.queue = append(h.queue, entry)
+	h.mu.Unlock()
+
+	h.processQueue()
+	return nil
+}
+
+// CancelQueue 取消排隊
+func (h *Hub) CancelQueue(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.removeFromQueueLocked(c) {
+		c.sendMessage(ServerMessage{Type: "queue_status", Payload: QueueStatusPayload{Queued: false}})
+		h.sendQueueStatusLocked()
+	}
+}
+
+func (h *Hub) removeFromQueueLocked(c *Client) bool {
+	for i, entry := range h.queue {
+		if entry.client != c {
+			continue
+		}
+		if entry.timer != nil {
+			entry.timer.Stop()
+		}
+		h.queue = append(h.queue[:i], h.queue[i+1:]...)
+		return true
+	}
+	return false
+}
+
+// processQueue 依序為排隊玩家尋找空位：先補進等待中的公開房間，
+// 其次湊滿整桌真人，最後為等待逾時且接受機器人的玩家開桌補滿
+func (h *Hub) processQueue() {
+	h.mu.Lock()
+	assignments := h.planMatchesLocked(h.clock.Now())
+	for _, assignment := range assignments {
+		for _, entry := range assignment.members {
+			h.removeFromQueueLocked(entry.client)
+		}
+	}
+	h.sendQueueStatusLocked()
+	h.mu.Unlock()
+
+	for _, assignment := range assignments {
+		h.executeMatch(assignment)
+	}
+}
+
+func (h *Hub) planMatchesLocked(now time.Time) []matchAssignment {
+	assignments := make([]matchAssignment, 0)
+	reserved := make(map[*Room]int)
+	groups := make(map[MatchPreferences][]*queueEntry)
+	order := make([]MatchPreferences, 0)
+
+	for _, entry := range h.queue {
+		if room := h.findOpenRoomLocked(entry, reserved); room != nil {
+			reserved[room]++
+			assignments = append(assignments, matchAssignment{room: room, members: []*queueEntry{entry}})
+			continue
+		}
+		if _, ok := groups[entry.prefs]; !ok {
+			order = append(order, entry.prefs)
+		}
+		groups[entry.prefs] = append(groups[entry.prefs], entry)
+	}
+
+	for _, prefs := range order {
+		members := groups[prefs]
+		for len(members) >= defaultRoomCapacity {
+			assignments = append(assignments, matchAssignment{members: members[:defaultRoomCapacity]})
+			members = members[defaultRoomCapacity:]
+		}
+		if len(members) > 0 && prefs.AllowBots && now.Sub(members[0].enqueuedAt) >= h.matchWait {
+			assignments = append(assignments, matchAssignment{members: members})
+		}
+	}
+	return assignments
+}
+
+func (h *Hub) findOpenRoomLocked(entry *queueEntry, reserved map[*Room]int) *Room {
+	for _, room := range h.rooms {
+		if _, tried := entry.tried[room]; tried {
+			continue
+		}
+		if room.acceptsMatch(entry.prefs, reserved[room]) {
+			return room
+		}
+	}
+	return nil
+}
+
+func (h *Hub) executeMatch(assignment matchAssignment) {
+	if assignment.room != nil {
+		entry := assignment.members[0]
+		if err := h.JoinRoom(assignment.room.id, entry.client, JoinOptions{InviteCode: assignment.room.inviteCode}); err != nil {
+			// 房間拒絕此玩家時不再配到同一間，以免每次處理佇列都重試失敗
+			if entry.tried == nil {
+				entry.tried = make(map[*Room]struct{})
+			}
+			entry.tried[assignment.room] = struct{}{}
+			h.requeue(entry)
+		}
+		return
+	}
+
+	host := assignment.members[0]
+	prefs := host.prefs
+	room, err := h.CreateRoom(i18n.M("name.quick_match", "host", host.client.name).Localize(host.client.locale), host.client, RoomOptions{})
+	if err != nil {
+		for _, entry := range assignment.members {
+			h.requeue(entry)
+		}
+		return
+	}
+	room.applyMatchPreferences(prefs)
+	for _, entry := range assignment.members[1:] {
+		if err := h.JoinRoom(room.id, entry.client, JoinOptions{InviteCode: room.inviteCode}); err != nil {
+			h.requeue(entry)
+		}
+	}
+	if err := room.startMatched(); err != nil {
+		room.broadcastError(err)
+	}
+	h.refreshLobby()
+}
+
+func (h *Hub) requeue(entry *queueEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if entry.client.currentRoom() != nil {
+		return
+	}
+	if entry.prefs.AllowBots {
+		entry.timer = h.clock.AfterFunc(h.matchWait, h.processQueue)
+	}
+	h.queue = append([]*queueEntry{entry}, h.queue...)
+	h.sendQueueStatusLocked()
+}
+
+func (h *Hub) sendQueueStatusLocked() {
+	for i, entry := range h.queue {
+		compatible := 0
+		for _, other := range h.queue {
+			if other.prefs == entry.prefs {
+				compatible++
+			}
+		}
+		status := QueueStatusPayload{
+			Queued:     true,
+			Position:   i + 1,
+			Compatible: compatible,
+			Variant:    string(entry.prefs.Variant),
+			Rated:      entry.prefs.Rated,
+			AllowBots:  entry.prefs.AllowBots,
+			Since:      entry.enqueuedAt.UnixMilli(),
+		}
+		if entry.prefs.AllowBots {
+			status.TopUpAt = entry.enqueuedAt.Add(h.matchWait).UnixMilli()
+		}
+		entry.client.sendMessage(ServerMessage{Type: "queue_status", Payload: status})
+	}
+}
+
+// acceptsMatch 依房間發布的摘要判斷等待中的公開房間能否再補進配對玩家，不需等候房間迴圈；
+// 通信對局以小時計時，不適合快速配對
+func (r *Room) acceptsMatch(prefs MatchPreferences, reserved int) bool {
+	listing := r.published()
+	summary := listing.summary
+	if summary.Status != RoomStatusLobby || summary.Locked || summary.HasPassword || summary.Settings.Visibility != RoomVisibilityPublic || summary.Settings.Correspondence {
+		return false
+	}
+	if summary.Settings.Variant != string(prefs.Variant) || summary.Settings.Rated != prefs.Rated {
+		return false
+	}
+	if !prefs.AllowBots && listing.bots > 0 {
+		return false
+	}
+	return summary.Capacity-summary.Players > reserved
+}
+
+func (r *Room) applyMatchPreferences(prefs MatchPreferences) {
+	r.do(func() {
+		r.settings.Variant = prefs.Variant
+		r.settings.Rated = prefs.Rated
+	})
+}
+
+// startMatched 配對成桌後直接開局，空位由機器人補滿
+func (r *Room) startMatched() error {
+	return r.attempt(func() error {
+		return r.startGameLocked()
+	})
+}
+
+// broadcastError 將錯誤送給房內所有人
+func (r *Room) broadcastError(err error) {
+	r.do(func() {
+		for _, c := range r.collectClientsLocked() {
+			r.sendErrorLocked(c, err)
+		}
+	})
+}
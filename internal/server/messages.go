@@ -4,21 +4,28 @@ import (
 	"encoding/json"
 
 	"zombierush/internal/game"
+	"zombierush/internal/jsonpatch"
 )
 
-// ClientMessage 定義 WebSocket 客戶端發送的通用訊息格式
+// ClientMessage 為 WebSocket 客戶端發送的通用指令格式；帶 requestId 時伺服器以 ack/nack 回覆，並忽略時限內重送的相同 ID
 type ClientMessage struct {
-	Type    string          `json:"type"`
-	Payload json.RawMessage `json:"payload"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	RequestID string          `json:"requestId,omitempty"`
 }
 
 // 大廳與房間管理請求
 type CreateRoomPayload struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Private  bool   `json:"private,omitempty"`
+	Password string `json:"password,omitempty"`
 }
 
 type JoinRoomPayload struct {
-	RoomID string `json:"roomId"`
+	RoomID     string `json:"roomId,omitempty"`
+	InviteCode string `json:"inviteCode,omitempty"`
+	Password   string `json:"password,omitempty"`
+	Seat       *int   `json:"seat,omitempty"`
 }
 
 type LeaveRoomPayload struct{}
@@ -28,32 +35,116 @@ type BotCommandPayload struct {
 	Name string `json:"name,omitempty"`
 }
 
+// 房主管理指令
+type SeatTargetPayload struct {
+	Seat *int `json:"seat"`
+}
+
+type SeatResponsePayload struct {
+	Seat   *int `json:"seat"`
+	Accept bool `json:"accept"`
+}
+
+type LockRoomPayload struct {
+	Locked bool `json:"locked"`
+}
+
+// RoomSettingsPayload 為房間設定的部分更新，未提供的欄位維持原值
+type RoomSettingsPayload struct {
+	Variant         *string `json:"variant,omitempty"`
+	TurnTimeout     *int    `json:"turnTimeout,omitempty"`
+	DefenseTimeout  *int    `json:"defenseTimeout,omitempty"`
+	BotDifficulty   *string `json:"botDifficulty,omitempty"`
+	AllowSpectators *bool   `json:"allowSpectators,omitempty"`
+	Visibility      *string `json:"visibility,omitempty"`
+	Rated           *bool   `json:"rated,omitempty"`
+	AFKLimit        *int    `json:"afkLimit,omitempty"`
+	Seed            *int64  `json:"seed,omitempty"`
+	Correspondence  *bool   `json:"correspondence,omitempty"`
+	MoveHours       *int    `json:"moveHours,omitempty"`
+}
+
+type SpectatePayload struct {
+	RoomID     string `json:"roomId,omitempty"`
+	InviteCode string `json:"inviteCode,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+// 快速配對
+type QueueJoinPayload struct {
+	Variant   string `json:"variant,omitempty"`
+	Rated     bool   `json:"rated,omitempty"`
+	AllowBots *bool  `json:"allowBots,omitempty"`
+}
+
+type ReadyPayload struct {
+	Ready bool `json:"ready"`
+}
+
+type MinHumansPayload struct {
+	MinHumans int `json:"minHumans"`
+}
+
 // 對戰階段請求
-type StartGamePayload struct{}
+type StartGamePayload struct {
+	Force bool `json:"force,omitempty"`
+}
+
+type RematchVotePayload struct {
+	Vote string `json:"vote"`
+}
 
+// ChallengePayload 與 DefensePayload 以牌編號（CardView.id）指定出牌
 type ChallengePayload struct {
-    TargetID int   `json:"targetId"`
-    Cards    []int `json:"cards"`
+	TargetID int   `json:"targetId"`
+	CardIDs  []int `json:"cardIds"`
 }
 
 type DefensePayload struct {
-    Cards []int `json:"cards"`
+	CardIDs []int `json:"cardIds"`
 }
 
-// ServerMessage 是伺服器端對外推送的通用訊息格式
+// ServerMessage 是伺服器端對外推送的通用訊息格式；房間訊息另附序號 Seq
+// 與此連線上一則房間訊息的序號 Prev，兩者不連續表示漏收
 type ServerMessage struct {
 	Type    string      `json:"type"`
+	Seq     int64       `json:"seq,omitempty"`
+	Prev    int64       `json:"prev,omitempty"`
+	Version int64       `json:"version,omitempty"` // 狀態訊息的版本，客戶端以 state_ack 確認
 	Payload interface{} `json:"payload"`
+
+	// supersedes 非空時，送出佇列中同鍵而尚未送出的舊訊息會被此訊息取代；不輸出至 JSON
+	supersedes string
 }
 
 // 大廳資訊
 type RoomSummary struct {
-	RoomID   string `json:"roomId"`
-	Name     string `json:"name"`
-	Status   string `json:"status"`
-	Players  int    `json:"players"`
-	Capacity int    `json:"capacity"`
-	Host     string `json:"host"`
+	RoomID      string           `json:"roomId"`
+	Name        string           `json:"name"`
+	Status      string           `json:"status"`
+	Players     int              `json:"players"`
+	Capacity    int              `json:"capacity"`
+	Host        string           `json:"host"`
+	Locked      bool             `json:"locked"`
+	HasPassword bool             `json:"hasPassword"`
+	Settings    RoomSettingsView `json:"settings"`
+	Spectators  int              `json:"spectators"`
+}
+
+// RoomSettingsView 為公開的房間設定，不含亂數種子本身
+type RoomSettingsView struct {
+	Variant         string `json:"variant"`
+	MaxRounds       int    `json:"maxRounds"`
+	TurnTimeout     int    `json:"turnTimeout"`
+	DefenseTimeout  int    `json:"defenseTimeout"`
+	BotDifficulty   string `json:"botDifficulty"`
+	AllowSpectators bool   `json:"allowSpectators"`
+	Visibility      string `json:"visibility"`
+	Rated           bool   `json:"rated"`
+	AFKLimit        int    `json:"afkLimit"`
+	Seeded          bool   `json:"seeded"`
+	Correspondence  bool   `json:"correspondence"`
+	MoveHours       int    `json:"moveHours"`
 }
 
 type LobbyRoomsPayload struct {
@@ -67,17 +158,31 @@ type PublicRoomStatePayload struct {
 	Status     string               `json:"status"`
 	Seats      []SeatPublicSnapshot `json:"seats"`
 	HostSeat   int                  `json:"hostSeat"`
+	MinHumans  int                  `json:"minHumans"`
+	Locked     bool                 `json:"locked"`
+	Access     RoomAccessPayload    `json:"access"`
+	Settings   RoomSettingsView     `json:"settings"`
 	PublicGame *PublicGamePayload   `json:"publicGame,omitempty"`
+	PostGame   *PostGamePayload     `json:"postGame,omitempty"`
+}
+
+// RoomAccessPayload 為房間的存取設定；邀請碼可略過密碼，只在入座者的 welcome 中提供
+type RoomAccessPayload struct {
+	Visibility  string `json:"visibility"`
+	HasPassword bool   `json:"hasPassword"`
 }
 
 type SeatPublicSnapshot struct {
-	Index  int    `json:"index"`
-	Name   string `json:"name"`
-	Filled bool   `json:"filled"`
-	IsBot  bool   `json:"isBot"`
-	IsHost bool   `json:"isHost"`
-	Alive  *bool  `json:"alive,omitempty"`
-	Hand   *int   `json:"hand,omitempty"`
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	Filled  bool   `json:"filled"`
+	IsBot   bool   `json:"isBot"`
+	Away    bool   `json:"away,omitempty"`
+	Offline bool   `json:"offline,omitempty"`
+	IsHost  bool   `json:"isHost"`
+	Ready   bool   `json:"ready"`
+	Alive   *bool  `json:"alive,omitempty"`
+	Hand    *int   `json:"hand,omitempty"`
 }
 
 type PublicGamePayload struct {
@@ -85,6 +190,22 @@ type PublicGamePayload struct {
 	CurrentTurn  int                 `json:"currentTurn"`
 	CurrentRound int                 `json:"currentRound"`
 	PendingType  string              `json:"pendingType,omitempty"`
+	// 時限以 Unix 毫秒表示，0 代表不限時
+	TurnDeadline    int64 `json:"turnDeadline,omitempty"`
+	DefenseDeadline int64 `json:"defenseDeadline,omitempty"`
+	Paused          bool  `json:"paused,omitempty"`
+	PauseVotes      int   `json:"pauseVotes,omitempty"`
+}
+
+// 賽後再戰投票狀態
+type PostGamePayload struct {
+	Winner        string         `json:"winner"`
+	Votes         map[int]string `json:"votes"`
+	RematchVotes  int            `json:"rematchVotes"`
+	LeaveVotes    int            `json:"leaveVotes"`
+	Required      int            `json:"required"`
+	QuorumReached bool           `json:"quorumReached"`
+	Deadline      int64          `json:"deadline"`
 }
 
 // 私人資訊與提示
@@ -92,18 +213,39 @@ type PrivateStatePayload struct {
 	Snapshot game.PrivatePlayerSnapshot `json:"snapshot"`
 }
 
+// ResyncPayload 為 resync 的回覆：房間完整的公開狀態及其版本，入座者另附私人狀態
+type ResyncPayload struct {
+	Public  json.RawMessage      `json:"public"`
+	Version int64                `json:"version,omitempty"`
+	Private *PrivateStatePayload `json:"private,omitempty"`
+}
+
+// StatePatchPayload 為 state_patch 的內容：將 Target 類狀態的 Base 版本修補成訊息所帶的版本
+type StatePatchPayload struct {
+	Target string                `json:"target"`
+	Base   int64                 `json:"base"`
+	Ops    []jsonpatch.Operation `json:"ops"`
+}
+
+// StateAckPayload 為 state_ack 的內容：客戶端已持有 Target 類狀態的 Version 版本
+type StateAckPayload struct {
+	Target  string `json:"target"`
+	Version int64  `json:"version"`
+}
+
 type TurnPromptPayload struct {
 	PlayerID int    `json:"playerId"`
 	Name     string `json:"name"`
 }
 
 type DefensePromptPayload struct {
-    AttackerID    int             `json:"attackerId"`
-    AttackerName  string          `json:"attackerName"`
-    AttackCards   []game.CardView `json:"attackCards"`
-    Suit          *game.Suit      `json:"suit,omitempty"`
-    MaxSelectable int             `json:"maxSelectable"`
-    Options       []game.CardView `json:"options"`
+	AttackerID    int             `json:"attackerId"`
+	AttackerName  string          `json:"attackerName"`
+	AttackCards   []game.CardView `json:"attackCards"`
+	Suit          *game.Suit      `json:"suit,omitempty"`
+	MaxSelectable int             `json:"maxSelectable"`
+	Options       []game.CardView `json:"options"`
+	Deadline      int64           `json:"deadline,omitempty"`
 }
 
 type InfectionPromptPayload struct {
@@ -117,10 +259,95 @@ type LogPayload struct {
 	Message string `json:"message"`
 }
 
+// ErrorPayload 為錯誤內容；Code 為穩定的錯誤代碼，Message 為顯示用文字
 type ErrorPayload struct {
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+// RequestReplyPayload 為 ack/nack 的內容；Duplicate 表示該 ID 先前已處理，此次未重新執行
+type RequestReplyPayload struct {
+	RequestID string `json:"requestId"`
+	Code      string `json:"code,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
 type PrivateInfoPayload struct {
 	Message string `json:"message"`
 }
+
+// SeatSwapRequestPayload 通知玩家有人想與其交換座位
+type SeatSwapRequestPayload struct {
+	FromSeat int    `json:"fromSeat"`
+	Name     string `json:"name"`
+}
+
+// SeatClaimPayload 申請接手進行中對局的機器人座位
+type SeatClaimPayload struct {
+	RoomID     string `json:"roomId,omitempty"`
+	InviteCode string `json:"inviteCode,omitempty"`
+	Password   string `json:"password,omitempty"`
+	Seat       *int   `json:"seat"`
+}
+
+// SeatClaimRequestPayload 通知房主有玩家申請接手座位
+type SeatClaimRequestPayload struct {
+	Seat     int    `json:"seat"`
+	SeatName string `json:"seatName"`
+	Name     string `json:"name"`
+}
+
+// SavedGameSummary 描述一場可重新開啟的封存對局，SavedAt 為 Unix 毫秒
+type SavedGameSummary struct {
+	RoomID  string   `json:"roomId"`
+	Name    string   `json:"name"`
+	Round   int      `json:"round"`
+	Players []string `json:"players,omitempty"`
+	SavedAt int64    `json:"savedAt,omitempty"`
+}
+
+type SavedGamesPayload struct {
+	Games []SavedGameSummary `json:"games"`
+}
+
+type SavedGameTargetPayload struct {
+	RoomID string `json:"roomId"`
+}
+
+const (
+	InboxKindTurn      = "your_turn"
+	InboxKindChallenge = "challenged"
+)
+
+// InboxItem 為通信對局中等待玩家處理的事項，期限為 Unix 毫秒
+type InboxItem struct {
+	RoomID   string `json:"roomId"`
+	RoomName string `json:"roomName"`
+	Kind     string `json:"kind"`
+	Round    int    `json:"round"`
+	Attacker string `json:"attacker,omitempty"`
+	Deadline int64  `json:"deadline,omitempty"`
+}
+
+type InboxPayload struct {
+	Items []InboxItem `json:"items"`
+}
+
+// QueueStatusPayload 回報快速配對的排隊狀態，時間皆為 Unix 毫秒
+type QueueStatusPayload struct {
+	Queued     bool   `json:"queued"`
+	Position   int    `json:"position,omitempty"`
+	Compatible int    `json:"compatible,omitempty"`
+	Variant    string `json:"variant,omitempty"`
+	Rated      bool   `json:"rated,omitempty"`
+	AllowBots  bool   `json:"allowBots,omitempty"`
+	Since      int64  `json:"since,omitempty"`
+	TopUpAt    int64  `json:"topUpAt,omitempty"`
+}
+
+// RoomLeftPayload 通知玩家已被移出房間
+type RoomLeftPayload struct {
+	RoomID string `json:"roomId"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,93 @@
+package server
+
+import "zombierush/internal/i18n"
+
+// detachClientLocked 將座位上的真人移出房間，回傳被移出的客戶端
+func (r *Room) detachClientLocked(seat *Seat) *Client {
+	c := seat.Client
+	if c == nil {
+		return nil
+	}
+	r.vacateSeatLocked(seat)
+	c.setRoom(nil, -1)
+	return c
+}
+
+// kickSeat 由房主將指定座位的玩家請回大廳；ban 為真時同時封鎖該帳號
+func (r *Room) kickSeat(host *Client, seatIdx int, ban bool) (*Client, error) {
+	return attemptWith(r, func() (*Client, error) {
+		if err := r.ensureHostLocked(host, "error.host_only.kick"); err != nil {
+			return nil, err
+		}
+		seat := r.getSeatLocked(seatIdx)
+		if seat == nil {
+			return nil, errInvalidSeat
+		}
+		if seat.Client == nil {
+			return nil, reject("seat_not_human", "seat", seatIdx)
+		}
+		if seat.Client == host {
+			return nil, reject("kick_self")
+		}
+
+		target := seat.Client
+		if ban {
+			r.banned[target.userID] = struct{}{}
+		}
+		// 踢出後不保留座位 token，避免以原 token 重連
+		seat.Token = ""
+		seat.UserID = 0
+		r.detachClientLocked(seat)
+
+		note := i18n.M("log.kicked", "player", target.name)
+		if ban {
+			note = i18n.M("log.banned", "player", target.name)
+		}
+		r.broadcastLogLocked(note)
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return target, nil
+	})
+}
+
+// transferHost 將房主權限交給指定座位的真人
+func (r *Room) transferHost(host *Client, seatIdx int) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.transfer"); err != nil {
+			return err
+		}
+		seat := r.getSeatLocked(seatIdx)
+		if seat == nil {
+			return errInvalidSeat
+		}
+		if seat.Client == nil {
+			return reject("host_must_be_human")
+		}
+		if seat.Index == r.hostSeat {
+			return reject("already_host")
+		}
+		r.hostSeat = seat.Index
+		r.broadcastLogLocked(i18n.M("log.host_transferred", "player", seat.displayName()))
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return nil
+	})
+}
+
+// setLocked 鎖定後僅允許原座位重連，不再接受新玩家
+func (r *Room) setLocked(host *Client, locked bool) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.lock"); err != nil {
+			return err
+		}
+		r.locked = locked
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return nil
+	})
+}
+
+func (r *Room) isBannedLocked(c *Client) bool {
+	_, banned := r.banned[c.userID]
+	return banned
+}
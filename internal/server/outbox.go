@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"zombierush/internal/clock"
+)
+
+// 每條連線的送出佇列。房間與大廳只把訊息排入佇列，由 WritePump 取出寫入連線：
+// 標有 supersedes 的狀態訊息（房間公開狀態、私人狀態、大廳列表）在佇列中只保留最新一則，
+// 其餘訊息依排入順序送出。佇列長度超過 outboxSoftLimit 並持續 outboxOverflowGrace，
+// 或超過 outboxHardLimit 時，才認定連線跟不上而關閉
+
+const (
+	outboxSoftLimit     = 256
+	outboxHardLimit     = 1024
+	outboxOverflowGrace = 5 * time.Second
+)
+
+// pushResult 為排入佇列的結果
+type pushResult int
+
+const (
+	pushQueued pushResult = iota
+	pushCoalesced
+	pushOverflowing
+	pushOverflowed
+	pushClosed
+)
+
+type outbox struct {
+	mu    sync.Mutex
+	items []ServerMessage
+	// ready 於有新訊息或佇列關閉時通知 WritePump
+	ready  chan struct{}
+	closed bool
+	clock  clock.Clock
+	// overSince 為佇列開始超過軟上限的時間，回到上限內即清除
+	overSince time.Time
+}
+
+func newOutbox(clk clock.Clock) *outbox {
+	return &outbox{ready: make(chan struct{}, 1), clock: clk}
+}
+
+// push 排入一則內容已編碼的訊息；同類狀態的舊訊息尚未送出時由新訊息取代
+func (q *outbox) push(msg ServerMessage) pushResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return pushClosed
+	}
+	q.items = append(q.items, msg)
+	result := pushQueued
+	if msg.supersedes != "" && q.dropSupersededLocked(msg.supersedes) {
+		result = pushCoalesced
+	}
+	q.signal()
+
+	switch n := len(q.items); {
+	case n > outboxHardLimit:
+		return pushOverflowed
+	case n > outboxSoftLimit:
+		now := q.clock.Now()
+		if q.overSince.IsZero() {
+			q.overSince = now
+			return pushOverflowing
+		}
+		if now.Sub(q.overSince) >= outboxOverflowGrace {
+			return pushOverflowed
+		}
+	default:
+		q.overSince = time.Time{}
+	}
+	return result
+}
+
+// dropSupersededLocked 移除最後一則之前同鍵的舊訊息。被移除的房間訊息不會送出，
+// 其後第一則房間訊息的 prev 改接被移除者的 prev，客戶端看到的序號鏈因此仍然連續
+func (q *outbox) dropSupersededLocked(key string) bool {
+	last := len(q.items) - 1
+	for i := last - 1; i >= 0; i-- {
+		old := q.items[i]
+		if old.supersedes != key {
+			continue
+		}
+		if old.Seq != 0 {
+			for j := i + 1; j <= last; j++ {
+				if q.items[j].Seq != 0 {
+					q.items[j].Prev = old.Prev
+					break
+				}
+			}
+		}
+		q.items = append(q.items[:i], q.items[i+1:]...)
+		return true
+	}
+	return false
+}
+
+func (q *outbox) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// take 取出目前排入的所有訊息；closed 為真表示佇列已關閉，送完這批後即應結束連線
+func (q *outbox) take() (items []ServerMessage, closed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items, q.items = q.items, nil
+	q.overSince = time.Time{}
+	return items, q.closed
+}
+
+// close 停止接收新訊息；已排入的訊息仍可由 take 取出
+func (q *outbox) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		q.signal()
+	}
+}
+
+func (q *outbox) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// enqueue 編碼訊息內容後排入送出佇列，並記錄合併與壅塞的統計；持續壅塞時關閉連線
+func (c *Client) enqueue(msg ServerMessage) {
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return
+	}
+	msg.Payload = json.RawMessage(payload)
+	switch c.outbox.push(msg) {
+	case pushCoalesced:
+		c.hub.recordCoalesced()
+	case pushOverflowing:
+		c.hub.recordOverflow()
+	case pushOverflowed:
+		if c.overflowed.CompareAndSwap(false, true) {
+			c.hub.recordDrop()
+			// 可能在房間迴圈中排入訊息，關閉連線需另起 goroutine
+			c.hub.background(c.close)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"time"
+
+	"zombierush/internal/i18n"
+)
+
+// votePause 提議暫停或繼續對局；房主提議立即生效，其他玩家需過半真人同意
+func (r *Room) votePause(c *Client, pause bool) error {
+	return r.attempt(func() error {
+		if r.status != RoomStatusRunning {
+			return reject("pause_not_running")
+		}
+		seat := r.getSeatLocked(c.seatIndex)
+		if seat == nil || seat.Client != c {
+			return reject("spectator_cannot_vote")
+		}
+		if pause == r.paused {
+			if pause {
+				return errGamePaused
+			}
+			return reject("not_paused")
+		}
+
+		r.pauseVotes[seat.Index] = struct{}{}
+		votes, needed := r.tallyPauseLocked()
+		if seat.Index != r.hostSeat && votes < needed {
+			id := "log.resume_proposed"
+			if pause {
+				id = "log.pause_proposed"
+			}
+			r.broadcastLogLocked(i18n.M(id, "player", seat.displayName(), "votes", votes, "needed", needed))
+			r.broadcastPublicStateLocked()
+			return nil
+		}
+
+		if pause {
+			r.pauseLocked()
+		} else {
+			r.resumeLocked()
+		}
+		return nil
+	})
+}
+
+// tallyPauseLocked 統計暫停或繼續的票數，只計仍在座的真人，門檻為在座真人過半
+func (r *Room) tallyPauseLocked() (votes, needed int) {
+	humans := 0
+	for _, s := range r.seats {
+		if s.Client == nil {
+			continue
+		}
+		humans++
+		if _, ok := r.pauseVotes[s.Index]; ok {
+			votes++
+		}
+	}
+	return votes, humans/2 + 1
+}
+
+// pauseLocked 凍結對局：停止所有計時器並記下剩餘時限，機器人與玩家皆不得行動
+func (r *Room) pauseLocked() {
+	r.paused = true
+	r.pauseVotes = make(map[int]struct{})
+	r.pauseLeft = 0
+	if !r.turnDeadline.IsZero() {
+		r.pauseLeft = r.turnDeadline.Sub(r.clock.Now())
+	}
+	r.stopTurnTimerLocked()
+	if pending := r.pendingChallenge; pending != nil && pending.timer != nil {
+		r.pauseLeft = pending.deadline.Sub(r.clock.Now())
+		pending.timer.Stop()
+		pending.timer = nil
+		pending.deadline = time.Time{}
+	}
+	r.broadcastLogLocked(i18n.M("log.paused"))
+	r.broadcastPublicStateLocked()
+}
+
+// resumeLocked 解除暫停，以暫停時剩餘的時限繼續目前回合或防守；沒有剩餘時限（例如重新開啟的封存對局）時重新起算
+func (r *Room) resumeLocked() {
+	left := r.pauseLeft
+	r.clearPauseLocked()
+	r.broadcastLogLocked(i18n.M("log.resumed"))
+
+	pending := r.pendingChallenge
+	if pending == nil {
+		seat := r.getSeatLocked(r.currentTurn)
+		switch {
+		case seat == nil:
+		case seat.Bot != nil:
+			r.scheduleBotTurnLocked(seat.Bot)
+		case left > 0:
+			r.armTurnTimerUntilLocked(seat, r.clock.Now().Add(left))
+		default:
+			r.broadcastPublicStateLocked()
+			r.notifyTurnLocked()
+			return
+		}
+		r.broadcastPublicStateLocked()
+		return
+	}
+	if seat := r.getSeatLocked(pending.DefenderSeat); seat != nil && seat.Bot != nil {
+		r.autoDefendLocked()
+		return
+	}
+	if left > 0 {
+		r.armDefenseTimerUntilLocked(pending, r.clock.Now().Add(left))
+	} else {
+		r.armDefenseTimerLocked(pending)
+	}
+	r.broadcastPublicStateLocked()
+}
+
+func (r *Room) ensureNotPausedLocked() error {
+	if r.paused {
+		return errGamePaused
+	}
+	return nil
+}
+
+func (r *Room) clearPauseLocked() {
+	r.paused = false
+	r.pauseVotes = make(map[int]struct{})
+	r.pauseLeft = 0
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 協定版本：版本 2 起房間訊息附帶序號，並可用 resync 取回完整狀態；
+// 版本 3 起公開狀態與大廳列表改送差異
+const (
+	ProtocolVersion    = 3
+	MinProtocolVersion = 1
+)
+
+// NegotiateProtocol 依 /ws 連線時宣告的版本決定本次連線的協定版本；
+// 未宣告視為最舊版本，高於伺服器支援者降為伺服器版本，過舊或無法辨識則拒絕連線
+func NegotiateProtocol(requested string) (int, error) {
+	requested = strings.TrimSpace(requested)
+	if requested == "" {
+		return MinProtocolVersion, nil
+	}
+	version, err := strconv.Atoi(requested)
+	if err != nil || version < MinProtocolVersion {
+		return 0, ErrUnsupportedProtocol
+	}
+	if version > ProtocolVersion {
+		return ProtocolVersion, nil
+	}
+	return version, nil
+}
+
+// SetProtocol 設定連線協定版本，需在開始讀寫前呼叫
+func (c *Client) SetProtocol(version int) {
+	c.protocol = version
+}
+
+// nextSeqLocked 取得房間下一個序號；同一事件廣播給所有人時共用同一個序號
+func (r *Room) nextSeqLocked() int64 {
+	r.seq++
+	return r.seq
+}
+
+// deliverLocked 為房間訊息標上序號與此連線上一則房間訊息的序號後送出；
+// 客戶端比對 prev 與自己最後收到的序號即可察覺漏收
+func (r *Room) deliverLocked(c *Client, msg ServerMessage, seq int64) {
+	msg.Seq = seq
+	msg.Prev = c.lastSeq
+	c.lastSeq = seq
+	c.enqueue(msg)
+}
+
+// sendLocked 將房間訊息單獨送給一位連線
+func (r *Room) sendLocked(c *Client, msg ServerMessage) {
+	r.deliverLocked(c, msg, r.nextSeqLocked())
+}
+
+// resync 回覆房間目前的完整公開狀態，入座者另附私人狀態；防守中的玩家會再收到一次防守提示
+func (r *Room) resync(c *Client) error {
+	return r.attempt(func() error {
+		if c.currentRoom() != r {
+			return errNotInRoom
+		}
+		update, err := r.recordPublicStateLocked(c.locale)
+		if err != nil {
+			return err
+		}
+		payload := ResyncPayload{Public: update.full, Version: update.version}
+		seat := r.getSeatLocked(c.seatIndex)
+		if seat != nil && seat.Client == c {
+			payload.Private = &PrivateStatePayload{}
+			if r.game != nil {
+				snapshot, err := r.game.BuildPrivateSnapshot(seat.Index, c.locale)
+				if err != nil {
+					return err
+				}
+				payload.Private.Snapshot = snapshot
+			}
+		}
+		r.sendLocked(c, ServerMessage{Type: "resync", Payload: payload})
+
+		if seat != nil && seat.Client == c && r.status == RoomStatusRunning && !r.paused {
+			if pending := r.pendingChallenge; pending != nil && pending.DefenderSeat == seat.Index {
+				r.sendPendingPromptLocked(seat)
+			}
+		}
+		return nil
+	})
+}
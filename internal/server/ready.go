@@ -0,0 +1,69 @@
+package server
+
+import "zombierush/internal/i18n"
+
+const defaultMinHumans = 1
+
+// setReady 切換玩家的準備狀態
+func (r *Room) setReady(c *Client, ready bool) error {
+	return r.attempt(func() error {
+		if r.status != RoomStatusLobby {
+			return reject("ready_not_lobby")
+		}
+		seat := r.getSeatLocked(c.seatIndex)
+		if seat == nil || seat.Client != c {
+			return errNotSeated
+		}
+		seat.Ready = ready
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return nil
+	})
+}
+
+// setMinHumans 設定開局所需的最少真人數
+func (r *Room) setMinHumans(host *Client, n int) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.min_humans"); err != nil {
+			return err
+		}
+		if r.status != RoomStatusLobby {
+			return reject("min_humans_not_lobby")
+		}
+		if n < 1 || n > r.capacity {
+			return reject("min_humans_range", "max", r.capacity)
+		}
+		r.minHumans = n
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return nil
+	})
+}
+
+// ensureReadyLocked 檢查開局條件：真人數達門檻，且除房主外的真人皆已準備
+func (r *Room) ensureReadyLocked(force bool) error {
+	humans := 0
+	waiting := make([]string, 0)
+	for _, seat := range r.seats {
+		if seat.Client == nil {
+			continue
+		}
+		humans++
+		if seat.Index != r.hostSeat && !seat.Ready {
+			waiting = append(waiting, seat.displayName())
+		}
+	}
+	if humans < r.minHumans {
+		return reject("not_enough_humans", "humans", humans, "required", r.minHumans)
+	}
+	if !force && len(waiting) > 0 {
+		return reject("players_not_ready", "players", i18n.List(waiting))
+	}
+	return nil
+}
+
+func (r *Room) clearReadyLocked() {
+	for _, seat := range r.seats {
+		seat.Ready = false
+	}
+}
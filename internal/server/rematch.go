@@ -0,0 +1,151 @@
+package server
+
+import (
+	"time"
+
+	"zombierush/internal/clock"
+	"zombierush/internal/i18n"
+)
+
+const (
+	RematchVoteRematch = "rematch"
+	RematchVoteLeave   = "leave"
+
+	postGameTimeout = 2 * time.Minute
+)
+
+// postGameState 記錄對局結束後的勝方與再戰投票；勝方於送出時依收訊者語系呈現
+type postGameState struct {
+	winner   i18n.Message
+	votes    map[int]string
+	deadline time.Time
+	timer    clock.Timer
+}
+
+func (r *Room) beginPostGameLocked(winner i18n.Message) {
+	r.endPostGameLocked()
+	state := &postGameState{
+		winner:   winner,
+		votes:    make(map[int]string),
+		deadline: r.clock.Now().Add(postGameTimeout),
+	}
+	state.timer = r.clock.AfterFunc(postGameTimeout, func() {
+		r.do(func() {
+			if r.postGame != state || r.status != RoomStatusFinished {
+				return
+			}
+			r.broadcastLogLocked(i18n.M("log.rematch_timeout"))
+			r.resetToLobbyLocked()
+		})
+	})
+	r.postGame = state
+}
+
+func (r *Room) endPostGameLocked() {
+	if r.postGame == nil {
+		return
+	}
+	if r.postGame.timer != nil {
+		r.postGame.timer.Stop()
+	}
+	r.postGame = nil
+}
+
+// tallyRematchLocked 統計再戰票數，門檻為在座真人過半
+func (r *Room) tallyRematchLocked() (rematch, leave, required int) {
+	humans := 0
+	for _, seat := range r.seats {
+		if seat.Client == nil {
+			continue
+		}
+		humans++
+		switch r.postGame.votes[seat.Index] {
+		case RematchVoteRematch:
+			rematch++
+		case RematchVoteLeave:
+			leave++
+		}
+	}
+	required = humans/2 + 1
+	return
+}
+
+func (r *Room) buildPostGamePayloadLocked(loc i18n.Locale) *PostGamePayload {
+	if r.status != RoomStatusFinished || r.postGame == nil {
+		return nil
+	}
+	rematch, leave, required := r.tallyRematchLocked()
+	votes := make(map[int]string, len(r.postGame.votes))
+	for idx, vote := range r.postGame.votes {
+		votes[idx] = vote
+	}
+	return &PostGamePayload{
+		Winner:        r.postGame.winner.Localize(loc),
+		Votes:         votes,
+		RematchVotes:  rematch,
+		LeaveVotes:    leave,
+		Required:      required,
+		QuorumReached: rematch >= required,
+		Deadline:      r.postGame.deadline.UnixMilli(),
+	}
+}
+
+// castRematchVote 記錄玩家的再戰意向
+func (r *Room) castRematchVote(c *Client, vote string) error {
+	return r.attempt(func() error {
+		if r.status != RoomStatusFinished || r.postGame == nil {
+			return reject("not_post_game")
+		}
+		if vote != RematchVoteRematch && vote != RematchVoteLeave {
+			return reject("invalid_vote")
+		}
+		seat := r.getSeatLocked(c.seatIndex)
+		if seat == nil || seat.Client != c {
+			return errNotSeated
+		}
+		r.postGame.votes[seat.Index] = vote
+		r.broadcastPublicStateLocked()
+		return nil
+	})
+}
+
+// startRematch 在再戰票數過半後由房主立即開局，回傳投票離開而被請回大廳的玩家
+func (r *Room) startRematch(host *Client) ([]*Client, error) {
+	return attemptWith(r, func() ([]*Client, error) {
+		if err := r.ensureHostLocked(host, "error.host_only.rematch"); err != nil {
+			return nil, err
+		}
+		if r.status != RoomStatusFinished || r.postGame == nil {
+			return nil, reject("not_post_game")
+		}
+		rematch, _, required := r.tallyRematchLocked()
+		if rematch < required {
+			return nil, reject("rematch_votes", "votes", rematch, "required", required)
+		}
+
+		departed := make([]*Client, 0)
+		for _, seat := range r.seats {
+			if seat.Client == nil || r.postGame.votes[seat.Index] != RematchVoteLeave {
+				continue
+			}
+			departed = append(departed, r.detachClientLocked(seat))
+		}
+
+		r.resetToLobbyLocked()
+		return departed, r.startGameLocked()
+	})
+}
+
+// returnToLobby 由房主結束賽後階段並返回待機
+func (r *Room) returnToLobby(host *Client) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.return_lobby"); err != nil {
+			return err
+		}
+		if r.status != RoomStatusFinished {
+			return reject("game_not_finished")
+		}
+		r.resetToLobbyLocked()
+		return nil
+	})
+}
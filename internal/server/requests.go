@@ -0,0 +1,158 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"zombierush/internal/i18n"
+)
+
+const (
+	requestDedupWindow = 2 * time.Minute
+	maxRequestsPerUser = 256
+)
+
+// requestResult 記錄一筆帶有請求 ID 的指令結果，供重送時直接回覆
+type requestResult struct {
+	done bool
+	err  error
+	at   time.Time
+}
+
+// requestLog 依帳號保存近期處理過的請求 ID；以帳號而非連線區分，斷線重連後重送仍可辨識
+type requestLog struct {
+	mu     sync.Mutex
+	byUser map[int64]map[string]*requestResult
+	// swept 為上次清理所有帳號的時間；之後不再送出請求的帳號也會在下次清理時移除
+	swept time.Time
+}
+
+func newRequestLog() *requestLog {
+	return &requestLog{byUser: make(map[int64]map[string]*requestResult)}
+}
+
+// begin 於 now 登記新請求；若相同 ID 已在時限內處理過或處理中，回傳先前的結果與 true
+func (l *requestLog) begin(userID int64, id string, now time.Time) (requestResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) > requestDedupWindow {
+		l.sweepLocked(now)
+	}
+	entries := l.byUser[userID]
+	if entries == nil {
+		entries = make(map[string]*requestResult)
+		l.byUser[userID] = entries
+	}
+	pruneRequests(entries, now)
+	if prior, ok := entries[id]; ok {
+		return *prior, true
+	}
+	if len(entries) >= maxRequestsPerUser {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range entries {
+			if oldestKey == "" || entry.at.Before(oldest) {
+				oldestKey, oldest = key, entry.at
+			}
+		}
+		delete(entries, oldestKey)
+	}
+	entries[id] = &requestResult{at: now}
+	return requestResult{}, false
+}
+
+// sweepLocked 移除所有帳號過期的請求，清空的帳號一併移除
+func (l *requestLog) sweepLocked(now time.Time) {
+	l.swept = now
+	for userID, entries := range l.byUser {
+		pruneRequests(entries, now)
+		if len(entries) == 0 {
+			delete(l.byUser, userID)
+		}
+	}
+}
+
+func pruneRequests(entries map[string]*requestResult, now time.Time) {
+	for key, entry := range entries {
+		if now.Sub(entry.at) > requestDedupWindow {
+			delete(entries, key)
+		}
+	}
+}
+
+// users 回傳仍保存請求紀錄的帳號數
+func (l *requestLog) users() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.byUser)
+}
+
+// finish 記錄請求結果，err 為 nil 表示成功
+func (l *requestLog) finish(userID int64, id string, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, ok := l.byUser[userID][id]; ok {
+		entry.done = true
+		entry.err = err
+	}
+}
+
+// handleRequest 處理帶有請求 ID 的指令：重複的 ID 不再執行，只補發先前的結果；
+// 指令回傳的錯誤改以 nack 回覆，成功則回覆 ack
+func (c *Client) handleRequest(msg ClientMessage) {
+	if prior, duplicate := c.hub.requests.begin(c.userID, msg.RequestID, c.hub.currentClock().Now()); duplicate {
+		if prior.done {
+			prior.reply(c, msg.RequestID, true)
+		}
+		return
+	}
+
+	c.beginRequestContext(msg.RequestID)
+	c.dispatch(msg)
+	err := c.endRequestContext()
+
+	c.hub.requests.finish(c.userID, msg.RequestID, err)
+	requestResult{done: true, err: err}.reply(c, msg.RequestID, false)
+}
+
+// beginRequestContext 開始處理帶有請求 ID 的指令，之後指令回傳的錯誤記入該請求
+func (c *Client) beginRequestContext(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requestID = id
+	c.requestError = nil
+}
+
+// endRequestContext 結束請求並回傳處理期間的第一個錯誤
+func (c *Client) endRequestContext() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := c.requestError
+	c.requestID = ""
+	c.requestError = nil
+	return err
+}
+
+// recordRequestError 於連線正在處理請求時記下指令回傳的錯誤並回傳真，由 nack 取代個別的錯誤訊息；
+// 只由處理指令的 goroutine 經 sendError 呼叫
+func (c *Client) recordRequestError(err error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.requestID == "" {
+		return false
+	}
+	if c.requestError == nil {
+		c.requestError = err
+	}
+	return true
+}
+
+func (res requestResult) reply(c *Client, id string, duplicate bool) {
+	if res.err != nil {
+		c.sendMessage(ServerMessage{Type: "nack", Payload: RequestReplyPayload{RequestID: id, Code: ErrorCode(res.err), Message: i18n.ErrorText(res.err, c.locale), Duplicate: duplicate}})
+		return
+	}
+	c.sendMessage(ServerMessage{Type: "ack", Payload: RequestReplyPayload{RequestID: id, Duplicate: duplicate}})
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"zombierush/internal/clock"
 	"zombierush/internal/game"
+	"zombierush/internal/i18n"
 )
 
 const (
@@ -18,24 +20,69 @@ const (
 	RoomStatusFinished = "finished"
 )
 
-// Room 負責管理單一遊戲房間的生命週期
+const defaultRoomCapacity = 8
+
+// Room 負責管理單一遊戲房間的生命週期；狀態只由房間迴圈（見 loop.go）讀寫
 type Room struct {
 	id       string
 	name     string
 	hub      *Hub
-	mu       sync.Mutex
 	status   string
 	seats    []*Seat
 	capacity int
 	hostSeat int
 	game     *game.Game
 
+	minHumans int
+	locked    bool
+	banned    map[int64]struct{}
+
+	settings   RoomSettings
+	password   string
+	inviteCode string
+	spectators map[*Client]struct{}
+
+	// swapRequests 記錄待回應的換位請求：提出者座位 -> 對象座位
+	swapRequests map[int]int
+	// claimRequests 記錄待房主審核的機器人座位接手申請：座位 -> 申請者
+	claimRequests map[int]*Client
+
 	currentTurn  int
 	currentRound int
 
 	pendingChallenge *pendingChallenge
-
+	postGame         *postGameState
+
+	paused     bool
+	pauseVotes map[int]struct{}
+	pauseLeft  time.Duration // 暫停時目前回合或防守剩餘的時限，繼續時沿用
+	restored   bool          // 由封存對局重新開啟
+	archive    GameArchive
+
+	turnSerial   int
+	turnTimer    clock.Timer
+	turnDeadline time.Time
+	// turnDue 為目前回合的時限，座位由 AI 代打而未計時時同樣記錄；真人回到座位時沿用，不重新起算
+	turnDue time.Time
+	// idleTimer 為由保存紀錄載入後、無人連線時的卸載計時
+	idleTimer clock.Timer
+
+	// seq 為房間訊息的序號，每則送出的房間訊息遞增
+	seq int64
+	// publicStates 依語系保存近期的公開狀態版本，作為差異廣播的基準；狀態中的勝方等文字依收訊者語系呈現
+	publicStates map[i18n.Locale]*stateHistory
+
+	// rng 為房間的亂數來源；開局時改以該局的種子重設，機器人的選擇因此可由種子重現
 	rng *rand.Rand
+
+	// listing 為最近發布的大廳摘要，由房間迴圈寫入、Hub 讀取
+	listing atomic.Pointer[roomListing]
+
+	clock    clock.Clock
+	commands chan func()
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 // Seat 表示一個座位資訊
@@ -43,6 +90,10 @@ type Seat struct {
 	Index  int
 	Name   string
 	Token  string
+	Ready  bool
+	Away   bool  // 仍連線但因閒置改由 AI 代打
+	Missed int   // 連續逾時次數
+	UserID int64 // 入座帳號，對局中斷線後仍保留以便依帳號取回座位
 	Client *Client
 	Bot    *BotPlayer
 	Player *game.Player
@@ -52,11 +103,21 @@ func (s *Seat) isFilled() bool {
 	return s.Client != nil || s.Bot != nil
 }
 
+// isOffline 表示通信對局中已離線但仍保有座位的真人
+func (s *Seat) isOffline() bool {
+	return s.Client == nil && s.Bot == nil && s.UserID != 0
+}
+
 func (s *Seat) displayBaseName() string {
 	if s.Name != "" {
 		return s.Name
 	}
-	return fmt.Sprintf("座位%d", s.Index)
+	return i18n.M("name.seat", "number", s.Index).String()
+}
+
+// botName 為補位機器人的預設名稱；名稱會寫入對局與紀錄，因此一律使用預設語系
+func botName(seatIndex int) string {
+	return i18n.M("name.bot", "number", seatIndex+1).String()
 }
 
 func (s *Seat) displayName() string {
@@ -78,7 +139,11 @@ func (r *Room) firstEmptySeatLocked() *Seat {
 	return nil
 }
 
+// assignHostLocked 在房主座位無人時重新指派；現任房主（含轉讓取得者）仍在座時維持不變
 func (r *Room) assignHostLocked() {
+	if seat := r.getSeatLocked(r.hostSeat); seat != nil && seat.Client != nil {
+		return
+	}
 	r.hostSeat = -1
 	for _, seat := range r.seats {
 		if seat.Client != nil {
@@ -94,67 +159,77 @@ func (r *Room) assignHostLocked() {
 	}
 }
 
-func (r *Room) isHost(c *Client) bool {
-	if c == nil {
-		return false
+// ensureHostLocked 確認 c 為房主，detail 為拒絕時的說明；與房主專屬操作在同一個指令中檢查，
+// 檢查與執行之間房主不會易手
+func (r *Room) ensureHostLocked(c *Client, detail string) error {
+	if c == nil || c.seatIndex < 0 || c.seatIndex != r.hostSeat {
+		return errHostOnly.withDetail(detail)
 	}
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	return c.seatIndex == r.hostSeat
+	return nil
 }
 
-func (r *Room) addBot(name string) (int, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *Room) addBot(host *Client, name string) (int, error) {
+	return attemptWith(r, func() (int, error) {
+		if err := r.ensureHostLocked(host, "error.host_only.add_bot"); err != nil {
+			return -1, err
+		}
+		if r.status != RoomStatusLobby {
+			return -1, reject("bots_lobby_only")
+		}
+		seat := r.firstEmptySeatLocked()
+		if seat == nil {
+			return -1, errRoomFull
+		}
+		if name == "" {
+			name = botName(seat.Index)
+		}
+		seat.Bot = &BotPlayer{SeatIndex: seat.Index, Name: name, KnownZombies: make(map[int]struct{})}
+		seat.Name = name
+		seat.Token = fmt.Sprintf("bot-%d-%d", seat.Index, r.rng.Int63())
+		seat.Client = nil
+		if r.hostSeat == -1 {
+			r.hostSeat = seat.Index
+		}
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return seat.Index, nil
+	})
+}
 
-	if r.status != RoomStatusLobby {
-		return -1, fmt.Errorf("僅能在待機狀態新增機器人")
-	}
-	seat := r.firstEmptySeatLocked()
-	if seat == nil {
-		return -1, fmt.Errorf("房間已滿")
-	}
-	if name == "" {
-		name = fmt.Sprintf("機器人%d", seat.Index+1)
-	}
-	seat.Bot = &BotPlayer{SeatIndex: seat.Index, Name: name, KnownZombies: make(map[int]struct{})}
-	seat.Name = name
-	seat.Token = fmt.Sprintf("bot-%d-%d", seat.Index, r.rng.Int63())
-	seat.Client = nil
-	if r.hostSeat == -1 {
-		r.hostSeat = seat.Index
-	}
-	r.broadcastLobbyLocked()
-	r.broadcastPublicStateLocked()
-	return seat.Index, nil
+func (r *Room) removeBot(host *Client, seatIdx int) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.remove_bot"); err != nil {
+			return err
+		}
+		if seatIdx < 0 || seatIdx >= len(r.seats) {
+			return errInvalidSeat
+		}
+		seat := r.seats[seatIdx]
+		if seat.Bot == nil {
+			return reject("seat_no_bot", "seat", seatIdx)
+		}
+		seat.Bot = nil
+		if seat.Client == nil {
+			seat.Name = ""
+			seat.Token = ""
+		}
+		if r.hostSeat == seatIdx {
+			r.assignHostLocked()
+		}
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return nil
+	})
 }
 
-func (r *Room) removeBot(seatIdx int) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if seatIdx < 0 || seatIdx >= len(r.seats) {
-		return fmt.Errorf("無效座位")
-	}
-	seat := r.seats[seatIdx]
-	if seat.Bot == nil {
-		return fmt.Errorf("座位 %d 沒有機器人", seatIdx)
-	}
-	seat.Bot = nil
-	if seat.Client == nil {
-		seat.Name = ""
-		seat.Token = ""
-	}
-	if r.hostSeat == seatIdx {
-		r.assignHostLocked()
-	}
-	r.broadcastLobbyLocked()
-	r.broadcastPublicStateLocked()
-	return nil
+// roomListing 為房間發布給大廳的摘要；listed 為假的私人房間不列入大廳列表，bots 供配對判斷
+type roomListing struct {
+	summary RoomSummary
+	listed  bool
+	bots    int
 }
 
-func (r *Room) summary() RoomSummary {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *Room) summaryLocked() RoomSummary {
 	if r.hostSeat < 0 || r.hostSeat >= len(r.seats) {
 		r.assignHostLocked()
 	}
@@ -169,155 +244,270 @@ func (r *Room) summary() RoomSummary {
 		}
 	}
 	return RoomSummary{
-		RoomID:   r.id,
-		Name:     r.name,
-		Status:   r.status,
-		Players:  players,
-		Capacity: r.capacity,
-		Host:     hostName,
+		RoomID:      r.id,
+		Name:        r.name,
+		Status:      r.status,
+		Players:     players,
+		Capacity:    r.capacity,
+		Host:        hostName,
+		Locked:      r.locked,
+		HasPassword: r.password != "",
+		Settings:    r.settings.view(),
+		Spectators:  len(r.spectators),
 	}
 }
 
-func (r *Room) isEmpty() bool {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// publishListingLocked 更新發布的大廳摘要；內容有變動時請 Hub 重新推送房間列表
+func (r *Room) publishListingLocked() {
+	next := roomListing{summary: r.summaryLocked(), listed: r.settings.Visibility != RoomVisibilityPrivate}
 	for _, seat := range r.seats {
-		if seat.isFilled() {
-			return false
+		if seat.Bot != nil {
+			next.bots++
 		}
 	}
-	return true
+	prev := r.listing.Load()
+	if prev != nil && *prev == next {
+		return
+	}
+	r.listing.Store(&next)
+	if prev != nil && r.hub != nil {
+		r.hub.background(r.hub.refreshLobby)
+	}
+}
+
+// published 回傳最近發布的大廳摘要，可在任何 goroutine 呼叫
+func (r *Room) published() roomListing {
+	return *r.listing.Load()
+}
+
+func (r *Room) isEmpty() bool {
+	return query(r, func() bool {
+		for _, seat := range r.seats {
+			if seat.isFilled() {
+				return false
+			}
+		}
+		return true
+	})
 }
 
-// pendingChallenge 暫存待防守者回應的挑戰
+// pendingChallenge 暫存待防守者回應的挑戰；攻擊牌以牌編號記錄，不受手牌排序影響
 type pendingChallenge struct {
-	AttackerSeat  int
-	DefenderSeat  int
-	AttackerCards []int
-	AttackKind    game.CardKind
-	AttackSuit    game.Suit
+	AttackerSeat    int
+	DefenderSeat    int
+	AttackerCardIDs []int
+	AttackKind      game.CardKind
+	AttackSuit      game.Suit
+
+	deadline time.Time
+	timer    clock.Timer
 }
 
-// NewRoom 建立房間
+// NewRoom 建立房間並啟動房間迴圈
 func NewRoom(id, name string, capacity int, hub *Hub) *Room {
+	r := newRoom(id, name, capacity, hub)
+	r.start()
+	return r
+}
+
+// newRoom 建立尚未啟動的房間；迴圈啟動前可直接設定狀態，之後只能經由房間迴圈讀寫
+func newRoom(id, name string, capacity int, hub *Hub) *Room {
 	if capacity <= 0 {
-		capacity = 8
+		capacity = defaultRoomCapacity
 	}
 	seats := make([]*Seat, capacity)
 	for i := 0; i < capacity; i++ {
 		seats[i] = &Seat{Index: i}
 	}
-	return &Room{
-		id:       id,
-		name:     name,
-		hub:      hub,
-		status:   RoomStatusLobby,
-		seats:    seats,
-		capacity: capacity,
-		hostSeat: -1,
-		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
-	}
+	clk := clock.Real()
+	if hub != nil {
+		clk = hub.currentClock()
+	}
+	r := &Room{
+		id:            id,
+		name:          name,
+		hub:           hub,
+		status:        RoomStatusLobby,
+		seats:         seats,
+		capacity:      capacity,
+		hostSeat:      -1,
+		minHumans:     defaultMinHumans,
+		banned:        make(map[int64]struct{}),
+		settings:      defaultRoomSettings(),
+		spectators:    make(map[*Client]struct{}),
+		swapRequests:  make(map[int]int),
+		claimRequests: make(map[int]*Client),
+		pauseVotes:    make(map[int]struct{}),
+		rng:           rand.New(rand.NewSource(clk.Now().UnixNano())),
+		publicStates:  make(map[i18n.Locale]*stateHistory),
+		clock:         clk,
+		commands:      make(chan func(), roomCommandBuffer),
+		quit:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	return r
+}
+
+// start 發布房間的大廳摘要並啟動房間迴圈
+func (r *Room) start() {
+	r.publishListingLocked()
+	go r.run()
+}
+
+// Join 將玩家加入座位；原座位重連不需憑證，新玩家需通過房間的存取設定。
+// 已在其他房間（包含同時由配對加入）的連線會被拒絕
+func (r *Room) Join(c *Client, opts JoinOptions) error {
+	return r.attempt(func() error {
+		if !c.reserveRoom(r) {
+			return errAlreadyInRoom
+		}
+		err := r.joinLocked(c, opts)
+		if err != nil {
+			c.releaseRoom(r)
+		}
+		return err
+	})
 }
 
-// Join 將玩家加入座位
-func (r *Room) Join(c *Client) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// joinLocked 在房間迴圈內完成加入，呼叫前連線已保留給此房間
+func (r *Room) joinLocked(c *Client, opts JoinOptions) error {
+	if r.isBannedLocked(c) {
+		return errBanned
+	}
 
-	// 嘗試以 token 找到原座位進行重連
+	// 嘗試以 token 找到原座位進行重連；座位屬於其他帳號時拒絕
 	if c.token != "" {
 		for _, seat := range r.seats {
 			if seat.Token == c.token {
-				if seat.Client != nil {
-					return fmt.Errorf("該座位已有連線")
+				if seat.UserID != c.userID {
+					return reject("seat_token_mismatch")
 				}
-				seat.Client = c
-				seat.Bot = nil
-				if seat.Name != "" {
-					c.name = seat.Name
-				} else {
-					seat.Name = c.name
-				}
-				if seat.Token == "" {
-					seat.Token = fmt.Sprintf("seat-%d-%d", seat.Index, r.rng.Int63())
-				}
-				c.token = seat.Token
-				c.room = r
-				c.seatIndex = seat.Index
-				c.inLobby = false
-				r.assignHostLocked()
-				r.sendWelcomeLocked(c)
-				r.broadcastLobbyLocked()
-				r.broadcastPublicStateLocked()
-				if r.game != nil {
-					r.sendPrivateStateLocked(seat.Index)
+				if seat.Client != nil {
+					return reject("seat_connected")
 				}
+				r.reconnectSeatLocked(c, seat)
 				return nil
 			}
 		}
 	}
+	// 依帳號取回原座位，換裝置或遺失 token 時也能重連；每個帳號在房間內至多一個座位
+	if seat := r.seatByUserLocked(c.userID); seat != nil {
+		if seat.Client != nil {
+			return reject("already_seated")
+		}
+		r.reconnectSeatLocked(c, seat)
+		return nil
+	}
 
 	if r.status != RoomStatusLobby {
-		return fmt.Errorf("遊戲已開始或結束，無法加入")
+		return reject("game_in_progress")
+	}
+	if r.locked {
+		return reject("room_locked")
+	}
+	if err := r.checkAccessLocked(opts); err != nil {
+		return err
 	}
 
-	for _, seat := range r.seats {
-		if !seat.isFilled() {
-			seat.Client = c
-			seat.Bot = nil
-			seat.Name = c.name
-			token := c.token
-			if token == "" {
-				token = fmt.Sprintf("seat-%d-%d", seat.Index, r.rng.Int63())
-			}
-			seat.Token = token
-			c.token = token
-			c.room = r
-			c.seatIndex = seat.Index
-			c.inLobby = false
-			r.assignHostLocked()
-			r.sendWelcomeLocked(c)
-			r.broadcastLobbyLocked()
-			r.broadcastPublicStateLocked()
-			return nil
-		}
+	seat, err := r.takeSeatLocked(opts.Seat)
+	if err != nil {
+		return err
 	}
+	seat.Client = c
+	seat.Bot = nil
+	seat.Name = c.name
+	seat.Ready = false
+	seat.UserID = c.userID
+	seat.Token = newSeatToken()
+	c.token = seat.Token
+	c.setRoom(r, seat.Index)
+	r.assignHostLocked()
+	r.sendWelcomeLocked(c)
+	r.broadcastLobbyLocked()
+	r.broadcastPublicStateLocked()
+	return nil
+}
 
-	return fmt.Errorf("房間已滿")
+func (r *Room) reconnectSeatLocked(c *Client, seat *Seat) {
+	seat.Client = c
+	seat.Bot = nil
+	seat.Away = false
+	seat.UserID = c.userID
+	if seat.Name != "" {
+		c.name = seat.Name
+	} else {
+		seat.Name = c.name
+	}
+	if seat.Token == "" {
+		seat.Token = newSeatToken()
+	}
+	c.token = seat.Token
+	c.setRoom(r, seat.Index)
+	delete(r.claimRequests, seat.Index)
+	r.assignHostLocked()
+	r.sendWelcomeLocked(c)
+	r.broadcastLobbyLocked()
+	r.broadcastPublicStateLocked()
+	r.resumeSeatLocked(seat)
 }
 
 func (r *Room) onClientLeft(c *Client) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	r.do(func() {
+		delete(r.spectators, c)
+		r.dropClaimsByLocked(c)
+		if seat := r.getSeatLocked(c.seatIndex); seat != nil && seat.Client == c {
+			r.vacateSeatLocked(seat)
+		}
 
-	if c.seatIndex >= 0 && c.seatIndex < len(r.seats) {
-		seat := r.seats[c.seatIndex]
-		if seat.Client == c {
-			seat.Client = nil
-			if seat.Name == "" {
-				seat.Name = c.name
-			}
-			if r.status == RoomStatusRunning {
-				if seat.Bot == nil {
-					seat.Bot = &BotPlayer{SeatIndex: seat.Index, Name: fmt.Sprintf("%s (AI)", seat.displayBaseName()), KnownZombies: make(map[int]struct{})}
-				}
-				if r.pendingChallenge == nil && r.currentTurn == seat.Index {
-					go r.executeBotTurn(seat.Bot)
-				}
-			} else {
-				seat.Bot = nil
-				seat.Name = ""
-				seat.Token = ""
-			}
-			if seat.Index == r.hostSeat {
-				r.assignHostLocked()
-			}
+		if c.currentRoom() == r {
+			c.setRoom(nil, -1)
 		}
-	}
 
-	r.broadcastLobbyLocked()
-	if r.game != nil {
-		r.broadcastPublicStateLocked()
+		r.broadcastLobbyLocked()
+		if r.game != nil {
+			r.broadcastPublicStateLocked()
+		}
+		// 重新開啟的封存對局只剩 AI 佔位時不會自行清空，逾時仍無人回座就再次封存
+		if r.restored && !r.settings.Correspondence && !r.hasConnectedLocked() {
+			r.armIdleReleaseLocked()
+		}
+	})
+}
+
+// vacateSeatLocked 讓真人離開座位；對局進行中改由 AI 接手（通信對局則保留座位），否則清空座位
+func (r *Room) vacateSeatLocked(seat *Seat) {
+	c := seat.Client
+	seat.Client = nil
+	seat.Ready = false
+	r.dropSwapRequestsLocked(seat.Index)
+	delete(r.pauseVotes, seat.Index)
+	if seat.Name == "" && c != nil {
+		seat.Name = c.name
+	}
+	switch {
+	case r.status == RoomStatusRunning && r.settings.Correspondence && seat.isOffline():
+		// 通信對局保留座位，期限內未回來才由系統代為行動
+	case r.status == RoomStatusRunning:
+		if seat.Bot == nil {
+			seat.Bot = &BotPlayer{SeatIndex: seat.Index, Name: fmt.Sprintf("%s (AI)", seat.displayBaseName()), KnownZombies: make(map[int]struct{})}
+		}
+		// 暫停期間不代為行動，繼續時再由 resumeLocked 接手
+		if r.pendingChallenge != nil && r.pendingChallenge.DefenderSeat == seat.Index && !r.paused {
+			r.autoDefendLocked()
+		} else if r.pendingChallenge == nil && r.currentTurn == seat.Index {
+			r.scheduleBotTurnLocked(seat.Bot)
+		}
+	default:
+		seat.Bot = nil
+		seat.Name = ""
+		seat.Token = ""
+		seat.UserID = 0
+		if r.postGame != nil {
+			delete(r.postGame.votes, seat.Index)
+		}
+	}
+	if seat.Index == r.hostSeat {
+		r.assignHostLocked()
 	}
 }
 
@@ -328,7 +518,7 @@ func (r *Room) sendWelcomeLocked(c *Client) {
 			token = seat.Token
 		}
 	}
-	payload := ServerMessage{Type: "welcome", Payload: map[string]interface{}{
+	welcome := map[string]interface{}{
 		"roomId":      r.id,
 		"roomName":    r.name,
 		"seatIndex":   c.seatIndex,
@@ -338,27 +528,33 @@ func (r *Room) sendWelcomeLocked(c *Client) {
 		"displayName": c.name,
 		"account":     c.account,
 		"userId":      c.userID,
-	}}
-	data, err := json.Marshal(payload)
-	if err != nil {
-		return
-	}
-	select {
-	case c.send <- data:
-	default:
-		go c.close()
-	}
+		"spectator":   r.isSpectatorLocked(c),
+		"protocol":    c.protocol,
+	}
+	// 邀請碼可略過密碼，只交給入座者分享，觀戰者拿不到
+	if c.seatIndex >= 0 {
+		welcome["inviteCode"] = r.inviteCode
+	}
+	payload := ServerMessage{Type: "welcome", Payload: welcome}
+	// 歡迎訊息開啟新的房間訊息序列，客戶端以此重新起算序號與狀態版本
+	c.lastSeq = 0
+	c.publicAcked = 0
+	r.sendLocked(c, payload)
 }
 
 func (r *Room) broadcastLobbyLocked() {
 	msg := ServerMessage{
 		Type: "lobby_state",
 		Payload: PublicRoomStatePayload{
-			RoomID:   r.id,
-			RoomName: r.name,
-			Status:   r.status,
-			Seats:    r.buildSeatSnapshotsLocked(),
-			HostSeat: r.hostSeat,
+			RoomID:    r.id,
+			RoomName:  r.name,
+			Status:    r.status,
+			Seats:     r.buildSeatSnapshotsLocked(),
+			HostSeat:  r.hostSeat,
+			MinHumans: r.minHumans,
+			Locked:    r.locked,
+			Access:    r.buildAccessPayloadLocked(),
+			Settings:  r.settings.view(),
 		},
 	}
 	r.broadcastLocked(msg)
@@ -368,11 +564,14 @@ func (r *Room) buildSeatSnapshotsLocked() []SeatPublicSnapshot {
 	seats := make([]SeatPublicSnapshot, 0, len(r.seats))
 	for _, seat := range r.seats {
 		snapshot := SeatPublicSnapshot{
-			Index:  seat.Index,
-			Name:   seat.displayName(),
-			Filled: seat.isFilled(),
-			IsBot:  seat.Bot != nil,
-			IsHost: seat.Index == r.hostSeat,
+			Index:   seat.Index,
+			Name:    seat.displayName(),
+			Filled:  seat.isFilled() || seat.isOffline(),
+			IsBot:   seat.Bot != nil,
+			Away:    seat.Away,
+			Offline: seat.isOffline(),
+			IsHost:  seat.Index == r.hostSeat,
+			Ready:   seat.Bot != nil || seat.Ready,
 		}
 		if r.game != nil && seat.Player != nil {
 			alive := seat.Player.Alive
@@ -388,49 +587,121 @@ func (r *Room) buildSeatSnapshotsLocked() []SeatPublicSnapshot {
 }
 
 func (r *Room) broadcastLocked(msg ServerMessage) {
-	payload, err := json.Marshal(msg)
+	payload, err := json.Marshal(msg.Payload)
 	if err != nil {
 		return
 	}
-	recipients := r.collectClientsLocked()
-	for _, c := range recipients {
-		select {
-		case c.send <- payload:
-		default:
-			go c.close()
+	seq := r.nextSeqLocked()
+	for _, c := range r.collectClientsLocked() {
+		r.deliverLocked(c, ServerMessage{Type: msg.Type, Payload: json.RawMessage(payload)}, seq)
+	}
+}
+
+// broadcastLocalizedLocked 依每位收訊者的語系產生訊息，同語系的收訊者共用編碼結果
+func (r *Room) broadcastLocalizedLocked(render func(loc i18n.Locale) ServerMessage) {
+	type rendered struct {
+		kind    string
+		payload json.RawMessage
+	}
+	encoded := make(map[i18n.Locale]rendered)
+	seq := r.nextSeqLocked()
+	for _, c := range r.collectClientsLocked() {
+		out, ok := encoded[c.locale]
+		if !ok {
+			msg := render(c.locale)
+			data, err := json.Marshal(msg.Payload)
+			if err != nil {
+				return
+			}
+			out = rendered{kind: msg.Type, payload: data}
+			encoded[c.locale] = out
 		}
+		r.deliverLocked(c, ServerMessage{Type: out.kind, Payload: out.payload}, seq)
 	}
 }
 
+// broadcastLogLocked 以各收訊者的語系廣播行動紀錄
+func (r *Room) broadcastLogLocked(msg i18n.Message) {
+	r.broadcastLocalizedLocked(func(loc i18n.Locale) ServerMessage {
+		return ServerMessage{Type: "log", Payload: LogPayload{Message: msg.Localize(loc)}}
+	})
+}
+
 func (r *Room) collectClientsLocked() []*Client {
-	clients := make([]*Client, 0, len(r.seats))
+	clients := make([]*Client, 0, len(r.seats)+len(r.spectators))
 	for _, seat := range r.seats {
 		if seat.Client != nil {
 			clients = append(clients, seat.Client)
 		}
 	}
+	for c := range r.spectators {
+		clients = append(clients, c)
+	}
 	return clients
 }
 
+// broadcastPublicStateLocked 依各收訊者的語系廣播公開狀態；支援差異的連線依其確認的版本只收到變動部分
 func (r *Room) broadcastPublicStateLocked() {
+	seq := r.nextSeqLocked()
+	updates := make(map[i18n.Locale]*stateUpdate)
+	for _, c := range r.collectClientsLocked() {
+		update, ok := updates[c.locale]
+		if !ok {
+			var err error
+			if update, err = r.recordPublicStateLocked(c.locale); err != nil {
+				continue
+			}
+			updates[c.locale] = update
+		}
+		if msg, ok := update.messageFor(c, c.publicAcked); ok {
+			r.deliverLocked(c, msg, seq)
+		}
+	}
+}
+
+// recordPublicStateLocked 於該語系的狀態歷史登記目前的公開狀態
+func (r *Room) recordPublicStateLocked(loc i18n.Locale) (*stateUpdate, error) {
+	return r.publicHistoryLocked(loc).record(r.buildPublicStateLocked(loc))
+}
+
+func (r *Room) publicHistoryLocked(loc i18n.Locale) *stateHistory {
+	history, ok := r.publicStates[loc]
+	if !ok {
+		history = newStateHistory("public_state")
+		r.publicStates[loc] = history
+	}
+	return history
+}
+
+func (r *Room) buildPublicStateLocked(loc i18n.Locale) PublicRoomStatePayload {
 	payload := PublicRoomStatePayload{
-		RoomID:   r.id,
-		RoomName: r.name,
-		Status:   r.status,
-		Seats:    r.buildSeatSnapshotsLocked(),
+		RoomID:    r.id,
+		RoomName:  r.name,
+		Status:    r.status,
+		Seats:     r.buildSeatSnapshotsLocked(),
+		MinHumans: r.minHumans,
+		Locked:    r.locked,
+		Access:    r.buildAccessPayloadLocked(),
+		Settings:  r.settings.view(),
 	}
 	if r.game != nil {
+		pauseVotes, _ := r.tallyPauseLocked()
 		payload.PublicGame = &PublicGamePayload{
 			Snapshot:     r.game.BuildPublicSnapshot(),
 			CurrentTurn:  r.currentTurn,
 			CurrentRound: r.currentRound,
+			TurnDeadline: deadlineMillis(r.turnDeadline),
+			Paused:       r.paused,
+			PauseVotes:   pauseVotes,
 		}
 		if r.pendingChallenge != nil {
 			payload.PublicGame.PendingType = "challenge"
+			payload.PublicGame.DefenseDeadline = deadlineMillis(r.pendingChallenge.deadline)
 		}
 	}
 	payload.HostSeat = r.hostSeat
-	r.broadcastLocked(ServerMessage{Type: "public_state", Payload: payload})
+	payload.PostGame = r.buildPostGamePayloadLocked(loc)
+	return payload
 }
 
 func (r *Room) sendPrivateStateLocked(seatIdx int) {
@@ -441,88 +712,85 @@ func (r *Room) sendPrivateStateLocked(seatIdx int) {
 	if seat.Client == nil || r.game == nil {
 		return
 	}
-	snapshot, err := r.game.BuildPrivateSnapshot(seatIdx)
+	snapshot, err := r.game.BuildPrivateSnapshot(seatIdx, seat.Client.locale)
 	if err != nil {
 		r.sendErrorLocked(seat.Client, err)
 		return
 	}
-	payload, err := json.Marshal(ServerMessage{Type: "private_state", Payload: PrivateStatePayload{Snapshot: snapshot}})
-	if err != nil {
-		return
-	}
-	select {
-	case seat.Client.send <- payload:
-	default:
-		go seat.Client.close()
-	}
+	r.sendLocked(seat.Client, ServerMessage{Type: "private_state", Payload: PrivateStatePayload{Snapshot: snapshot}, supersedes: "private_state"})
 }
 
+// sendErrorLocked 以 error 訊息將錯誤送給連線；房間迴圈與計時器的錯誤不屬於任何請求，不計入 nack
 func (r *Room) sendErrorLocked(c *Client, err error) {
-	payload, encodeErr := json.Marshal(ServerMessage{Type: "error", Payload: ErrorPayload{Message: err.Error()}})
-	if encodeErr != nil {
-		return
-	}
-	select {
-	case c.send <- payload:
-	default:
-		go c.close()
-	}
+	r.sendLocked(c, ServerMessage{Type: "error", Payload: NewErrorPayload(err, c.locale)})
 }
 
-func (r *Room) sendPrivateInfoLocked(c *Client, message string) {
-	payload, encodeErr := json.Marshal(ServerMessage{Type: "private_info", Payload: PrivateInfoPayload{Message: message}})
-	if encodeErr != nil {
-		return
-	}
-	select {
-	case c.send <- payload:
-	default:
-		go c.close()
-	}
+func (r *Room) sendPrivateInfoLocked(c *Client, message i18n.Message) {
+	r.sendLocked(c, ServerMessage{Type: "private_info", Payload: PrivateInfoPayload{Message: message.Localize(c.locale)}})
 }
 
-func (r *Room) sendPrivateLogLocked(c *Client, message string) {
-	payload, encodeErr := json.Marshal(ServerMessage{Type: "log", Payload: LogPayload{Message: message}})
-	if encodeErr != nil {
-		return
-	}
-	select {
-	case c.send <- payload:
-	default:
-		go c.close()
-	}
+func (r *Room) sendPrivateLogLocked(c *Client, message i18n.Message) {
+	r.sendLocked(c, ServerMessage{Type: "log", Payload: LogPayload{Message: message.Localize(c.locale)}})
 }
 
-// StartGame 由主持端觸發正式開局
-func (r *Room) StartGame() error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// StartGame 由房主觸發正式開局，force 為真時略過準備檢查
+func (r *Room) StartGame(host *Client, force bool) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.start"); err != nil {
+			return err
+		}
+		if r.status != RoomStatusLobby {
+			return reject("game_already_started")
+		}
+		if err := r.ensureReadyLocked(force); err != nil {
+			return err
+		}
+		return r.startGameLocked()
+	})
+}
 
+func (r *Room) startGameLocked() error {
 	if r.status != RoomStatusLobby {
-		return fmt.Errorf("遊戲已在進行或結束")
+		return reject("game_already_started")
 	}
+	r.clearAwayLocked()
 
+	// 已有的機器人（房主加入的或上一局接手的）沿用原本的名稱與設定，只替空座位補上新的機器人
 	names := make([]string, len(r.seats))
 	for i, seat := range r.seats {
-		if seat.Client != nil {
+		switch {
+		case seat.Client != nil:
 			names[i] = seat.Client.name
-		} else {
-			botName := fmt.Sprintf("機器人%d", i+1)
-			seat.Bot = &BotPlayer{SeatIndex: i, Name: botName, KnownZombies: make(map[int]struct{})}
-			seat.Name = botName
-			names[i] = botName
+		case seat.Bot != nil:
+			names[i] = seat.Bot.Name
+		default:
+			name := botName(i)
+			seat.Bot = &BotPlayer{SeatIndex: i, Name: name, KnownZombies: make(map[int]struct{})}
+			seat.Name = name
+			names[i] = name
 		}
 	}
 
-	newGame, err := game.NewGame(names, r.rng.Int63())
+	rules, err := game.RulesFor(r.settings.Variant)
+	if err != nil {
+		return err
+	}
+	seed := r.settings.Seed
+	if seed == 0 {
+		seed = r.rng.Int63()
+	}
+	newGame, err := game.NewGameWithRules(names, seed, rules)
 	if err != nil {
 		return err
 	}
 	r.game = newGame
+	// 機器人的隨機選擇同樣取自這局的種子，指定種子時整局可重現
+	r.rng = rand.New(rand.NewSource(seed))
 	for i, seat := range r.seats {
 		seat.Player = r.game.Players[i]
 	}
 
+	r.swapRequests = make(map[int]int)
 	r.status = RoomStatusRunning
 	r.currentRound = 1
 	r.game.Round = 1
@@ -552,9 +820,11 @@ func (r *Room) notifyTurnLocked() {
 	if seat.Client != nil {
 		r.sendPrivateStateLocked(seat.Index)
 	}
+	r.armTurnTimerLocked(seat)
+	r.persistLocked()
 
 	if seat.Bot != nil {
-		go r.executeBotTurn(seat.Bot)
+		r.scheduleBotTurnLocked(seat.Bot)
 	}
 }
 
@@ -571,90 +841,80 @@ func (r *Room) findNextAlive(start int) int {
 
 // handleChallenge 由當前玩家提出挑戰
 func (r *Room) handleChallenge(attacker *Client, payload ChallengePayload) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if err := r.ensurePlayerTurnLocked(attacker); err != nil {
-		return err
-	}
-	if r.pendingChallenge != nil {
-		return fmt.Errorf("目前有待處理的挑戰")
-	}
-
-	attackerSeat := r.seats[attacker.seatIndex]
-	if attackerSeat.Player == nil || !attackerSeat.Player.Alive {
-		return fmt.Errorf("攻擊者狀態異常")
-	}
+	return r.attempt(func() error {
+		if err := r.ensurePlayerTurnLocked(attacker); err != nil {
+			return err
+		}
+		if r.pendingChallenge != nil {
+			return reject("challenge_pending")
+		}
 
-	defenderSeat := r.getSeatLocked(payload.TargetID)
-	if defenderSeat == nil || defenderSeat.Player == nil || !defenderSeat.Player.Alive {
-		return fmt.Errorf("目標不可挑戰")
-	}
-	if defenderSeat.Index == attackerSeat.Index {
-		return fmt.Errorf("不可挑戰自己")
-	}
+		attackerSeat := r.seats[attacker.seatIndex]
+		if attackerSeat.Player == nil || !attackerSeat.Player.Alive {
+			return reject("attacker_invalid")
+		}
 
-	if len(payload.Cards) == 0 {
-		return fmt.Errorf("必須選擇至少一張牌")
-	}
+		defenderSeat := r.getSeatLocked(payload.TargetID)
+		if defenderSeat == nil || defenderSeat.Player == nil || !defenderSeat.Player.Alive {
+			return reject("target_invalid")
+		}
+		if defenderSeat.Index == attackerSeat.Index {
+			return game.ErrSelfChallenge
+		}
 
-	attackerCards, err := normalizeCardSelection(payload.Cards, attackerSeat.Player.HandSize())
-	if err != nil {
-		return err
-	}
+		if len(payload.CardIDs) == 0 {
+			return game.ErrNoCardsSelected
+		}
 
-	firstCard := attackerSeat.Player.Hand[attackerCards[0]]
-	switch firstCard.Kind {
-	case game.CardKindNumber:
-		suit, err := ensureNumericSelection(attackerSeat.Player, attackerCards)
+		selected, err := handIndicesForIDs(attackerSeat.Player, payload.CardIDs)
 		if err != nil {
 			return err
 		}
-		if len(attackerCards) > 5 {
-			return fmt.Errorf("一次最多只能出五張數字牌")
+		attackerIndices, err := normalizeCardSelection(selected, attackerSeat.Player.HandSize())
+		if err != nil {
+			return err
 		}
-
-		if defenderSeat.Player.HasSuit(suit) && defenderSeat.Player.Alive {
-			if defenderSeat.Bot != nil {
-				defense := selectBotDefenseIndices(defenderSeat.Player, suit, len(attackerCards))
-				return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, defense)
+		attackerCards := cardIDsAt(attackerSeat.Player, attackerIndices)
+
+		firstCard := attackerSeat.Player.Hand[attackerIndices[0]]
+		switch firstCard.Kind {
+		case game.CardKindNumber:
+			suit, err := ensureNumericSelection(attackerSeat.Player, attackerIndices)
+			if err != nil {
+				return err
 			}
-
-			r.pendingChallenge = &pendingChallenge{
-				AttackerSeat:  attackerSeat.Index,
-				DefenderSeat:  defenderSeat.Index,
-				AttackerCards: attackerCards,
-				AttackKind:    game.CardKindNumber,
-				AttackSuit:    suit,
+			if len(attackerCards) > 5 {
+				return game.ErrTooManyCards
 			}
 
-			r.sendDefensePromptLocked(
-				defenderSeat,
-				collectSuitOptions(defenderSeat.Player, suit),
-				buildCardViewsFromIndices(attackerSeat.Player, attackerCards),
-				&suit,
-			)
-			r.broadcastPublicStateLocked()
-			return nil
-		}
+			if defenderSeat.Player.HasSuit(suit) && defenderSeat.Player.Alive {
+				if defenderSeat.Bot != nil {
+					defense := r.selectBotDefenseLocked(defenderSeat.Player, suit, len(attackerCards))
+					return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, cardIDsAt(defenderSeat.Player, defense))
+				}
+
+				r.beginDefenseLocked(attackerSeat, defenderSeat, attackerCards, suit)
+				return nil
+			}
 
-		return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, nil)
+			return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, nil)
 
-	case game.CardKindZombie:
-		if !attackerSeat.Player.IsZombie() {
-			return fmt.Errorf("僵屍牌僅能由僵屍使用")
-		}
-		return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, nil)
+		case game.CardKindZombie:
+			if !attackerSeat.Player.IsZombie() {
+				return game.ErrZombieCardNotAllowed.WithDetail("error.zombie_card_not_allowed.human")
+			}
+			return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, nil)
 
-	case game.CardKindShotgun:
-		return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, nil)
+		case game.CardKindShotgun:
+			return r.resolveChallengeLocked(attackerSeat.Index, defenderSeat.Index, attackerCards, nil)
 
-	case game.CardKindVaccine:
-		return fmt.Errorf("疫苗僅能在防守時使用")
+		case game.CardKindVaccine:
+			return game.ErrVaccineNotAllowed.WithDetail("error.vaccine_not_allowed.attack")
 
-	default:
-		return fmt.Errorf("未知牌型")
-	}
+		default:
+			return reject("unknown_card_kind")
+		}
+	})
 }
 
 func (r *Room) sendDefensePromptLocked(seat *Seat, options []game.CardView, attackViews []game.CardView, suit *game.Suit) {
@@ -667,22 +927,15 @@ func (r *Room) sendDefensePromptLocked(seat *Seat, options []game.CardView, atta
 		AttackCards:   attackViews,
 		MaxSelectable: 5,
 		Options:       options,
+		Deadline:      deadlineMillis(r.pendingChallenge.deadline),
 	}
 	if suit != nil {
 		payload.Suit = suit
 	}
-	msg := ServerMessage{Type: "defense_prompt", Payload: payload}
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return
-	}
-	select {
-	case seat.Client.send <- data:
-	default:
-		go seat.Client.close()
-	}
+	r.sendLocked(seat.Client, ServerMessage{Type: "defense_prompt", Payload: payload})
 }
 
+// resolveChallengeLocked 以雙方出牌的牌編號結算挑戰
 func (r *Room) resolveChallengeLocked(attackerSeat, defenderSeat int, attackerCards, defenderCards []int) error {
 	outcome, err := r.game.Challenge(game.ChallengeOptions{
 		AttackerID:    attackerSeat,
@@ -694,7 +947,7 @@ func (r *Room) resolveChallengeLocked(attackerSeat, defenderSeat int, attackerCa
 		return err
 	}
 
-	r.pendingChallenge = nil
+	r.clearPendingChallengeLocked()
 
 	r.broadcastPublicStateLocked()
 	r.sendPrivateStateLocked(attackerSeat)
@@ -704,17 +957,17 @@ func (r *Room) resolveChallengeLocked(attackerSeat, defenderSeat int, attackerCa
 		r.registerInfectionKnowledge(attackerSeat, defenderSeat)
 	}
 
-	publicNotes := make([]string, 0, len(outcome.Notes))
-	privateNotes := make([]string, 0)
+	publicNotes := make([]i18n.Message, 0, len(outcome.Notes))
+	privateNotes := make([]i18n.Message, 0)
 	for _, note := range outcome.Notes {
-		if outcome.Infection && (strings.Contains(note, "僵屍牌") || strings.Contains(note, "成為僵屍")) {
+		if outcome.Infection && (note.ID == game.NoteInfection || note.ID == game.NoteZombieGrant) {
 			privateNotes = append(privateNotes, note)
 			continue
 		}
 		publicNotes = append(publicNotes, note)
 	}
 	for _, note := range publicNotes {
-		r.broadcastLocked(ServerMessage{Type: "log", Payload: LogPayload{Message: note}})
+		r.broadcastLogLocked(note)
 	}
 	if len(privateNotes) > 0 {
 		if attackerSeatObj := r.getSeatLocked(attackerSeat); attackerSeatObj != nil && attackerSeatObj.Client != nil {
@@ -729,7 +982,7 @@ func (r *Room) resolveChallengeLocked(attackerSeat, defenderSeat int, attackerCa
 		}
 	}
 	if outcome.StolenCard != nil {
-		r.broadcastLocked(ServerMessage{Type: "log", Payload: LogPayload{Message: fmt.Sprintf("奪得手牌：%s", outcome.StolenCard)}})
+		r.broadcastLogLocked(i18n.M("log.card_stolen", "card", *outcome.StolenCard))
 	}
 	if len(outcome.ConvertedToHuman) > 0 {
 		for _, idx := range outcome.ConvertedToHuman {
@@ -751,29 +1004,36 @@ func (r *Room) resolveChallengeLocked(attackerSeat, defenderSeat int, attackerCa
 }
 
 func (r *Room) handleDefenseResponse(defender *Client, payload DefensePayload) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if r.pendingChallenge == nil {
-		return fmt.Errorf("目前沒有待防禦的挑戰")
-	}
-	if defender.seatIndex != r.pendingChallenge.DefenderSeat {
-		return fmt.Errorf("非指定防守者")
-	}
-
-	defense, err := normalizeDefenseSelection(payload.Cards, r.seats[defender.seatIndex].Player.HandSize())
-	if err != nil {
-		return err
-	}
+	return r.attempt(func() error {
+		if r.pendingChallenge == nil {
+			return reject("no_pending_challenge")
+		}
+		if err := r.ensureNotPausedLocked(); err != nil {
+			return err
+		}
+		if defender.seatIndex != r.pendingChallenge.DefenderSeat {
+			return reject("not_defender")
+		}
 
-	if r.pendingChallenge.AttackKind == game.CardKindNumber && len(defense) > 0 {
-		suit := r.pendingChallenge.AttackSuit
-		if err := ensureDefenseMatchesSuit(r.seats[defender.seatIndex].Player, defense, suit); err != nil {
+		selected, err := handIndicesForIDs(r.seats[defender.seatIndex].Player, payload.CardIDs)
+		if err != nil {
+			return err
+		}
+		defense, err := normalizeDefenseSelection(selected, r.seats[defender.seatIndex].Player.HandSize())
+		if err != nil {
 			return err
 		}
-	}
 
-	return r.resolveChallengeLocked(r.pendingChallenge.AttackerSeat, r.pendingChallenge.DefenderSeat, r.pendingChallenge.AttackerCards, defense)
+		if r.pendingChallenge.AttackKind == game.CardKindNumber && len(defense) > 0 {
+			suit := r.pendingChallenge.AttackSuit
+			if err := ensureDefenseMatchesSuit(r.seats[defender.seatIndex].Player, defense, suit); err != nil {
+				return err
+			}
+		}
+
+		defenderCards := cardIDsAt(r.seats[defender.seatIndex].Player, defense)
+		return r.resolveChallengeLocked(r.pendingChallenge.AttackerSeat, r.pendingChallenge.DefenderSeat, r.pendingChallenge.AttackerCardIDs, defenderCards)
+	})
 }
 
 func normalizeCardSelection(indices []int, handSize int) ([]int, error) {
@@ -789,16 +1049,16 @@ func normalizeDefenseSelection(indices []int, handSize int) ([]int, error) {
 
 func ensureNumericSelection(player *game.Player, indices []int) (game.Suit, error) {
 	if len(indices) == 0 {
-		return "", fmt.Errorf("必須選擇至少一張數字牌")
+		return "", game.ErrNoCardsSelected.WithDetail("error.no_cards_selected.number")
 	}
 	suit := player.Hand[indices[0]].Suit
 	for _, idx := range indices {
 		card := player.Hand[idx]
 		if card.Kind != game.CardKindNumber {
-			return "", fmt.Errorf("數字牌不能與特殊牌混出")
+			return "", game.ErrMixedCards
 		}
 		if card.Suit != suit {
-			return "", fmt.Errorf("多張數字牌必須為相同花色")
+			return "", game.ErrInvalidSuit
 		}
 	}
 	return suit, nil
@@ -806,12 +1066,12 @@ func ensureNumericSelection(player *game.Player, indices []int) (game.Suit, erro
 
 func ensureDefenseMatchesSuit(player *game.Player, indices []int, suit game.Suit) error {
 	if len(indices) > 5 {
-		return fmt.Errorf("一次最多只能防禦五張牌")
+		return game.ErrTooManyCards.WithDetail("error.too_many_cards.defense")
 	}
 	for _, idx := range indices {
 		card := player.Hand[idx]
 		if card.Kind != game.CardKindNumber || card.Suit != suit {
-			return fmt.Errorf("防守牌需同花色 %s", suit)
+			return game.ErrInvalidSuit.WithDetail("error.invalid_suit.named", "suit", suit)
 		}
 	}
 	return nil
@@ -822,29 +1082,58 @@ func uniqueSortedIndices(indices []int, handSize int) ([]int, error) {
 	sort.Ints(sorted)
 	for i, idx := range sorted {
 		if idx < 0 || idx >= handSize {
-			return nil, fmt.Errorf("手牌索引 %d 無效", idx)
+			return nil, game.ErrCardNotInHand.WithDetail("error.card_not_in_hand.bad_index", "index", idx)
 		}
 		if i > 0 && sorted[i-1] == idx {
-			return nil, fmt.Errorf("手牌索引 %d 重複", idx)
+			return nil, game.ErrDuplicateCard.WithDetail("error.duplicate_card.index", "index", idx)
 		}
 	}
 	return sorted, nil
 }
 
-func buildCardViewsFromIndices(player *game.Player, indices []int) []game.CardView {
-	views := make([]game.CardView, 0, len(indices))
+// handIndicesForIDs 將玩家送出的牌編號轉為目前的手牌索引；依過期畫面送出、已不在手中的牌會被拒絕
+func handIndicesForIDs(player *game.Player, ids []int) ([]int, error) {
+	indices := make([]int, 0, len(ids))
+	for _, id := range ids {
+		idx := player.CardIndex(id)
+		if idx < 0 {
+			return nil, game.ErrCardNotInHand.WithDetail("error.card_not_in_hand.stale", "card", id)
+		}
+		indices = append(indices, idx)
+	}
+	return indices, nil
+}
+
+func cardIDsAt(player *game.Player, indices []int) []int {
+	if len(indices) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(indices))
 	for _, idx := range indices {
+		ids = append(ids, player.Hand[idx].ID)
+	}
+	return ids
+}
+
+// cardViewsByID 依牌編號呈現仍在手中的牌，已不在手中的編號略過
+func cardViewsByID(player *game.Player, ids []int, loc i18n.Locale) []game.CardView {
+	views := make([]game.CardView, 0, len(ids))
+	for _, id := range ids {
+		idx := player.CardIndex(id)
+		if idx < 0 {
+			continue
+		}
 		card := player.Hand[idx]
-		views = append(views, game.CardView{Index: idx, Kind: card.Kind, Suit: card.Suit, Value: card.Value, Label: card.String()})
+		views = append(views, game.CardView{ID: card.ID, Index: idx, Kind: card.Kind, Suit: card.Suit, Value: card.Value, Label: card.Localize(loc)})
 	}
 	return views
 }
 
-func collectSuitOptions(player *game.Player, suit game.Suit) []game.CardView {
+func collectSuitOptions(player *game.Player, suit game.Suit, loc i18n.Locale) []game.CardView {
 	options := make([]game.CardView, 0)
 	for idx, card := range player.Hand {
 		if card.Kind == game.CardKindNumber && card.Suit == suit {
-			options = append(options, game.CardView{Index: idx, Kind: card.Kind, Suit: card.Suit, Value: card.Value, Label: card.String()})
+			options = append(options, game.CardView{ID: card.ID, Index: idx, Kind: card.Kind, Suit: card.Suit, Value: card.Value, Label: card.Localize(loc)})
 		}
 	}
 	return options
@@ -922,20 +1211,22 @@ func (r *Room) handleEliminationLocked(ids []int) {
 	for _, id := range ids {
 		seat := r.getSeatLocked(id)
 		if seat != nil {
-			note := fmt.Sprintf("玩家 %s 被淘汰", seat.displayName())
-			r.broadcastLocked(ServerMessage{Type: "log", Payload: LogPayload{Message: note}})
+			r.broadcastLogLocked(i18n.M("log.player_eliminated", "player", seat.displayName()))
 		}
 	}
 }
 
 func (r *Room) ensurePlayerTurnLocked(c *Client) error {
 	if r.status != RoomStatusRunning {
-		return fmt.Errorf("遊戲尚未開始")
+		return errGameNotRunning
+	}
+	if c.seatIndex < 0 {
+		return errSpectatorCannotAct
 	}
 	if c.seatIndex != r.currentTurn {
-		return fmt.Errorf("尚未輪到你行動")
+		return game.ErrNotYourTurn
 	}
-	return nil
+	return r.ensureNotPausedLocked()
 }
 
 func (r *Room) getSeatLocked(id int) *Seat {
@@ -989,49 +1280,46 @@ func (r *Room) finishGameLocked() {
 	}
 
 	r.status = RoomStatusFinished
+	r.stopTimersLocked()
+	r.clearPauseLocked()
+	r.claimRequests = make(map[int]*Client)
+	if r.restored || r.settings.Correspondence {
+		r.restored = false
+		r.hub.background(func() { r.hub.discardSavedGame(r.id) })
+	}
 	humanWins, _, _ := r.game.DetermineWinner()
-	winner := "僵屍陣營"
+	winner := i18n.M("team.zombies")
 	if humanWins {
-		winner = "人類陣營"
+		winner = i18n.M("team.humans")
 	}
-	r.broadcastLocked(ServerMessage{Type: "log", Payload: LogPayload{Message: fmt.Sprintf("對局結束，%s取得勝利", winner)}})
+	r.broadcastLogLocked(i18n.M("log.game_over", "winner", winner))
+	r.beginPostGameLocked(winner)
 	r.broadcastPublicStateLocked()
-
-	go func() {
-		time.Sleep(5 * time.Second)
-		r.mu.Lock()
-		defer r.mu.Unlock()
-		if r.status != RoomStatusFinished {
-			return
-		}
-		r.resetToLobbyLocked()
-	}()
 }
 
 func (r *Room) resetToLobbyLocked() {
-	emptyPayload, err := json.Marshal(ServerMessage{Type: "private_state", Payload: PrivateStatePayload{}})
-	if err != nil {
-		emptyPayload = nil
-	}
-
 	for _, seat := range r.seats {
 		seat.Player = nil
-		if emptyPayload != nil && seat.Client != nil {
-			select {
-			case seat.Client.send <- emptyPayload:
-			default:
-				go seat.Client.close()
-			}
+		if seat.Client != nil {
+			r.sendLocked(seat.Client, ServerMessage{Type: "private_state", Payload: PrivateStatePayload{}, supersedes: "private_state"})
 		}
 		if seat.Bot != nil {
 			seat.Bot.KnownZombies = make(map[int]struct{})
 		}
+		if seat.isOffline() {
+			seat.Name = ""
+			seat.Token = ""
+			seat.UserID = 0
+		}
 	}
 
 	r.game = nil
 	r.currentRound = 0
 	r.currentTurn = -1
-	r.pendingChallenge = nil
+	r.stopTimersLocked()
+	r.endPostGameLocked()
+	r.clearReadyLocked()
+	r.clearAwayLocked()
 	r.status = RoomStatusLobby
 
 	r.broadcastPublicStateLocked()
@@ -0,0 +1,163 @@
+package server
+
+import "zombierush/internal/i18n"
+
+// takeSeatLocked 為新玩家挑選座位；preferred 為 nil 時取第一個空位
+func (r *Room) takeSeatLocked(preferred *int) (*Seat, error) {
+	if preferred == nil {
+		if seat := r.firstEmptySeatLocked(); seat != nil {
+			return seat, nil
+		}
+		return nil, errRoomFull
+	}
+	seat := r.getSeatLocked(*preferred)
+	if seat == nil {
+		return nil, errInvalidSeat
+	}
+	if seat.isFilled() {
+		return nil, reject("seat_taken", "seat", seat.Index)
+	}
+	return seat, nil
+}
+
+// chooseSeat 讓已入座的玩家移到指定空位
+func (r *Room) chooseSeat(c *Client, target int) error {
+	return r.attempt(func() error {
+		seat, err := r.ownSeatLocked(c)
+		if err != nil {
+			return err
+		}
+		dest, err := r.takeSeatLocked(&target)
+		if err != nil {
+			return err
+		}
+		r.swapSeatsLocked(seat, dest)
+		r.broadcastSeatingLocked()
+		return nil
+	})
+}
+
+// requestSwap 向另一座位提出換位請求；對象為機器人時直接交換
+func (r *Room) requestSwap(c *Client, target int) error {
+	return r.attempt(func() error {
+		seat, err := r.ownSeatLocked(c)
+		if err != nil {
+			return err
+		}
+		dest := r.getSeatLocked(target)
+		if dest == nil || dest == seat {
+			return errInvalidSeat
+		}
+		if dest.Client == nil {
+			r.swapSeatsLocked(seat, dest)
+			r.broadcastSeatingLocked()
+			return nil
+		}
+		r.swapRequests[seat.Index] = dest.Index
+		r.sendLocked(dest.Client, ServerMessage{Type: "seat_swap_request", Payload: SeatSwapRequestPayload{FromSeat: seat.Index, Name: seat.displayName()}})
+		r.sendPrivateInfoLocked(c, i18n.M("info.swap_requested", "player", dest.displayName()))
+		return nil
+	})
+}
+
+// respondSwap 由被請求者接受或拒絕 fromSeat 提出的換位
+func (r *Room) respondSwap(c *Client, fromSeat int, accept bool) error {
+	return r.attempt(func() error {
+		seat, err := r.ownSeatLocked(c)
+		if err != nil {
+			return err
+		}
+		if target, ok := r.swapRequests[fromSeat]; !ok || target != seat.Index {
+			return reject("swap_expired")
+		}
+		delete(r.swapRequests, fromSeat)
+		requester := r.getSeatLocked(fromSeat)
+		if requester == nil || requester.Client == nil {
+			return reject("swap_expired")
+		}
+		if !accept {
+			r.sendPrivateInfoLocked(requester.Client, i18n.M("info.swap_declined", "player", seat.displayName()))
+			return nil
+		}
+		r.swapSeatsLocked(requester, seat)
+		r.broadcastSeatingLocked()
+		return nil
+	})
+}
+
+// shuffleSeats 由房主在開局前隨機打亂座位（即出牌順序）
+func (r *Room) shuffleSeats(host *Client) error {
+	return r.attempt(func() error {
+		if err := r.ensureHostLocked(host, "error.host_only.shuffle"); err != nil {
+			return err
+		}
+		if r.status != RoomStatusLobby {
+			return reject("seating_lobby_only")
+		}
+		r.rng.Shuffle(len(r.seats), func(i, j int) {
+			r.swapSeatsLocked(r.seats[i], r.seats[j])
+		})
+		r.broadcastLogLocked(i18n.M("log.seats_shuffled"))
+		r.broadcastSeatingLocked()
+		return nil
+	})
+}
+
+func (r *Room) ownSeatLocked(c *Client) (*Seat, error) {
+	if r.status != RoomStatusLobby {
+		return nil, reject("seating_lobby_only")
+	}
+	seat := r.getSeatLocked(c.seatIndex)
+	if seat == nil || seat.Client != c {
+		return nil, errNotSeated
+	}
+	return seat, nil
+}
+
+// swapSeatsLocked 交換兩個座位上的玩家，房主身分隨人移動
+func (r *Room) swapSeatsLocked(a, b *Seat) {
+	if a == b {
+		return
+	}
+	switch r.hostSeat {
+	case a.Index:
+		r.hostSeat = b.Index
+	case b.Index:
+		r.hostSeat = a.Index
+	}
+	a.Name, b.Name = b.Name, a.Name
+	a.Token, b.Token = b.Token, a.Token
+	a.UserID, b.UserID = b.UserID, a.UserID
+	a.Ready, b.Ready = b.Ready, a.Ready
+	a.Client, b.Client = b.Client, a.Client
+	a.Bot, b.Bot = b.Bot, a.Bot
+	for _, seat := range []*Seat{a, b} {
+		if seat.Client != nil {
+			seat.Client.seatIndex = seat.Index
+		}
+		if seat.Bot != nil {
+			seat.Bot.SeatIndex = seat.Index
+		}
+	}
+	r.dropSwapRequestsLocked(a.Index)
+	r.dropSwapRequestsLocked(b.Index)
+}
+
+func (r *Room) dropSwapRequestsLocked(seatIdx int) {
+	for from, to := range r.swapRequests {
+		if from == seatIdx || to == seatIdx {
+			delete(r.swapRequests, from)
+		}
+	}
+}
+
+// broadcastSeatingLocked 座位變動後重送 welcome 讓客戶端更新自己的座位
+func (r *Room) broadcastSeatingLocked() {
+	for _, seat := range r.seats {
+		if seat.Client != nil {
+			r.sendWelcomeLocked(seat.Client)
+		}
+	}
+	r.broadcastLobbyLocked()
+	r.broadcastPublicStateLocked()
+}
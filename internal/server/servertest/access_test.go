@@ -0,0 +1,179 @@
+package servertest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"zombierush/internal/server"
+)
+
+// listedRooms 回傳最近一則大廳列表中的房間 ID
+func listedRooms(t *testing.T, c *Client) []string {
+	t.Helper()
+	msg, ok := c.Last("lobby_rooms")
+	if !ok {
+		t.Fatalf("%s 應收到大廳列表", c.Name)
+	}
+	ids := make([]string, 0)
+	for _, room := range Payload[server.LobbyRoomsPayload](t, msg).Rooms {
+		ids = append(ids, room.RoomID)
+	}
+	return ids
+}
+
+// welcomeInvite 回傳連線最近一則 welcome 附帶的邀請碼，未入座者為空字串
+func welcomeInvite(t *testing.T, c *Client) string {
+	t.Helper()
+	msg, ok := c.Last("welcome")
+	if !ok {
+		t.Fatalf("%s 應收到 welcome", c.Name)
+	}
+	return Payload[struct {
+		InviteCode string `json:"inviteCode"`
+	}](t, msg).InviteCode
+}
+
+// 私人房間不列入大廳，只能憑邀請碼加入；邀請碼不分大小寫
+func TestPrivateRoomNeedsInvite(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "私人房", Private: true})
+	state, _ := host.RoomState()
+	code := welcomeInvite(t, host)
+	if state.Access.Visibility != server.RoomVisibilityPrivate || code == "" {
+		t.Fatalf("私人房間應附邀請碼，存取設定為 %+v", state.Access)
+	}
+
+	bob := s.Connect("bob")
+	if slices.Contains(listedRooms(t, bob), state.RoomID) {
+		t.Fatal("私人房間不應出現在大廳列表")
+	}
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if errs := bob.Errors(); !slices.Contains(errs, "private_room") {
+		t.Fatalf("沒有邀請碼不能加入私人房間，錯誤為 %v", errs)
+	}
+	bob.Send("room_join", server.JoinRoomPayload{InviteCode: "ZZZZZZ"})
+	if errs := bob.Errors(); !slices.Contains(errs, "room_not_found") {
+		t.Fatalf("錯誤的邀請碼應找不到房間，錯誤為 %v", errs)
+	}
+	bob.Send("room_join", server.JoinRoomPayload{InviteCode: strings.ToLower(code)})
+	if joined, ok := bob.RoomState(); !ok || joined.RoomID != state.RoomID {
+		t.Fatalf("憑邀請碼應可加入，錯誤為 %v", bob.Errors())
+	}
+}
+
+// 有密碼的公開房間需輸入正確密碼，持有邀請碼者可略過密碼
+func TestRoomPassword(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "密碼房", Password: "secret"})
+	state, _ := host.RoomState()
+	if !state.Access.HasPassword {
+		t.Fatal("房間應標示需要密碼")
+	}
+
+	bob := s.Connect("bob")
+	if !slices.Contains(listedRooms(t, bob), state.RoomID) {
+		t.Fatal("有密碼的公開房間仍應列在大廳")
+	}
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID, Password: "wrong"})
+	if errs := bob.Errors(); !slices.Contains(errs, "wrong_password") {
+		t.Fatalf("密碼錯誤應被拒，錯誤為 %v", errs)
+	}
+	if _, ok := bob.Last("welcome"); ok {
+		t.Fatal("密碼錯誤時不應入座")
+	}
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID, Password: "secret"})
+	if _, ok := bob.Last("welcome"); !ok {
+		t.Fatalf("密碼正確應可加入，錯誤為 %v", bob.Errors())
+	}
+
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{InviteCode: welcomeInvite(t, host)})
+	if _, ok := carol.Last("welcome"); !ok {
+		t.Fatalf("持有邀請碼者應可略過密碼，錯誤為 %v", carol.Errors())
+	}
+}
+
+// 邀請碼可略過密碼，只交給入座者：觀戰者的 welcome 與房間狀態都看不到
+func TestSpectatorCannotSeeInviteCode(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "密碼房", Password: "secret"})
+	state, _ := host.RoomState()
+	code := welcomeInvite(t, host)
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	watcher := s.Connect("carol")
+	watcher.Send("room_spectate", server.SpectatePayload{RoomID: state.RoomID, Password: "secret"})
+	if _, ok := watcher.Last("welcome"); !ok {
+		t.Fatalf("觀戰者應能進入房間，錯誤：%v", watcher.Errors())
+	}
+	if got := welcomeInvite(t, watcher); got != "" {
+		t.Fatalf("觀戰者不應取得邀請碼，卻收到 %q", got)
+	}
+	for _, c := range []*Client{host, watcher} {
+		for _, msg := range c.Messages() {
+			if strings.Contains(string(msg.Payload), code) && msg.Type != "welcome" {
+				t.Fatalf("%s 的 %s 不應帶有邀請碼", c.Name, msg.Type)
+			}
+		}
+	}
+}
+
+// 加入與入座失敗各有專屬代碼，用戶端不必比對訊息文字即可分辨原因
+func TestJoinAndSeatFailureCodes(t *testing.T) {
+	s := New(t)
+	expect := func(c *Client, want string) {
+		t.Helper()
+		errs := c.Errors()
+		if len(errs) == 0 || errs[len(errs)-1] != want {
+			t.Fatalf("%s 的錯誤代碼應為 %s，實際為 %v", c.Name, want, errs)
+		}
+		c.Clear()
+	}
+
+	alice := s.Connect("alice")
+	alice.Send("room_create", server.CreateRoomPayload{Name: "私人房", Private: true})
+	private, _ := alice.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_create", server.CreateRoomPayload{Name: "密碼房", Password: "secret"})
+	guarded, _ := bob.RoomState()
+
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: private.RoomID})
+	expect(carol, "private_room")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "wrong"})
+	expect(carol, "wrong_password")
+	taken := welcomeSeat(t, bob)
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "secret", Seat: &taken})
+	expect(carol, "seat_taken")
+
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "secret"})
+	token := welcomeToken(t, carol)
+	carol.Send("seat_choose", server.SeatTargetPayload{Seat: &taken})
+	expect(carol, "seat_taken")
+
+	erin := s.Connect("erin")
+	erin.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "secret"})
+	state, _ := bob.RoomState()
+	erinSeat := seatNamed(t, state, "erin").Index
+	bob.Send("room_ban", server.SeatTargetPayload{Seat: &erinSeat})
+	erin.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "secret"})
+	expect(erin, "banned")
+
+	bob.Send("start_game", server.StartGamePayload{Force: true})
+	carol.Disconnect()
+	s.Settle()
+	dave := s.ConnectWithOptions("dave", Options{SeatToken: token})
+	dave.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "secret"})
+	expect(dave, "seat_token_mismatch")
+	frank := s.Connect("frank")
+	frank.Send("room_join", server.JoinRoomPayload{RoomID: guarded.RoomID, Password: "secret"})
+	expect(frank, "game_in_progress")
+
+	alice.Send("room_lock", server.LockRoomPayload{Locked: true})
+	frank.Send("room_join", server.JoinRoomPayload{InviteCode: welcomeInvite(t, alice)})
+	expect(frank, "room_locked")
+}
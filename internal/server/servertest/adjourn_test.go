@@ -0,0 +1,158 @@
+package servertest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// 重新開啟封存對局後無人回座：逾時後再次封存並關閉房間，之後仍可再重新開啟
+func TestReopenedGameReadjournsWhenNobodyReturns(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "封存房"})
+	seed := int64(7)
+	host.Send("room_settings", server.RoomSettingsPayload{Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, ok := host.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("開局後房間應為進行中，錯誤：%v", host.Errors())
+	}
+	host.Send("game_adjourn", nil)
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("封存後房間應移除，仍有 %d 間", rooms)
+	}
+	adjourned, err := s.Store.LoadGame(state.RoomID)
+	if err != nil {
+		t.Fatalf("封存後應有保存紀錄：%v", err)
+	}
+
+	s.Advance(time.Hour)
+	host.Send("saved_game_resume", server.SavedGameTargetPayload{RoomID: state.RoomID})
+	if reopened, ok := host.RoomState(); !ok || reopened.RoomID != state.RoomID {
+		t.Fatalf("應回到重新開啟的房間，錯誤：%v", host.Errors())
+	}
+	host.Disconnect()
+	s.Settle()
+	if rooms := s.Hub.Stats().Rooms; rooms != 1 {
+		t.Fatalf("閒置時限前房間應保留，目前有 %d 間", rooms)
+	}
+
+	s.Advance(5 * time.Minute)
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("無人回座逾時後房間應關閉，仍有 %d 間", rooms)
+	}
+	readjourned, err := s.Store.LoadGame(state.RoomID)
+	if err != nil {
+		t.Fatalf("再次封存後應保留保存紀錄：%v", err)
+	}
+	if !readjourned.SavedAt.After(adjourned.SavedAt) {
+		t.Fatalf("保存紀錄應於逾時時更新，原為 %v，現為 %v", adjourned.SavedAt, readjourned.SavedAt)
+	}
+
+	back := s.Reconnect(host)
+	back.Send("saved_game_resume", server.SavedGameTargetPayload{RoomID: state.RoomID})
+	if reopened, ok := back.RoomState(); !ok || reopened.Status != server.RoomStatusRunning {
+		t.Fatalf("再次封存的對局應可重新開啟，錯誤：%v", back.Errors())
+	}
+}
+
+// 保存資料中的待防守挑戰毀損時重新開啟失敗，不留下房間；修復資料後仍可正常重新開啟
+func TestCorruptPendingChallengeRefusesResume(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "毀損房"})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, ok := host.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("開局後房間應為進行中，錯誤：%v", host.Errors())
+	}
+	host.Send("game_adjourn", nil)
+	record, err := s.Store.LoadGame(state.RoomID)
+	if err != nil {
+		t.Fatalf("封存後應有保存紀錄：%v", err)
+	}
+
+	var snapshot map[string]any
+	if err := json.Unmarshal(record.State, &snapshot); err != nil {
+		t.Fatalf("解析保存資料失敗：%v", err)
+	}
+	snapshot["pending"] = map[string]any{"attackerSeat": 0, "defenderSeat": 9, "attackerCardIds": []int{}}
+	corrupt := *record
+	if corrupt.State, err = json.Marshal(snapshot); err != nil {
+		t.Fatalf("編碼保存資料失敗：%v", err)
+	}
+	if err := s.Store.SaveGame(corrupt); err != nil {
+		t.Fatalf("寫入毀損資料失敗：%v", err)
+	}
+
+	host.Clear()
+	host.Send("saved_game_resume", server.SavedGameTargetPayload{RoomID: state.RoomID})
+	if errs := host.Errors(); len(errs) == 0 || errs[len(errs)-1] != "saved_game_corrupt" {
+		t.Fatalf("毀損的保存資料應拒絕重新開啟，錯誤：%v", errs)
+	}
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("重新開啟失敗後不應留下房間，目前有 %d 間", rooms)
+	}
+
+	if err := s.Store.SaveGame(*record); err != nil {
+		t.Fatalf("寫回保存資料失敗：%v", err)
+	}
+	host.Send("saved_game_resume", server.SavedGameTargetPayload{RoomID: state.RoomID})
+	if reopened, ok := host.RoomState(); !ok || reopened.RoomID != state.RoomID {
+		t.Fatalf("修復後應可重新開啟，錯誤：%v", host.Errors())
+	}
+}
+
+// 重新開啟後停在待防守的挑戰時無人回座：再次封存連同挑戰一併保存，重新開啟後仍停在同一個挑戰
+func TestReadjournKeepsPendingChallenge(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "挑戰房"})
+	turn, defense, seed := 10, 30, int64(5)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, _ := host.RoomState()
+	host.Send("game_adjourn", nil)
+	host.Send("saved_game_resume", server.SavedGameTargetPayload{RoomID: state.RoomID})
+	host.Send("game_resume", nil)
+
+	pending := s.RunUntil(func() bool {
+		state, ok := host.RoomState()
+		return ok && state.PublicGame != nil && state.PublicGame.PendingType == "challenge"
+	}, time.Hour)
+	if !pending {
+		t.Fatal("對局應在時限內出現待防守的挑戰")
+	}
+	host.Send("game_pause", nil)
+	before, _ := host.RoomState()
+	host.Disconnect()
+	s.Advance(5 * time.Minute)
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("無人回座逾時後房間應關閉，仍有 %d 間", rooms)
+	}
+
+	record, err := s.Store.LoadGame(state.RoomID)
+	if err != nil {
+		t.Fatalf("應保留保存紀錄：%v", err)
+	}
+	var snapshot struct {
+		Pending *json.RawMessage `json:"pending"`
+	}
+	if err := json.Unmarshal(record.State, &snapshot); err != nil || snapshot.Pending == nil {
+		t.Fatalf("再次封存應保存待防守的挑戰，錯誤：%v", err)
+	}
+
+	back := s.Reconnect(host)
+	back.Send("saved_game_resume", server.SavedGameTargetPayload{RoomID: state.RoomID})
+	reopened, ok := back.RoomState()
+	if !ok || reopened.PublicGame == nil || reopened.PublicGame.PendingType != "challenge" {
+		t.Fatalf("重新開啟後應回到待防守的挑戰，錯誤：%v", back.Errors())
+	}
+	if reopened.PublicGame.CurrentRound != before.PublicGame.CurrentRound || reopened.PublicGame.CurrentTurn != before.PublicGame.CurrentTurn {
+		t.Fatalf("重新開啟後應保留封存前的進度：第 %d 輪座位 %d，實際為第 %d 輪座位 %d",
+			before.PublicGame.CurrentRound, before.PublicGame.CurrentTurn, reopened.PublicGame.CurrentRound, reopened.PublicGame.CurrentTurn)
+	}
+}
@@ -0,0 +1,181 @@
+package servertest
+
+import (
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// 網頁前端會自動回送 state_ack；這類訊息不應算作玩家操作，否則在線但未操作的玩家永遠不會轉為代打
+func TestAutoAckDoesNotPreventAFK(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense, seed := 10, 5, int64(7)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+
+	guest := s.ConnectWithOptions("bob", Options{Protocol: 3, AutoAck: true})
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	away := func() bool {
+		state, ok := guest.RoomState()
+		if !ok {
+			return false
+		}
+		for _, seat := range state.Seats {
+			if seat.Name == "bob" {
+				return seat.Away
+			}
+		}
+		return false
+	}
+	if !s.RunUntil(away, time.Hour) {
+		t.Fatal("只回送 state_ack 的玩家在連續逾時後應轉為代打")
+	}
+	if len(guest.OfType("state_patch")) == 0 {
+		t.Fatal("protocol 3 的連線應收到 state_patch")
+	}
+	if n := guest.PatchFailures(); n > 0 {
+		t.Fatalf("有 %d 則 state_patch 無法套用", n)
+	}
+}
+
+// 連續逾時達上限後改由 AI 代打、輪到時不必等回合時限；玩家親自操作即取回座位。
+// 機器人的選擇帶有隨機性，alice 可能在再次輪到前就被淘汰，此時換一桌重來
+func TestAFKHandsOverToBotAndBack(t *testing.T) {
+	for attempt := 0; attempt < 5; attempt++ {
+		if afkRoundTrip(t) {
+			return
+		}
+	}
+	t.Fatal("連續五桌都在 alice 再次輪到前結束")
+}
+
+// afkRoundTrip 執行一次代打與取回的流程；alice 在檢查完成前被淘汰或對局結束時回傳 false
+func afkRoundTrip(t *testing.T) bool {
+	t.Helper()
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense := 10, 5
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, _ := host.RoomState()
+	seat := seatNamed(t, state, "alice").Index
+
+	out := func() bool {
+		state, ok := host.RoomState()
+		return !ok || state.Status != server.RoomStatusRunning || !state.PublicGame.Snapshot.Players[seat].Alive
+	}
+	away := func() bool {
+		state, ok := host.RoomState()
+		return ok && seatNamed(t, state, "alice").Away
+	}
+	aliceTurn := func() bool {
+		state, ok := host.RoomState()
+		return ok && state.Status == server.RoomStatusRunning && state.PublicGame.CurrentTurn == seat && state.PublicGame.PendingType == ""
+	}
+
+	if !s.RunUntil(func() bool { return away() || out() }, time.Hour) {
+		t.Fatal("對局應在時限內結束或讓 alice 轉為代打")
+	}
+	if out() {
+		return false
+	}
+	state, _ = host.RoomState()
+	if !seatNamed(t, state, "alice").IsBot {
+		t.Fatal("轉為代打後座位應由 AI 接手")
+	}
+	if !s.RunUntil(func() bool { return aliceTurn() || out() }, time.Hour) {
+		t.Fatal("代打期間對局應持續推進")
+	}
+	if out() {
+		return false
+	}
+	if !s.RunUntil(func() bool { return !aliceTurn() }, time.Duration(turn)*time.Second-time.Millisecond) {
+		t.Fatalf("代打的 AI 應直接出牌，不必等 %d 秒的回合時限", turn)
+	}
+	if out() {
+		return false
+	}
+
+	// 房主暫停立即生效，同時算作親自操作
+	host.Send("game_pause", nil)
+	host.Send("game_resume", nil)
+	state, _ = host.RoomState()
+	if back := seatNamed(t, state, "alice"); back.Away || back.IsBot {
+		t.Fatalf("親自操作後應取回座位，座位為 %+v", back)
+	}
+	return true
+}
+
+// 自動送出的確認與查詢不算操作；其餘任何指令即使被拒絕，也會讓代打中的玩家取回座位
+func TestAFKActivityMessages(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense, seed := 10, 5, int64(7)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	guest := s.ConnectWithOptions("bob", Options{Protocol: 3})
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	away := func() bool {
+		state, ok := host.RoomState()
+		return ok && seatNamed(t, state, "bob").Away
+	}
+	if !s.RunUntil(away, time.Hour) {
+		t.Fatal("連續逾時後應轉為代打")
+	}
+
+	last, _ := guest.Last("public_state")
+	guest.Send("state_ack", server.StateAckPayload{Target: "public_state", Version: last.Version})
+	guest.Send("resync", nil)
+	guest.Send("lobby_list", nil)
+	guest.Send("inbox_list", nil)
+	guest.Send("saved_games_list", nil)
+	if !away() {
+		t.Fatal("自動確認與查詢不應讓玩家取回座位")
+	}
+
+	guest.Send("room_ready", server.ReadyPayload{Ready: true})
+	if away() {
+		t.Fatal("送出任何指令都應取回座位，即使指令本身被拒絕")
+	}
+	state, _ = host.RoomState()
+	if seat := seatNamed(t, state, "bob"); seat.IsBot {
+		t.Fatalf("取回後座位應由真人操作，實為 %+v", seat)
+	}
+}
+
+// 代打中的玩家在自己的回合內取回座位時，沿用本回合原本的期限，不重新計時
+func TestAFKReturnKeepsTurnDeadline(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense, seed := 10, 5, int64(7)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	guest := s.Connect("bob")
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	away := func() bool {
+		state, ok := host.RoomState()
+		return ok && seatNamed(t, state, "bob").Away
+	}
+	if !s.RunUntil(away, time.Hour) {
+		t.Fatal("連續逾時後應轉為代打")
+	}
+	due := awaitTurnOf(t, s, host, "bob", time.Duration(turn)*time.Second)
+	s.Advance(time.Second)
+	guest.Send("room_ready", server.ReadyPayload{Ready: true})
+	if away() {
+		t.Fatal("回合中送出指令應取回座位")
+	}
+	assertDeadlineHolds(t, s, host, "bob", due)
+}
@@ -0,0 +1,172 @@
+package servertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"zombierush/internal/server"
+)
+
+// Message 為收到的伺服器訊息，Payload 保留原始 JSON，可用 Payload[T] 或 Decode 轉為對應型別
+type Message struct {
+	Type    string          `json:"type"`
+	Seq     int64           `json:"seq,omitempty"`
+	Prev    int64           `json:"prev,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode 將訊息內容解碼至 v
+func (m Message) Decode(v any) error {
+	return json.Unmarshal(m.Payload, v)
+}
+
+// Client 為不經網路的假連線，記錄收到的所有訊息
+type Client struct {
+	Conn   *server.Client
+	UserID int64
+	Name   string
+
+	server *Server
+	opts   Options
+
+	mu       sync.Mutex
+	messages []Message
+	closed   bool
+	// stalled 表示暫停讀取，訊息留在伺服器端的送出佇列
+	stalled bool
+	// state 由收到的房間訊息重建公開狀態
+	state stateTracker
+}
+
+// Send 送出一則指令並等待處理完畢；payload 為 nil 時送出空物件
+func (c *Client) Send(msgType string, payload any) {
+	c.server.t.Helper()
+	c.SendRequest(msgType, payload, "")
+}
+
+// SendRequest 同 Send，另帶請求 ID，伺服器會以 ack 或 nack 回覆
+func (c *Client) SendRequest(msgType string, payload any, requestID string) {
+	c.server.t.Helper()
+	if payload == nil {
+		payload = struct{}{}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		c.server.t.Fatalf("編碼 %s 失敗: %v", msgType, err)
+	}
+	c.Conn.Receive(server.ClientMessage{Type: msgType, Payload: data, RequestID: requestID})
+	c.server.Settle()
+}
+
+// Disconnect 中斷連線
+func (c *Client) Disconnect() {
+	c.Conn.Disconnect()
+	c.server.Settle()
+}
+
+// Closed 回傳伺服器是否已關閉此連線
+func (c *Client) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Stall 模擬讀取停滯的連線：之後 Settle 不再取出送出佇列，訊息在伺服器端累積
+func (c *Client) Stall() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stalled = true
+}
+
+// Resume 恢復讀取並立即收下累積的訊息
+func (c *Client) Resume() {
+	c.mu.Lock()
+	c.stalled = false
+	c.mu.Unlock()
+	c.server.Settle()
+}
+
+// Messages 回傳至今收到的所有訊息
+func (c *Client) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.messages...)
+}
+
+// OfType 回傳指定類型的訊息，依收到順序排列
+func (c *Client) OfType(msgType string) []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matched := make([]Message, 0)
+	for _, msg := range c.messages {
+		if msg.Type == msgType {
+			matched = append(matched, msg)
+		}
+	}
+	return matched
+}
+
+// Last 回傳最近一則指定類型的訊息
+func (c *Client) Last(msgType string) (Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		if c.messages[i].Type == msgType {
+			return c.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Clear 清除已收到的訊息紀錄
+func (c *Client) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = nil
+}
+
+// Errors 回傳收到的錯誤代碼，包含 nack
+func (c *Client) Errors() []string {
+	codes := make([]string, 0)
+	for _, msg := range c.Messages() {
+		if msg.Type != "error" && msg.Type != "nack" {
+			continue
+		}
+		var payload struct {
+			Code string `json:"code"`
+		}
+		if err := msg.Decode(&payload); err != nil {
+			codes = append(codes, fmt.Sprintf("undecodable %s", msg.Type))
+			continue
+		}
+		codes = append(codes, payload.Code)
+	}
+	return codes
+}
+
+// drain 將送出佇列中的訊息收進紀錄；暫停讀取時不做任何事
+func (c *Client) drain() {
+	c.mu.Lock()
+	stalled := c.stalled
+	c.mu.Unlock()
+	if stalled {
+		return
+	}
+	batch, closed := c.Conn.Flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, data := range batch {
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.server.t.Errorf("%s 收到無法解析的訊息: %s", c.Name, data)
+			continue
+		}
+		c.messages = append(c.messages, msg)
+		c.state.track(msg)
+	}
+	if closed {
+		c.closed = true
+	}
+}
@@ -0,0 +1,45 @@
+package servertest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+	"zombierush/internal/server/store"
+)
+
+// 無人連線的通信對局會卸載；Hub 依保存的期限定期喚醒，由系統代為行動直到對局結束
+func TestCorrespondenceRunsWhileEveryoneIsAway(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "通信房"})
+	correspondence, hours, seed := true, 1, int64(11)
+	host.Send("room_settings", server.RoomSettingsPayload{Correspondence: &correspondence, MoveHours: &hours, Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, ok := host.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("開局後房間應為進行中，錯誤：%v", host.Errors())
+	}
+
+	host.Disconnect()
+	s.Settle()
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("無人連線的通信對局應卸載，仍有 %d 間房間", rooms)
+	}
+	saved, err := s.Store.LoadGame(state.RoomID)
+	if err != nil {
+		t.Fatalf("卸載前應保存對局：%v", err)
+	}
+	if saved.Deadline.IsZero() {
+		t.Fatal("保存的通信對局應記下下次行動的期限")
+	}
+
+	finished := s.RunUntil(func() bool {
+		_, err := s.Store.LoadGame(state.RoomID)
+		return errors.Is(err, store.ErrGameNotFound)
+	}, 14*24*time.Hour)
+	if !finished {
+		t.Fatal("即使無人連線，到期的通信對局也應由系統代為行動直到結束")
+	}
+}
@@ -0,0 +1,74 @@
+package servertest
+
+import (
+	"testing"
+	"time"
+
+	"zombierush/internal/i18n"
+	"zombierush/internal/server"
+)
+
+// 同一個錯誤依各連線的語系呈現訊息，代碼則不受語系影響；請求的 nack 同樣依語系呈現
+func TestErrorsFollowClientLocale(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "語系房"})
+	state, _ := host.RoomState()
+	bob := s.ConnectWithOptions("bob", Options{Locale: i18n.En})
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+
+	for _, c := range []*Client{bob, carol} {
+		c.Clear()
+		c.Send("start_game", server.StartGamePayload{Force: true})
+		msg, ok := c.Last("error")
+		if !ok {
+			t.Fatalf("%s 非房主開局應收到錯誤", c.Name)
+		}
+		payload := Payload[server.ErrorPayload](t, msg)
+		want := i18n.M("error.host_only.start").Localize(c.opts.Locale)
+		if payload.Code != "host_only" || payload.Message != want {
+			t.Fatalf("%s 應收到 host_only 與 %q，實際為 %+v", c.Name, want, payload)
+		}
+	}
+
+	guest := s.ConnectWithOptions("dave", Options{Locale: i18n.En})
+	guest.SendRequest("room_join", server.JoinRoomPayload{RoomID: "missing"}, "r1")
+	kind, reply := lastReply(t, guest)
+	if want := i18n.M("error.room_not_found").Localize(i18n.En); kind != "nack" || reply.Code != "room_not_found" || reply.Message != want {
+		t.Fatalf("nack 應帶 room_not_found 與 %q，實際為 %s %+v", want, kind, reply)
+	}
+}
+
+func TestPostGameWinnerFollowsLocale(t *testing.T) {
+	s := New(t)
+	host := s.ConnectWithOptions("alice", Options{Locale: i18n.En})
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	state, _ := host.RoomState()
+	guest := s.Connect("bob")
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	turn, defense, seed := 10, 5, int64(42)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	finished := s.RunUntil(func() bool {
+		state, ok := host.RoomState()
+		return ok && state.Status == server.RoomStatusFinished
+	}, 24*time.Hour)
+	if !finished {
+		t.Fatal("對局應在時限內結束")
+	}
+	english, _ := host.RoomState()
+	chinese, _ := guest.RoomState()
+	if english.PostGame == nil || chinese.PostGame == nil {
+		t.Fatal("對局結束後應附上再戰投票資訊")
+	}
+	winners := map[string]string{
+		i18n.M("team.humans").Localize(i18n.En):  i18n.M("team.humans").Localize(i18n.ZhTW),
+		i18n.M("team.zombies").Localize(i18n.En): i18n.M("team.zombies").Localize(i18n.ZhTW),
+	}
+	if want, ok := winners[english.PostGame.Winner]; !ok || chinese.PostGame.Winner != want {
+		t.Fatalf("勝方應依語系呈現，英文為 %q、中文為 %q", english.PostGame.Winner, chinese.PostGame.Winner)
+	}
+}
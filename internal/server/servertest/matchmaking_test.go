@@ -0,0 +1,160 @@
+package servertest
+
+import (
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// lastQueueStatus 回傳最近一則 queue_status
+func lastQueueStatus(t *testing.T, c *Client) server.QueueStatusPayload {
+	t.Helper()
+	msg, ok := c.Last("queue_status")
+	if !ok {
+		t.Fatalf("%s 應收到 queue_status", c.Name)
+	}
+	return Payload[server.QueueStatusPayload](t, msg)
+}
+
+// 接受機器人的玩家等滿時限才開桌，兩人同桌、其餘座位由機器人補滿並直接開局
+func TestMatchmakingTopsUpWithBotsAfterWait(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	bob := s.Connect("bob")
+	alice.Send("queue_join", server.QueueJoinPayload{})
+	bob.Send("queue_join", server.QueueJoinPayload{})
+	status := lastQueueStatus(t, bob)
+	if !status.Queued || status.Position != 2 || status.Compatible != 2 {
+		t.Fatalf("bob 應排在第二位且有兩位條件相同的玩家，實為 %+v", status)
+	}
+	topUp := time.UnixMilli(status.TopUpAt)
+
+	s.Advance(topUp.Sub(s.Clock.Now()) - time.Second)
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("等待時限前不應開桌，已有 %d 間房間", rooms)
+	}
+	s.Advance(time.Second)
+	state, ok := alice.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("等待逾時後應開桌並直接開局，錯誤為 %v", alice.Errors())
+	}
+	if other, _ := bob.RoomState(); other.RoomID != state.RoomID {
+		t.Fatal("排隊中的兩人應被配到同一桌")
+	}
+	bots := 0
+	for _, seat := range state.Seats {
+		if seat.IsBot {
+			bots++
+		}
+	}
+	if bots != len(state.Seats)-2 {
+		t.Fatalf("其餘 %d 個座位應由機器人補滿，實為 %d", len(state.Seats)-2, bots)
+	}
+}
+
+// 不接受機器人的玩家立即補進等待中的公開房間；取消排隊後不再被配對
+func TestMatchmakingJoinsOpenRoomAndCancels(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "公開房"})
+	room, _ := host.RoomState()
+
+	noBots := false
+	bob := s.Connect("bob")
+	bob.Send("queue_join", server.QueueJoinPayload{AllowBots: &noBots})
+	if state, ok := bob.RoomState(); !ok || state.RoomID != room.RoomID {
+		t.Fatalf("應立即補進等待中的公開房間，錯誤為 %v", bob.Errors())
+	}
+
+	host.Send("room_leave", nil)
+	bob.Send("room_leave", nil)
+	carol := s.Connect("carol")
+	carol.Send("queue_join", server.QueueJoinPayload{})
+	carol.Send("queue_cancel", nil)
+	if status := lastQueueStatus(t, carol); status.Queued {
+		t.Fatalf("取消後應回報已離開佇列，實為 %+v", status)
+	}
+	s.Advance(time.Hour)
+	if rooms := s.Hub.Stats().Rooms; rooms != 0 {
+		t.Fatalf("取消排隊後不應再開桌，已有 %d 間房間", rooms)
+	}
+}
+
+// 通信對局不接受配對；被房間封鎖的玩家不會一再被配回同一間，等待逾時後照常開新桌
+func TestMatchmakingSkipsCorrespondenceAndBans(t *testing.T) {
+	s := New(t)
+	slow := s.Connect("alice")
+	slow.Send("room_create", server.CreateRoomPayload{Name: "通信房"})
+	correspondence := true
+	slow.Send("room_settings", server.RoomSettingsPayload{Correspondence: &correspondence})
+
+	noBots := false
+	carol := s.Connect("carol")
+	carol.Send("queue_join", server.QueueJoinPayload{AllowBots: &noBots})
+	if _, ok := carol.RoomState(); ok {
+		t.Fatal("快速配對不應補進通信對局")
+	}
+	carol.Send("queue_cancel", nil)
+
+	host := s.Connect("dave")
+	host.Send("room_create", server.CreateRoomPayload{Name: "公開房"})
+	banned, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: banned.RoomID})
+	joined, _ := host.RoomState()
+	seat := seatNamed(t, joined, "bob").Index
+	host.Send("room_ban", server.SeatTargetPayload{Seat: &seat})
+
+	bob.Send("queue_join", server.QueueJoinPayload{})
+	status := lastQueueStatus(t, bob)
+	if !status.Queued {
+		t.Fatalf("被封鎖者加入失敗後應留在佇列，實為 %+v", status)
+	}
+	s.Advance(time.UnixMilli(status.TopUpAt).Sub(s.Clock.Now()))
+	state, ok := bob.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("等待逾時後應另開新桌，狀態為 %+v", lastQueueStatus(t, bob))
+	}
+	if state.RoomID == banned.RoomID {
+		t.Fatal("不應配回封鎖自己的房間")
+	}
+}
+
+// 配對在背景加入房間時玩家可能已自行進入其他房間：Hub 本身即拒絕已在房間內的連線，不會讓同一連線坐進兩個房間
+func TestHubRefusesClientAlreadyInRoom(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	alice.Send("room_create", server.CreateRoomPayload{Name: "甲房"})
+	first, _ := alice.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_create", server.CreateRoomPayload{Name: "乙房"})
+	second, _ := bob.RoomState()
+
+	attempts := map[string]error{
+		"加入": s.Hub.JoinRoom(second.RoomID, alice.Conn, server.JoinOptions{}),
+		"觀戰": s.Hub.SpectateRoom(second.RoomID, alice.Conn, server.JoinOptions{}),
+	}
+	_, err := s.Hub.CreateRoom("丙房", alice.Conn, server.RoomOptions{})
+	attempts["建立"] = err
+	s.Settle()
+	for action, err := range attempts {
+		if code := server.ErrorCode(err); code != "already_in_room" {
+			t.Fatalf("已在房間內時%s應回傳 already_in_room，實際為 %v", action, err)
+		}
+	}
+	if rooms := s.Hub.Stats().Rooms; rooms != 2 {
+		t.Fatalf("不應建立新房間，目前有 %d 間", rooms)
+	}
+	state, _ := bob.RoomState()
+	for _, seat := range state.Seats {
+		if seat.Name == "alice" {
+			t.Fatalf("alice 不應同時坐進乙房：%+v", seat)
+		}
+	}
+	state, _ = alice.RoomState()
+	if state.RoomID != first.RoomID {
+		t.Fatalf("alice 應留在甲房，實際在 %s", state.RoomID)
+	}
+	seatNamed(t, state, "alice")
+}
@@ -0,0 +1,73 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+
+	"zombierush/internal/server"
+)
+
+// 只有房主能踢人；被踢者可再加入，被封鎖者不行；轉讓後由新房主管理並可鎖定房間
+func TestKickBanTransferAndLock(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	alice.Send("room_create", server.CreateRoomPayload{Name: "管理房"})
+	state, _ := alice.RoomState()
+	roomID := state.RoomID
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: roomID})
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: roomID})
+	state, _ = alice.RoomState()
+	carolSeat := seatNamed(t, state, "carol").Index
+	bobSeat := seatNamed(t, state, "bob").Index
+
+	bob.Send("room_kick", server.SeatTargetPayload{Seat: &carolSeat})
+	if errs := bob.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("非房主不能踢人，錯誤為 %v", errs)
+	}
+	alice.Send("room_kick", server.SeatTargetPayload{Seat: &carolSeat})
+	if _, ok := carol.Last("room_left"); !ok {
+		t.Fatal("被踢者應被請回大廳")
+	}
+	state, _ = alice.RoomState()
+	if seat := state.Seats[carolSeat]; seat.Filled {
+		t.Fatalf("被踢者的座位應清空，實為 %+v", seat)
+	}
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: roomID})
+	if state, ok := carol.RoomState(); !ok || state.RoomID != roomID || len(carol.Errors()) > 0 {
+		t.Fatalf("只被踢出的玩家可再加入，錯誤為 %v", carol.Errors())
+	}
+
+	state, _ = alice.RoomState()
+	carolSeat = seatNamed(t, state, "carol").Index
+	alice.Send("room_ban", server.SeatTargetPayload{Seat: &carolSeat})
+	carol.Clear()
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: roomID})
+	if errs := carol.Errors(); !slices.Contains(errs, "banned") {
+		t.Fatalf("被封鎖者不能再加入，錯誤為 %v", errs)
+	}
+
+	alice.Send("room_transfer_host", server.SeatTargetPayload{Seat: &bobSeat})
+	state, _ = alice.RoomState()
+	if state.HostSeat != bobSeat {
+		t.Fatalf("房主應轉給 %d 號座位，實為 %d", bobSeat, state.HostSeat)
+	}
+	alice.Clear()
+	alice.Send("room_lock", server.LockRoomPayload{Locked: true})
+	if errs := alice.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("轉讓後原房主不能再管理房間，錯誤為 %v", errs)
+	}
+	bob.Send("room_lock", server.LockRoomPayload{Locked: true})
+	if state, _ := bob.RoomState(); !state.Locked {
+		t.Fatal("新房主應可鎖定房間")
+	}
+	dave := s.Connect("dave")
+	dave.Send("room_join", server.JoinRoomPayload{RoomID: roomID})
+	if errs := dave.Errors(); !slices.Contains(errs, "room_locked") {
+		t.Fatalf("鎖定後不應接受新玩家，錯誤為 %v", errs)
+	}
+	if _, ok := dave.Last("welcome"); ok {
+		t.Fatal("鎖定後新玩家不應入座")
+	}
+}
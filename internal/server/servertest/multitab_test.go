@@ -0,0 +1,110 @@
+package servertest
+
+import (
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// 同帳號開新分頁：舊分頁收到 superseded 後關閉，座位原樣交給新分頁，帳號在房間內仍只有一個座位
+func TestNewTabSupersedesSeatedTab(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "分頁房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	seatIndex := welcomeSeat(t, bob)
+
+	tab := s.Reconnect(bob)
+	if _, ok := bob.Last("superseded"); !ok || !bob.Closed() {
+		t.Fatal("舊分頁應收到 superseded 並被關閉")
+	}
+	if got := welcomeSeat(t, tab); got != seatIndex {
+		t.Fatalf("新分頁應接手原座位 %d，實際為 %d", seatIndex, got)
+	}
+	state, _ = host.RoomState()
+	seats := 0
+	for _, seat := range state.Seats {
+		if seat.Name == "bob" {
+			seats++
+		}
+	}
+	if seats != 1 {
+		t.Fatalf("同帳號應只佔一個座位，實際為 %d 個", seats)
+	}
+
+	tab.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if errs := tab.Errors(); len(errs) == 0 {
+		t.Fatal("已在房間內的分頁再次加入應被拒絕")
+	}
+	tab.Send("room_ready", server.ReadyPayload{Ready: true})
+	state, _ = host.RoomState()
+	if !state.Seats[seatIndex].Ready {
+		t.Fatal("新分頁應能操作接手的座位")
+	}
+}
+
+// 觀戰與排隊中的分頁被取代時：觀戰位置交給新分頁，排隊則取消
+func TestNewTabSupersedesSpectatorAndQueue(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "觀戰房"})
+	state, _ := host.RoomState()
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	carol := s.Connect("carol")
+	carol.Send("room_spectate", server.SpectatePayload{RoomID: state.RoomID})
+	if got := welcomeSeat(t, carol); got != -1 {
+		t.Fatalf("觀戰者不應有座位，實際為 %d", got)
+	}
+	tab := s.Reconnect(carol)
+	if _, ok := carol.Last("superseded"); !ok || !carol.Closed() {
+		t.Fatal("觀戰中的舊分頁應收到 superseded 並被關閉")
+	}
+	if got := welcomeSeat(t, tab); got != -1 {
+		t.Fatalf("新分頁應以觀戰身分回到房間，實際座位為 %d", got)
+	}
+	if reopened, ok := tab.RoomState(); !ok || reopened.RoomID != state.RoomID {
+		t.Fatal("新分頁應收到房間狀態")
+	}
+
+	dave := s.Connect("dave")
+	dave.Send("queue_join", server.QueueJoinPayload{})
+	if !lastQueueStatus(t, dave).Queued {
+		t.Fatal("dave 應在排隊中")
+	}
+	queued := s.Reconnect(dave)
+	if _, ok := dave.Last("superseded"); !ok || !dave.Closed() {
+		t.Fatal("排隊中的舊分頁應收到 superseded 並被關閉")
+	}
+	queued.Send("queue_join", server.QueueJoinPayload{})
+	if status := lastQueueStatus(t, queued); !status.Queued || status.Position != 1 {
+		t.Fatalf("舊分頁的排隊應已取消，新分頁重新排在第 1 位：%+v", status)
+	}
+}
+
+// 輪到自己時開新分頁：新分頁接手回合提示，回合仍在原期限逾時，不因換分頁重新計時
+func TestNewTabKeepsTurnDeadline(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "分頁房"})
+	turn, defense, seed := 10, 5, int64(7)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	due := awaitTurnOf(t, s, host, "bob", time.Duration(turn)*time.Second)
+	s.Advance(6 * time.Second)
+	tab := s.Reconnect(bob)
+	if !bob.Closed() {
+		t.Fatal("舊分頁應被關閉")
+	}
+	if _, ok := tab.Last("turn_start"); !ok {
+		t.Fatal("新分頁應收到回合提示")
+	}
+	assertDeadlineHolds(t, s, host, "bob", due)
+}
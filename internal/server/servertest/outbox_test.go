@@ -0,0 +1,126 @@
+package servertest
+
+import (
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// 與 server 套件的送出佇列上限一致
+const (
+	outboxSoftLimit     = 256
+	outboxHardLimit     = 1024
+	outboxOverflowGrace = 5 * time.Second
+)
+
+// flood 以大廳中無效的 resync 讓伺服器回覆 n 則不會被合併的錯誤
+func flood(c *Client, n int) {
+	for i := 0; i < n; i++ {
+		c.Send("resync", nil)
+	}
+}
+
+// 佇列超過軟上限時只記錄壅塞，持續達寬限時間才關閉；期間讀取過佇列則重新起算
+func TestOutboxSoftLimitGrace(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	alice.Stall()
+
+	flood(alice, outboxSoftLimit+1)
+	if stats := s.Hub.Stats(); stats.OverflowEvents != 1 || stats.DroppedClients != 0 {
+		t.Fatalf("超過軟上限應記錄壅塞但保留連線，統計為 %+v", stats)
+	}
+	s.Advance(outboxOverflowGrace - time.Second)
+	alice.Resume()
+	alice.Stall()
+
+	s.Advance(2 * time.Second)
+	flood(alice, outboxSoftLimit+1)
+	if stats := s.Hub.Stats(); stats.OverflowEvents != 2 || stats.DroppedClients != 0 {
+		t.Fatalf("讀取後寬限應重新起算，統計為 %+v", stats)
+	}
+	s.Advance(outboxOverflowGrace - time.Millisecond)
+	flood(alice, 1)
+	if dropped := s.Hub.Stats().DroppedClients; dropped != 0 {
+		t.Fatal("寬限時間內不應關閉連線")
+	}
+	s.Advance(time.Millisecond)
+	flood(alice, 1)
+	if dropped := s.Hub.Stats().DroppedClients; dropped != 1 {
+		t.Fatal("持續壅塞達寬限時間應關閉連線")
+	}
+
+	alice.Resume()
+	if !alice.Closed() {
+		t.Fatal("被關閉的連線應在送完已排入的訊息後結束")
+	}
+	if got, want := len(alice.Errors()), 2*(outboxSoftLimit+1)+2; got != want {
+		t.Fatalf("關閉前已排入的 %d 則訊息都應送出，實際收到 %d 則", want, got)
+	}
+}
+
+// 佇列超過硬上限時不等寬限立即關閉
+func TestOutboxHardLimitDrops(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	alice.Stall()
+
+	flood(alice, outboxHardLimit)
+	if dropped := s.Hub.Stats().DroppedClients; dropped != 0 {
+		t.Fatal("未超過硬上限時應在寬限內保留連線")
+	}
+	flood(alice, 1)
+	if dropped := s.Hub.Stats().DroppedClients; dropped != 1 {
+		t.Fatal("超過硬上限應立即關閉連線")
+	}
+	alice.Resume()
+	if !alice.Closed() {
+		t.Fatal("超過硬上限的連線應被關閉")
+	}
+}
+
+// 停止讀取期間的狀態訊息只保留最新一則；被取代的房間訊息移除後，序號鏈仍逐則相連
+func TestOutboxCoalescingKeepsSequenceChain(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense := 10, 5
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense})
+	state, _ := host.RoomState()
+	guest := s.Connect("bob")
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	guest.Stall()
+	mark := len(guest.Messages())
+	before := s.Hub.Stats().CoalescedMessages
+	s.Advance(30 * time.Second)
+	if s.Hub.Stats().CoalescedMessages == before {
+		t.Fatal("停止讀取期間的狀態訊息應被合併")
+	}
+	guest.Resume()
+
+	states, logs := 0, 0
+	for _, msg := range guest.Messages()[mark:] {
+		switch msg.Type {
+		case "public_state":
+			states++
+		case "log":
+			logs++
+		}
+	}
+	if states != 1 {
+		t.Fatalf("停止讀取期間的公開狀態應只剩最新一則，實際收到 %d 則", states)
+	}
+	if logs == 0 {
+		t.Fatal("不可合併的訊息應全數保留")
+	}
+	assertSequenceChain(t, guest)
+	got, _ := guest.RoomState()
+	want, _ := host.RoomState()
+	if got.Status != want.Status || got.PublicGame.CurrentTurn != want.PublicGame.CurrentTurn || got.PublicGame.CurrentRound != want.PublicGame.CurrentRound {
+		t.Fatalf("恢復讀取後應取得最新狀態，收到 %s 第 %d 輪 %d 號，應為 %s 第 %d 輪 %d 號",
+			got.Status, got.PublicGame.CurrentRound, got.PublicGame.CurrentTurn, want.Status, want.PublicGame.CurrentRound, want.PublicGame.CurrentTurn)
+	}
+}
@@ -0,0 +1,127 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// 非房主需過半真人同意才暫停；暫停期間計時器與機器人都停止、出牌被拒，房主繼續後照常計時
+func TestPauseVoteFreezesGame(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense, seed := 10, 5, int64(5)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	bob.Send("game_pause", nil)
+	state, _ = host.RoomState()
+	if state.PublicGame.Paused || state.PublicGame.PauseVotes != 1 {
+		t.Fatalf("三位真人中一票不足以暫停，狀態為 %+v", state.PublicGame)
+	}
+	carol.Send("game_pause", nil)
+	frozen, _ := host.RoomState()
+	if !frozen.PublicGame.Paused || frozen.PublicGame.TurnDeadline != 0 {
+		t.Fatalf("過半同意後應暫停並停止計時，狀態為 %+v", frozen.PublicGame)
+	}
+
+	s.Advance(time.Hour)
+	state, _ = host.RoomState()
+	if state.PublicGame.CurrentTurn != frozen.PublicGame.CurrentTurn || state.PublicGame.CurrentRound != frozen.PublicGame.CurrentRound {
+		t.Fatalf("暫停期間不應有人行動，回合由 %d 變為 %d", frozen.PublicGame.CurrentTurn, state.PublicGame.CurrentTurn)
+	}
+	clients := map[string]*Client{"alice": host, "bob": bob, "carol": carol}
+	current, ok := clients[state.Seats[state.PublicGame.CurrentTurn].Name]
+	if !ok {
+		t.Fatalf("開局後應輪到真人，實為 %d 號座位", state.PublicGame.CurrentTurn)
+	}
+	current.Send("action_challenge", server.ChallengePayload{TargetID: 1, CardIDs: []int{0}})
+	if !slices.Contains(current.Errors(), "game_paused") {
+		t.Fatalf("暫停期間出牌應被拒，錯誤為 %v", current.Errors())
+	}
+
+	host.Send("game_resume", nil)
+	state, _ = host.RoomState()
+	if state.PublicGame.Paused {
+		t.Fatalf("房主繼續應立即生效，狀態為 %+v", state.PublicGame)
+	}
+	moved := s.RunUntil(func() bool {
+		state, _ := host.RoomState()
+		return state.PublicGame == nil || state.PublicGame.CurrentTurn != frozen.PublicGame.CurrentTurn
+	}, time.Duration(turn+defense)*time.Second+time.Minute)
+	if !moved {
+		t.Fatal("繼續後回合應照常推進")
+	}
+}
+
+// 暫停時記下回合剩餘的時限，繼續後沿用而非重新計時，也不再重送 turn_start
+func TestResumeKeepsRemainingTurnTime(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense := 30, 5
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, _ := host.RoomState()
+	if state.PublicGame.CurrentTurn != seatNamed(t, state, "alice").Index {
+		t.Fatalf("開局後應輪到房主，實為 %d 號座位", state.PublicGame.CurrentTurn)
+	}
+
+	s.Advance(20 * time.Second)
+	host.Send("game_pause", nil)
+	s.Advance(time.Hour)
+	prompts := len(host.OfType("turn_start"))
+	host.Send("game_resume", nil)
+	state, _ = host.RoomState()
+	if left := time.UnixMilli(state.PublicGame.TurnDeadline).Sub(s.Clock.Now()); left != 10*time.Second {
+		t.Fatalf("繼續後應剩下暫停前的 10 秒，實為 %v", left)
+	}
+	if n := len(host.OfType("turn_start")); n != prompts {
+		t.Fatalf("繼續時不應重新開始回合，多收到 %d 則 turn_start", n-prompts)
+	}
+	s.Advance(10*time.Second - time.Millisecond)
+	if state, _ := host.RoomState(); state.PublicGame.CurrentTurn != seatNamed(t, state, "alice").Index {
+		t.Fatal("剩餘時限到期前不應代為出牌")
+	}
+	s.Advance(time.Millisecond)
+	if len(host.OfType("turn_start")) == prompts {
+		t.Fatal("剩餘時限到期後應由系統代為出牌並輪到下一位")
+	}
+}
+
+// 離開房間的玩家不再計入暫停票數，門檻依在座真人重新計算
+func TestPauseVoteDropsWhenVoterLeaves(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	bob.Send("game_pause", nil)
+	state, _ = host.RoomState()
+	if state.PublicGame.PauseVotes != 1 {
+		t.Fatalf("應有一票暫停，實為 %d", state.PublicGame.PauseVotes)
+	}
+	bob.Disconnect()
+	state, _ = host.RoomState()
+	if state.PublicGame.PauseVotes != 0 {
+		t.Fatalf("離開者的票應移除，實為 %d", state.PublicGame.PauseVotes)
+	}
+	carol.Send("game_pause", nil)
+	state, _ = host.RoomState()
+	if state.PublicGame.Paused || state.PublicGame.PauseVotes != 1 {
+		t.Fatalf("剩兩位真人時一票不足以暫停，狀態為 %+v", state.PublicGame)
+	}
+}
@@ -0,0 +1,61 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+
+	"zombierush/internal/server"
+)
+
+// 除房主外的真人都準備後才能開局，強制開局可略過準備檢查，但略不過最少真人數
+func TestReadyCheckAndMinHumans(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "準備房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+
+	host.Send("start_game", server.StartGamePayload{})
+	if errs := host.Errors(); !slices.Contains(errs, "players_not_ready") {
+		t.Fatalf("有人未準備時不應開局，錯誤為 %v", errs)
+	}
+	bob.Send("room_ready", server.ReadyPayload{Ready: true})
+	state, _ = host.RoomState()
+	if !seatNamed(t, state, "bob").Ready {
+		t.Fatal("準備後座位應顯示已準備")
+	}
+	bob.Send("room_ready", server.ReadyPayload{Ready: false})
+
+	bob.Send("room_min_humans", server.MinHumansPayload{MinHumans: 3})
+	if errs := bob.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("只有房主能設定最少真人數，錯誤為 %v", errs)
+	}
+	host.Send("room_min_humans", server.MinHumansPayload{MinHumans: 9})
+	host.Send("room_min_humans", server.MinHumansPayload{MinHumans: 3})
+	state, _ = host.RoomState()
+	if state.MinHumans != 3 {
+		t.Fatalf("超出座位數的門檻應被拒，之後的設定應生效，門檻為 %d", state.MinHumans)
+	}
+
+	host.Clear()
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	if errs := host.Errors(); len(errs) != 1 || errs[0] != "not_enough_humans" {
+		t.Fatalf("真人數不足時強制開局也應被拒，錯誤為 %v", errs)
+	}
+	if state, _ := host.RoomState(); state.Status != server.RoomStatusLobby {
+		t.Fatalf("開局失敗後應停留在待機，實為 %s", state.Status)
+	}
+
+	host.Send("room_min_humans", server.MinHumansPayload{MinHumans: 2})
+	host.Clear()
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, _ = host.RoomState()
+	if state.Status != server.RoomStatusRunning {
+		t.Fatalf("真人數達門檻時強制開局應略過準備檢查，錯誤為 %v", host.Errors())
+	}
+	bob.Send("room_ready", server.ReadyPayload{Ready: true})
+	if errs := bob.Errors(); !slices.Contains(errs, "ready_not_lobby") {
+		t.Fatalf("開局後不應再切換準備狀態，錯誤為 %v", errs)
+	}
+}
@@ -0,0 +1,158 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// runToPostGame 以短時限讓系統代打，推進到賽後階段
+func runToPostGame(t *testing.T, s *Server, c *Client) {
+	t.Helper()
+	finished := s.RunUntil(func() bool {
+		state, ok := c.RoomState()
+		return ok && state.Status == server.RoomStatusFinished
+	}, 24*time.Hour)
+	if !finished {
+		t.Fatal("對局應在時限內結束")
+	}
+}
+
+// 再戰時房主加入的機器人保留原座位與名稱，只有空座位才補上新的機器人
+func TestRematchKeepsExistingBots(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "再戰房"})
+	turn, defense, seed := 10, 5, int64(3)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	host.Send("room_add_bot", server.BotCommandPayload{Name: "小明"})
+	state, _ := host.RoomState()
+	if name := state.Seats[1].Name; name != "小明" {
+		t.Fatalf("機器人應坐在 1 號座位，實為 %q", name)
+	}
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	runToPostGame(t, s, host)
+
+	host.Send("rematch_vote", server.RematchVotePayload{Vote: server.RematchVoteRematch})
+	host.Send("rematch_start", nil)
+	state, ok := host.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("再戰後房間應為進行中，錯誤：%v", host.Errors())
+	}
+	if seat := state.Seats[1]; !seat.IsBot || seat.Name != "小明" {
+		t.Fatalf("再戰應沿用原有的機器人，1 號座位為 %+v", seat)
+	}
+	for _, seat := range state.Seats[2:] {
+		if !seat.IsBot || seat.Name == "" {
+			t.Fatalf("空座位應補上機器人，%d 號座位為 %+v", seat.Index, seat)
+		}
+	}
+}
+
+// 再戰需在座真人過半同意；投票離開的玩家在再戰開局時被請回大廳，座位改由機器人補上
+func TestRematchVoteQuorum(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "再戰房"})
+	turn, defense, seed := 10, 5, int64(9)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	carol := s.Connect("carol")
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	state, _ = host.RoomState()
+	carolSeat := seatNamed(t, state, "carol").Index
+	runToPostGame(t, s, host)
+
+	host.Send("rematch_vote", server.RematchVotePayload{Vote: server.RematchVoteRematch})
+	host.Send("rematch_start", nil)
+	if errs := host.Errors(); !slices.Contains(errs, "rematch_votes") {
+		t.Fatalf("票數未過半時不應開局，錯誤為 %v", errs)
+	}
+	state, _ = host.RoomState()
+	if state.Status != server.RoomStatusFinished {
+		t.Fatalf("票數未過半時應停留在賽後階段，實為 %s", state.Status)
+	}
+	if post := state.PostGame; post == nil || post.RematchVotes != 1 || post.Required != 2 || post.QuorumReached {
+		t.Fatalf("三位真人應需兩票，賽後狀態為 %+v", state.PostGame)
+	}
+
+	bob.Send("rematch_vote", server.RematchVotePayload{Vote: server.RematchVoteRematch})
+	carol.Send("rematch_vote", server.RematchVotePayload{Vote: server.RematchVoteLeave})
+	state, _ = host.RoomState()
+	if post := state.PostGame; !post.QuorumReached || post.LeaveVotes != 1 {
+		t.Fatalf("兩票同意後應達門檻，賽後狀態為 %+v", post)
+	}
+	host.Send("rematch_start", nil)
+	state, _ = host.RoomState()
+	if state.Status != server.RoomStatusRunning {
+		t.Fatalf("達門檻後應開始再戰，錯誤為 %v", host.Errors())
+	}
+	if _, ok := carol.Last("room_left"); !ok {
+		t.Fatal("投票離開的玩家應被請回大廳")
+	}
+	if seat := state.Seats[carolSeat]; !seat.IsBot {
+		t.Fatalf("離開者的座位應由機器人補上，實為 %+v", seat)
+	}
+}
+
+// 賽後時限內未開始再戰時房間回到待機
+func TestRematchTimeoutReturnsToLobby(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "再戰房"})
+	turn, defense, seed := 10, 5, int64(9)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	runToPostGame(t, s, host)
+	state, _ := host.RoomState()
+	deadline := time.UnixMilli(state.PostGame.Deadline)
+
+	s.Advance(deadline.Sub(s.Clock.Now()) - time.Second)
+	if state, _ := host.RoomState(); state.Status != server.RoomStatusFinished {
+		t.Fatalf("時限前應停留在賽後階段，實為 %s", state.Status)
+	}
+	s.Advance(time.Second)
+	if state, _ := host.RoomState(); state.Status != server.RoomStatusLobby || state.PostGame != nil {
+		t.Fatalf("賽後時限到期應回到待機，實為 %s", state.Status)
+	}
+}
+
+// 離開房間的玩家不再計入再戰票數；非房主不能結束賽後階段，房主可直接返回待機
+func TestRematchLeaverAndReturnToLobby(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "再戰房"})
+	turn, defense, seed := 10, 5, int64(9)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	runToPostGame(t, s, host)
+
+	bob.Send("rematch_vote", server.RematchVotePayload{Vote: server.RematchVoteRematch})
+	state, _ = host.RoomState()
+	if post := state.PostGame; post.RematchVotes != 1 || post.Required != 2 {
+		t.Fatalf("兩位真人時一票不應達門檻，賽後狀態為 %+v", post)
+	}
+	bob.Send("rematch_lobby", nil)
+	if errs := bob.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("非房主不能結束賽後階段，錯誤為 %v", errs)
+	}
+	bob.Send("room_leave", nil)
+	state, _ = host.RoomState()
+	if post := state.PostGame; post.RematchVotes != 0 || post.Required != 1 {
+		t.Fatalf("離開者的票應一併移除，賽後狀態為 %+v", post)
+	}
+
+	host.Send("rematch_lobby", nil)
+	state, _ = host.RoomState()
+	if state.Status != server.RoomStatusLobby || state.PostGame != nil {
+		t.Fatalf("房主應可直接返回待機，實為 %s", state.Status)
+	}
+}
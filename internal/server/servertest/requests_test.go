@@ -0,0 +1,71 @@
+package servertest
+
+import (
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// lastReply 回傳最後一則 ack 或 nack 及其類型
+func lastReply(t *testing.T, c *Client) (string, server.RequestReplyPayload) {
+	t.Helper()
+	var kind string
+	var reply server.RequestReplyPayload
+	for _, msg := range c.Messages() {
+		if msg.Type == "ack" || msg.Type == "nack" {
+			kind = msg.Type
+			reply = Payload[server.RequestReplyPayload](t, msg)
+		}
+	}
+	if kind == "" {
+		t.Fatal("應收到 ack 或 nack")
+	}
+	return kind, reply
+}
+
+func TestDuplicateRequestWithinAndBeyondWindow(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	bob := s.Connect("bob")
+	create := server.CreateRoomPayload{Name: "測試房"}
+
+	alice.SendRequest("room_create", create, "r1")
+	if kind, reply := lastReply(t, alice); kind != "ack" || reply.Duplicate {
+		t.Fatalf("首次建立房間應收到 ack，實際為 %s %+v", kind, reply)
+	}
+	alice.Clear()
+	alice.SendRequest("room_create", create, "r1")
+	if kind, reply := lastReply(t, alice); kind != "ack" || !reply.Duplicate {
+		t.Fatalf("時限內重送應直接補發 ack，實際為 %s %+v", kind, reply)
+	}
+	if len(alice.Errors()) > 0 || len(alice.OfType("welcome")) > 0 {
+		t.Fatal("重送的請求不應再次執行")
+	}
+	if got := s.Hub.Stats().Rooms; got != 1 {
+		t.Fatalf("應只有一間房間，實際為 %d", got)
+	}
+
+	bob.SendRequest("room_join", server.JoinRoomPayload{RoomID: "missing"}, "r1")
+	if kind, reply := lastReply(t, bob); kind != "nack" || reply.Code != "room_not_found" || reply.Duplicate {
+		t.Fatalf("不同帳號使用相同 ID 應各自處理，實際為 %s %+v", kind, reply)
+	}
+	bob.Clear()
+	bob.SendRequest("room_join", server.JoinRoomPayload{RoomID: "missing"}, "r1")
+	if kind, reply := lastReply(t, bob); kind != "nack" || !reply.Duplicate || reply.Code != "room_not_found" {
+		t.Fatalf("失敗的請求重送時應補發相同的 nack，實際為 %s %+v", kind, reply)
+	}
+	if got := s.Hub.Stats().RequestLogAccounts; got != 2 {
+		t.Fatalf("兩個帳號都應保存請求紀錄，實際為 %d", got)
+	}
+
+	s.Advance(2*time.Minute + time.Second)
+	alice.Clear()
+	alice.SendRequest("room_create", create, "r1")
+	if kind, reply := lastReply(t, alice); kind != "nack" || reply.Duplicate || reply.Code != "already_in_room" {
+		t.Fatalf("逾時後相同 ID 應視為新請求並重新執行，實際為 %s %+v", kind, reply)
+	}
+	if got := s.Hub.Stats().RequestLogAccounts; got != 1 {
+		t.Fatalf("逾時後未再送出請求的帳號應被清除，實際保存 %d 個帳號", got)
+	}
+}
@@ -0,0 +1,105 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+
+	"zombierush/internal/server"
+)
+
+// welcomeSeat 回傳最近一則 welcome 中的座位
+func welcomeSeat(t *testing.T, c *Client) int {
+	t.Helper()
+	msg, ok := c.Last("welcome")
+	if !ok {
+		t.Fatalf("%s 應收到 welcome", c.Name)
+	}
+	return Payload[struct {
+		SeatIndex int `json:"seatIndex"`
+	}](t, msg).SeatIndex
+}
+
+// 玩家可移到空位；換到有人的座位需對方同意，房主身分隨人移動
+func TestChooseAndSwapSeats(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	alice.Send("room_create", server.CreateRoomPayload{Name: "座位房"})
+	state, _ := alice.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+
+	empty, taken := 5, 0
+	bob.Send("seat_choose", server.SeatTargetPayload{Seat: &taken})
+	if errs := bob.Errors(); !slices.Contains(errs, "seat_taken") {
+		t.Fatalf("不能直接坐到有人的座位，錯誤為 %v", errs)
+	}
+	bob.Send("seat_choose", server.SeatTargetPayload{Seat: &empty})
+	if seat := welcomeSeat(t, bob); seat != empty {
+		t.Fatalf("應移到 %d 號座位，實為 %d", empty, seat)
+	}
+
+	bob.Send("seat_swap_request", server.SeatTargetPayload{Seat: &taken})
+	msg, ok := alice.Last("seat_swap_request")
+	if !ok {
+		t.Fatal("被請求換位的玩家應收到通知")
+	}
+	if from := Payload[server.SeatSwapRequestPayload](t, msg).FromSeat; from != empty {
+		t.Fatalf("換位請求應來自 %d 號座位，實為 %d", empty, from)
+	}
+	alice.Send("seat_swap_response", server.SeatResponsePayload{Seat: &empty, Accept: true})
+	if welcomeSeat(t, bob) != taken || welcomeSeat(t, alice) != empty {
+		t.Fatal("同意後兩人應互換座位")
+	}
+	state, _ = alice.RoomState()
+	if state.HostSeat != empty {
+		t.Fatalf("房主身分應隨人移到 %d 號座位，實為 %d", empty, state.HostSeat)
+	}
+
+	alice.Send("seat_swap_request", server.SeatTargetPayload{Seat: &taken})
+	bob.Send("seat_swap_response", server.SeatResponsePayload{Seat: &empty, Accept: false})
+	if welcomeSeat(t, alice) != empty {
+		t.Fatal("拒絕後座位不應變動")
+	}
+	if _, ok := alice.Last("private_info"); !ok {
+		t.Fatal("提出者應收到被拒絕的通知")
+	}
+	bob.Send("seat_swap_response", server.SeatResponsePayload{Seat: &empty, Accept: true})
+	if errs := bob.Errors(); !slices.Contains(errs, "swap_expired") {
+		t.Fatalf("已回應的請求不能再次接受，錯誤為 %v", errs)
+	}
+}
+
+// 只有房主能打亂座位；打亂後每位玩家仍各有座位，房主身分不變
+func TestShuffleSeats(t *testing.T) {
+	s := New(t)
+	alice := s.Connect("alice")
+	alice.Send("room_create", server.CreateRoomPayload{Name: "座位房"})
+	state, _ := alice.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	alice.Send("room_add_bot", server.BotCommandPayload{Name: "小明"})
+
+	bob.Send("seat_shuffle", nil)
+	if errs := bob.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("非房主不能打亂座位，錯誤為 %v", errs)
+	}
+	alice.Send("seat_shuffle", nil)
+	state, _ = alice.RoomState()
+	aliceSeat, bobSeat := welcomeSeat(t, alice), welcomeSeat(t, bob)
+	if state.Seats[aliceSeat].Name != "alice" || state.Seats[bobSeat].Name != "bob" {
+		t.Fatalf("打亂後 welcome 應對應各自的座位，alice 為 %d、bob 為 %d", aliceSeat, bobSeat)
+	}
+	if state.HostSeat != aliceSeat {
+		t.Fatalf("房主應仍為 alice，實為 %d 號座位", state.HostSeat)
+	}
+	if bot := seatNamed(t, state, "小明"); !bot.IsBot {
+		t.Fatalf("機器人應隨座位移動，實為 %+v", bot)
+	}
+
+	alice.Send("start_game", server.StartGamePayload{Force: true})
+	alice.Clear()
+	alice.Send("seat_shuffle", nil)
+	if errs := alice.Errors(); !slices.Contains(errs, "seating_lobby_only") {
+		t.Fatalf("開局後不能打亂座位，錯誤為 %v", errs)
+	}
+}
@@ -0,0 +1,134 @@
+package servertest
+
+import (
+	"testing"
+
+	"zombierush/internal/i18n"
+	"zombierush/internal/server"
+)
+
+// welcomeToken 回傳連線最近一則 welcome 附帶的座位憑證
+func welcomeToken(t *testing.T, c *Client) string {
+	t.Helper()
+	msg, ok := c.Last("welcome")
+	if !ok {
+		t.Fatalf("%s 應收到 welcome", c.Name)
+	}
+	return Payload[struct {
+		Token string `json:"token"`
+	}](t, msg).Token
+}
+
+// 座位憑證必須由入座帳號出示：他人拿到憑證也無法取得座位
+func TestSeatTokenBelongsToAccount(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "憑證房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	seatIndex, token := welcomeSeat(t, bob), welcomeToken(t, bob)
+	if token == "" {
+		t.Fatal("入座時應取得座位憑證")
+	}
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	bob.Disconnect()
+	s.Settle()
+
+	carol := s.ConnectWithOptions("carol", Options{SeatToken: token})
+	carol.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	msg, ok := carol.Last("error")
+	if !ok {
+		t.Fatal("出示他人的座位憑證應被拒絕")
+	}
+	if got, want := Payload[server.ErrorPayload](t, msg).Message, i18n.M("error.seat_token_mismatch").String(); got != want {
+		t.Fatalf("拒絕原因應為憑證不符，實際為 %q", got)
+	}
+	if _, ok := carol.Last("welcome"); ok {
+		t.Fatal("憑證不符時不應進入房間")
+	}
+	state, _ = host.RoomState()
+	if seat := state.Seats[seatIndex]; !seat.IsBot {
+		t.Fatalf("bob 的座位應仍由代打 AI 保留：%+v", seat)
+	}
+	for _, seat := range state.Seats {
+		if seat.Name == "carol" {
+			t.Fatalf("carol 不應取得座位：%+v", seat)
+		}
+	}
+
+	back := s.Reconnect(bob)
+	back.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if got := welcomeSeat(t, back); got != seatIndex {
+		t.Fatalf("bob 應仍能取回原座位 %d，實際為 %d", seatIndex, got)
+	}
+}
+
+// 入座帳號換新連線且未帶憑證時，仍可依帳號取回原座位
+func TestOwnerReclaimsSeatWithoutToken(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "憑證房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	seatIndex := welcomeSeat(t, bob)
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	bob.Disconnect()
+	s.Settle()
+
+	fresh := s.Reconnect(bob)
+	if _, ok := fresh.Last("welcome"); ok {
+		t.Fatal("舊連線已中斷，新連線不應自動回到房間")
+	}
+	fresh.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if got := welcomeSeat(t, fresh); got != seatIndex {
+		t.Fatalf("應回到原座位 %d，實際為 %d", seatIndex, got)
+	}
+	if welcomeToken(t, fresh) == "" {
+		t.Fatal("重連後應重新取得座位憑證")
+	}
+	state, _ = fresh.RoomState()
+	if seat := seatNamed(t, state, "bob"); seat.IsBot || seat.Offline {
+		t.Fatalf("重連後座位應由真人持有：%+v", seat)
+	}
+}
+
+// 訪客的帳號 ID 為 0，不會被當成 AI 座位（同為 0）的主人而接手座位
+func TestGuestNeverMatchesBotSeat(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "訪客房"})
+	state, _ := host.RoomState()
+	host.Send("room_add_bot", server.BotCommandPayload{})
+
+	guest := s.ConnectGuest("visitor")
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if errs := guest.Errors(); len(errs) != 0 {
+		t.Fatalf("訪客應能加入大廳中的房間，錯誤：%v", errs)
+	}
+	state, _ = host.RoomState()
+	if seat := state.Seats[welcomeSeat(t, guest)]; seat.IsBot || seat.Name != "visitor" {
+		t.Fatalf("訪客應坐進空位而非 AI 座位：%+v", seat)
+	}
+	bots := 0
+	for _, seat := range state.Seats {
+		if seat.IsBot {
+			bots++
+		}
+	}
+	if bots != 1 {
+		t.Fatalf("AI 座位應保留，目前有 %d 個", bots)
+	}
+
+	guest.Send("room_leave", nil)
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	guest.Clear()
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if _, ok := guest.Last("welcome"); ok {
+		t.Fatal("遊戲進行中訪客不應接手 AI 座位")
+	}
+	if errs := guest.Errors(); len(errs) == 0 {
+		t.Fatal("遊戲進行中加入應被拒絕")
+	}
+}
@@ -0,0 +1,176 @@
+// Package servertest 在同一行程內架設完整的 Hub：資料存於記憶體、時間由 clock.Fake 推進，
+// 測試以假連線送出 ClientMessage 並檢查收到的 ServerMessage，不需真正的 websocket。
+// 所有操作在返回前都已處理完畢，機器人回合與各種逾時只在呼叫 Advance 或 RunUntil 時發生
+package servertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"zombierush/internal/clock"
+	"zombierush/internal/i18n"
+	"zombierush/internal/server"
+	"zombierush/internal/server/store"
+)
+
+// Epoch 為假時鐘的起始時間
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Server 為測試用的伺服器
+type Server struct {
+	Hub   *server.Hub
+	Store *store.Store
+	Clock *clock.Fake
+
+	t       testing.TB
+	clients []*Client
+}
+
+// Options 為假連線的設定，對應 /ws 的 lang、protocol 與 token 參數；
+// AutoAck 模擬網頁前端，收到房間公開狀態後自動以 state_ack 確認
+type Options struct {
+	Locale    i18n.Locale
+	Protocol  int
+	SeatToken string
+	AutoAck   bool
+}
+
+// New 建立測試伺服器，測試結束時自動中斷所有連線並關閉資料庫
+func New(t testing.TB) *Server {
+	t.Helper()
+	st, err := store.NewMemory()
+	if err != nil {
+		t.Fatalf("建立記憶體資料庫失敗: %v", err)
+	}
+	clk := clock.NewFake(Epoch)
+	st.SetClock(clk)
+
+	hub := server.NewHub()
+	hub.SetClock(clk)
+	hub.SetArchive(st)
+
+	s := &Server{Hub: hub, Store: st, Clock: clk, t: t}
+	t.Cleanup(func() {
+		for _, c := range s.clients {
+			c.Conn.Disconnect()
+		}
+		hub.WaitIdle()
+		_ = st.Close()
+	})
+	return s
+}
+
+// Connect 註冊帳號並以預設語系與最舊協定版本連線
+func (s *Server) Connect(name string) *Client {
+	s.t.Helper()
+	return s.ConnectWithOptions(name, Options{})
+}
+
+// ConnectWithOptions 註冊帳號並依 opts 連線；帳號名稱即顯示名稱
+func (s *Server) ConnectWithOptions(name string, opts Options) *Client {
+	s.t.Helper()
+	user, err := s.Store.CreateUser(name, "password")
+	if err != nil {
+		s.t.Fatalf("註冊 %s 失敗: %v", name, err)
+	}
+	return s.connect(user.ID, user.Username, opts)
+}
+
+// ConnectGuest 以未註冊的訪客身分（帳號 ID 為 0）連線
+func (s *Server) ConnectGuest(name string) *Client {
+	s.t.Helper()
+	return s.connect(0, name, Options{})
+}
+
+// Reconnect 以同一帳號開啟新連線，如同玩家在新分頁登入；舊連線會收到 superseded 後關閉
+func (s *Server) Reconnect(c *Client) *Client {
+	s.t.Helper()
+	return s.connect(c.UserID, c.Name, c.opts)
+}
+
+func (s *Server) connect(userID int64, name string, opts Options) *Client {
+	conn := server.NewLocalClient(s.Hub, userID, name, name)
+	if opts.Locale != "" {
+		conn.SetLocale(opts.Locale)
+	}
+	if opts.Protocol != 0 {
+		protocol, err := server.NegotiateProtocol(fmt.Sprint(opts.Protocol))
+		if err != nil {
+			s.t.Fatalf("協定版本 %d 無效: %v", opts.Protocol, err)
+		}
+		conn.SetProtocol(protocol)
+	}
+	if opts.SeatToken != "" {
+		conn.SetSeatToken(opts.SeatToken)
+	}
+	c := &Client{Conn: conn, UserID: userID, Name: name, server: s, opts: opts}
+	s.clients = append(s.clients, c)
+	s.Hub.Connect(conn)
+	s.Settle()
+	return c
+}
+
+// Settle 等待房間另起的 Hub 通知完成，並將所有連線已送出的訊息收進各自的紀錄；
+// 設定 AutoAck 的連線隨即確認收到的狀態版本
+func (s *Server) Settle() {
+	for {
+		s.Hub.WaitIdle()
+		acked := false
+		for _, c := range s.clients {
+			c.drain()
+			if version := c.pendingAck(); version > 0 && !c.Closed() {
+				c.Conn.Receive(ackMessage(version))
+				acked = true
+			}
+		}
+		if !acked {
+			return
+		}
+	}
+}
+
+func ackMessage(version int64) server.ClientMessage {
+	payload, _ := json.Marshal(server.StateAckPayload{Target: "public_state", Version: version})
+	return server.ClientMessage{Type: "state_ack", Payload: payload}
+}
+
+// Advance 將時間推進 d；途中每一項排程（機器人回合、逾時等）執行後都會 Settle
+func (s *Server) Advance(d time.Duration) {
+	deadline := s.Clock.Now().Add(d)
+	for {
+		next, ok := s.Clock.Next()
+		if !ok || s.Clock.Now().Add(next).After(deadline) {
+			break
+		}
+		s.Clock.Advance(next)
+		s.Settle()
+	}
+	s.Clock.Advance(deadline.Sub(s.Clock.Now()))
+	s.Settle()
+}
+
+// RunUntil 逐項執行排程直到 done 成立，最多推進 limit；回傳 done 是否成立
+func (s *Server) RunUntil(done func() bool, limit time.Duration) bool {
+	deadline := s.Clock.Now().Add(limit)
+	for !done() {
+		next, ok := s.Clock.Next()
+		if !ok || s.Clock.Now().Add(next).After(deadline) {
+			return false
+		}
+		s.Clock.Advance(next)
+		s.Settle()
+	}
+	return true
+}
+
+// Payload 將訊息內容解碼為 T，失敗時終止測試
+func Payload[T any](t testing.TB, msg Message) T {
+	t.Helper()
+	var payload T
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("解碼 %s 失敗: %v", msg.Type, err)
+	}
+	return payload
+}
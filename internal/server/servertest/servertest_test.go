@@ -0,0 +1,108 @@
+package servertest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+func TestBotGameRunsToCompletion(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	turn, defense, seed := 10, 5, int64(42)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	state, ok := host.RoomState()
+	if !ok || state.Status != server.RoomStatusRunning {
+		t.Fatalf("開局後房間應為進行中，錯誤：%v", host.Errors())
+	}
+	finished := s.RunUntil(func() bool {
+		state, ok := host.RoomState()
+		return ok && state.Status == server.RoomStatusFinished
+	}, 24*time.Hour)
+	if !finished {
+		t.Fatal("真人逾時由系統代打，對局應在時限內結束")
+	}
+	if errs := host.Errors(); len(errs) > 0 {
+		t.Fatalf("對局中不應出現錯誤：%v", errs)
+	}
+	assertSequenceChain(t, host)
+}
+
+// assertSequenceChain 確認送出佇列合併狀態訊息後，房間訊息的 prev 仍接續上一則收到的 seq
+func assertSequenceChain(t *testing.T, c *Client) {
+	t.Helper()
+	var last int64
+	for _, msg := range c.Messages() {
+		if msg.Seq == 0 {
+			continue
+		}
+		if msg.Type == "welcome" {
+			last = 0
+		}
+		if msg.Prev != last {
+			t.Fatalf("%s 的 prev 為 %d，上一則房間訊息為 %d", msg.Type, msg.Prev, last)
+		}
+		last = msg.Seq
+	}
+}
+
+// seatNamed 回傳房間公開狀態中指定名稱的座位
+func seatNamed(t *testing.T, state server.PublicRoomStatePayload, name string) server.SeatPublicSnapshot {
+	t.Helper()
+	for _, seat := range state.Seats {
+		if seat.Name == name {
+			return seat
+		}
+	}
+	t.Fatalf("找不到 %s 的座位", name)
+	return server.SeatPublicSnapshot{}
+}
+
+func TestReconnectTakesOverSeat(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	state, _ := host.RoomState()
+
+	guest := s.Connect("bob")
+	guest.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	replacement := s.Reconnect(guest)
+	if _, ok := guest.Last("superseded"); !ok || !guest.Closed() {
+		t.Fatal("舊連線應收到 superseded 並被關閉")
+	}
+	if _, ok := replacement.Last("welcome"); !ok {
+		t.Fatal("新連線應直接回到房間")
+	}
+	state, _ = replacement.RoomState()
+	for _, seat := range state.Seats {
+		if seat.Name == "bob" && (seat.IsBot || seat.Offline) {
+			t.Fatalf("重連後座位應由真人持有：%+v", seat)
+		}
+	}
+	if _, ok := replacement.Last("private_state"); !ok {
+		t.Fatal("重連後應收到私人狀態")
+	}
+}
+
+// 房間 ID 取自注入的時鐘；同一時刻建立的房間依序遞增，不會重複
+func TestRoomIDsFollowClock(t *testing.T) {
+	s := New(t)
+	for i, name := range []string{"alice", "bob", "carol"} {
+		c := s.Connect(name)
+		c.Send("room_create", server.CreateRoomPayload{Name: name})
+		state, ok := c.RoomState()
+		if !ok {
+			t.Fatalf("%s 應建立房間，錯誤：%v", name, c.Errors())
+		}
+		if want := fmt.Sprintf("room-%d", Epoch.UnixNano()+int64(i)); state.RoomID != want {
+			t.Fatalf("第 %d 間房間 ID 應為 %s，實際為 %s", i+1, want, state.RoomID)
+		}
+	}
+}
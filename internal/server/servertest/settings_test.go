@@ -0,0 +1,75 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+
+	"zombierush/internal/server"
+)
+
+// playSeededGame 以指定種子讓機器人與代打打完一局，回傳整局的行動紀錄
+func playSeededGame(t *testing.T, seed int64) []string {
+	t.Helper()
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "種子房"})
+	turn, defense := 10, 5
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	runToPostGame(t, s, host)
+
+	logs := make([]string, 0)
+	for _, msg := range host.OfType("log") {
+		logs = append(logs, Payload[server.LogPayload](t, msg).Message)
+	}
+	return logs
+}
+
+// 指定種子時不只發牌相同，機器人的每一步也相同，整局可重現
+func TestSeedReplaysWholeGame(t *testing.T) {
+	first := playSeededGame(t, 42)
+	second := playSeededGame(t, 42)
+	if len(first) == 0 {
+		t.Fatal("對局應留下行動紀錄")
+	}
+	if !slices.Equal(first, second) {
+		t.Fatalf("相同種子的兩局應完全相同，紀錄分別有 %d 與 %d 則", len(first), len(second))
+	}
+}
+
+// 設定只在待機時由房主調整，超出範圍的值被拒且不影響原設定
+func TestRoomSettingsValidation(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "設定房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+
+	turn := 30
+	bob.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn})
+	if errs := bob.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("非房主不能調整設定，錯誤為 %v", errs)
+	}
+	tooShort, hard := 3, server.BotDifficultyHard
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &tooShort, BotDifficulty: &hard})
+	if errs := host.Errors(); !slices.Contains(errs, "turn_timeout_range") {
+		t.Fatalf("超出範圍的回合時限應被拒，錯誤為 %v", errs)
+	}
+	state, _ = host.RoomState()
+	if state.Settings.TurnTimeout == tooShort || state.Settings.BotDifficulty == hard {
+		t.Fatalf("被拒的設定不應部分生效，設定為 %+v", state.Settings)
+	}
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, BotDifficulty: &hard})
+	state, _ = host.RoomState()
+	if state.Settings.TurnTimeout != turn || state.Settings.BotDifficulty != hard {
+		t.Fatalf("有效的設定應生效，設定為 %+v", state.Settings)
+	}
+
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	host.Clear()
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn})
+	if errs := host.Errors(); !slices.Contains(errs, "settings_lobby_only") {
+		t.Fatalf("開局後不能調整設定，錯誤為 %v", errs)
+	}
+}
@@ -0,0 +1,103 @@
+package servertest
+
+import (
+	"encoding/json"
+
+	"zombierush/internal/jsonpatch"
+	"zombierush/internal/server"
+)
+
+// stateTracker 依收到的房間訊息重建公開狀態：保存各版本供套用 state_patch，並記錄已確認的版本
+type stateTracker struct {
+	// states 保存收到的公開狀態版本；current 為最新版本，acked 為已確認的版本
+	states  map[int64]any
+	current int64
+	acked   int64
+	// failures 為缺少基準版本或套用失敗的 state_patch 數
+	failures int
+}
+
+// track 依房間訊息更新公開狀態的版本紀錄；welcome 表示進入新房間，先前的版本一併清除
+func (s *stateTracker) track(msg Message) {
+	if s.states == nil {
+		s.states = make(map[int64]any)
+	}
+	switch msg.Type {
+	case "welcome":
+		s.states = make(map[int64]any)
+		s.current, s.acked = 0, 0
+	case "public_state":
+		var doc any
+		if msg.Decode(&doc) == nil {
+			s.store(msg.Version, doc)
+		}
+	case "resync":
+		var payload struct {
+			Public  json.RawMessage `json:"public"`
+			Version int64           `json:"version"`
+		}
+		var doc any
+		if msg.Decode(&payload) == nil && json.Unmarshal(payload.Public, &doc) == nil {
+			s.store(payload.Version, doc)
+		}
+	case "state_patch":
+		var patch struct {
+			Target string                `json:"target"`
+			Base   int64                 `json:"base"`
+			Ops    []jsonpatch.Operation `json:"ops"`
+		}
+		if msg.Decode(&patch) != nil || patch.Target != "public_state" {
+			return
+		}
+		base, ok := s.states[patch.Base]
+		if !ok {
+			s.failures++
+			return
+		}
+		doc, err := jsonpatch.Apply(base, patch.Ops)
+		if err != nil {
+			s.failures++
+			return
+		}
+		s.store(msg.Version, doc)
+	}
+}
+
+func (s *stateTracker) store(version int64, doc any) {
+	s.states[version] = doc
+	s.current = version
+}
+
+// RoomState 回傳最新的房間公開狀態，已套用收到的 state_patch；resync 所附的完整狀態同樣計入
+func (c *Client) RoomState() (server.PublicRoomStatePayload, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var state server.PublicRoomStatePayload
+	doc, ok := c.state.states[c.state.current]
+	if !ok {
+		return state, false
+	}
+	data, err := json.Marshal(doc)
+	if err != nil || json.Unmarshal(data, &state) != nil {
+		return state, false
+	}
+	return state, true
+}
+
+// PatchFailures 回傳無法套用的 state_patch 數；正常情況下應為 0
+func (c *Client) PatchFailures() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.failures
+}
+
+// pendingAck 回傳需要以 state_ack 確認的版本；沒有新版本時回傳 0
+func (c *Client) pendingAck() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.opts.AutoAck || c.state.current <= c.state.acked {
+		return 0
+	}
+	c.state.acked = c.state.current
+	return c.state.current
+}
@@ -0,0 +1,154 @@
+package servertest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"zombierush/internal/server"
+)
+
+// 未確認過版本時只送完整狀態；確認後改送以確認版本為基準的差異，
+// 確認的版本超出保留的歷史後退回完整狀態
+func TestStatePatchesFollowAcks(t *testing.T) {
+	s := New(t)
+	host := s.ConnectWithOptions("alice", Options{Protocol: 3})
+	host.Send("room_create", server.CreateRoomPayload{Name: "測試房"})
+	// 每次變更設定都會產生新的公開狀態版本
+	change := func(i int) {
+		turn := 10 + i%2
+		host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn})
+	}
+	change(0)
+	change(1)
+	if n := len(host.OfType("state_patch")); n > 0 {
+		t.Fatalf("尚未確認任何版本前不應收到差異，卻收到 %d 則", n)
+	}
+
+	last, ok := host.Last("public_state")
+	if !ok {
+		t.Fatal("應收到完整的房間公開狀態")
+	}
+	acked := last.Version
+	host.Send("state_ack", server.StateAckPayload{Target: "public_state", Version: acked})
+
+	// 不再確認：之後的差異都以同一基準計算，直到該版本被擠出保留的歷史
+	const history = 32
+	for i := 0; i < history; i++ {
+		host.Clear()
+		change(i)
+		msgs := host.Messages()
+		if len(msgs) == 0 {
+			t.Fatalf("第 %d 次變更應送出新的狀態", i+1)
+		}
+		msg := msgs[len(msgs)-1]
+		if msg.Version != acked+int64(i)+1 {
+			t.Fatalf("第 %d 次變更的版本應為 %d，實為 %s %d", i+1, acked+int64(i)+1, msg.Type, msg.Version)
+		}
+		if i < history-1 {
+			if msg.Type != "state_patch" {
+				t.Fatalf("確認的版本仍在歷史中時應送差異，版本 %d 卻收到 %s", msg.Version, msg.Type)
+			}
+			if base := Payload[server.StatePatchPayload](t, msg).Base; base != acked {
+				t.Fatalf("差異應以確認的版本 %d 為基準，實為 %d", acked, base)
+			}
+		} else if msg.Type != "public_state" {
+			t.Fatalf("確認的版本被擠出歷史後應退回完整狀態，卻收到 %s", msg.Type)
+		}
+	}
+	if n := host.PatchFailures(); n > 0 {
+		t.Fatalf("有 %d 則差異無法套用", n)
+	}
+	state, _ := host.RoomState()
+	if state.Settings.TurnTimeout != 11 {
+		t.Fatalf("套用差異與完整狀態後應為最新設定，回合時限為 %d", state.Settings.TurnTimeout)
+	}
+}
+
+// 廣播共用同一個序號，各連線的 prev 接續自己上一則房間訊息；漏收時下一則的 prev 對不上，
+// 送出 resync 即取回完整狀態，之後的訊息從 resync 的序號接續
+func TestResyncAfterSequenceGap(t *testing.T) {
+	s := New(t)
+	host := s.ConnectWithOptions("alice", Options{Protocol: 2})
+	host.Send("room_create", server.CreateRoomPayload{Name: "序號房"})
+	state, _ := host.RoomState()
+	bob := s.ConnectWithOptions("bob", Options{Protocol: 2})
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	watcher := s.ConnectWithOptions("carol", Options{Protocol: 2})
+	watcher.Send("room_spectate", server.SpectatePayload{RoomID: state.RoomID})
+	seed := int64(11)
+	host.Send("room_settings", server.RoomSettingsPayload{Seed: &seed})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	s.Advance(30 * time.Second)
+
+	hostSeqs := make(map[int64]string)
+	for _, msg := range host.Messages() {
+		if msg.Seq != 0 {
+			hostSeqs[msg.Seq] = msg.Type
+		}
+	}
+	shared := 0
+	for _, msg := range bob.Messages() {
+		if kind, ok := hostSeqs[msg.Seq]; ok && kind == msg.Type {
+			shared++
+		}
+	}
+	if shared == 0 {
+		t.Fatal("同一事件廣播給所有人時應共用序號")
+	}
+	assertSequenceChain(t, host)
+	assertSequenceChain(t, bob)
+
+	// 模擬 bob 漏收倒數第二則房間訊息：最後一則的 prev 與 bob 最後收到的序號不符
+	var sequenced []Message
+	for _, msg := range bob.Messages() {
+		if msg.Seq != 0 {
+			sequenced = append(sequenced, msg)
+		}
+	}
+	if len(sequenced) < 3 {
+		t.Fatalf("bob 應收到多則房間訊息，實際為 %d 則", len(sequenced))
+	}
+	seen, next := sequenced[len(sequenced)-3], sequenced[len(sequenced)-1]
+	if next.Prev == seen.Seq {
+		t.Fatal("漏收一則後，下一則的 prev 應與最後收到的序號不符")
+	}
+
+	bob.Send("resync", nil)
+	msg, ok := bob.Last("resync")
+	if !ok {
+		t.Fatalf("送出 resync 後應收到完整狀態，錯誤：%v", bob.Errors())
+	}
+	if msg.Prev != next.Seq || msg.Seq <= msg.Prev {
+		t.Fatalf("resync 的 prev 應為 %d 且序號遞增，實際為 seq %d prev %d", next.Seq, msg.Seq, msg.Prev)
+	}
+	type resync struct {
+		Public  json.RawMessage `json:"public"`
+		Private *struct {
+			Snapshot struct {
+				Hand []json.RawMessage `json:"hand"`
+			} `json:"snapshot"`
+		} `json:"private"`
+	}
+	payload := Payload[resync](t, msg)
+	var public server.PublicRoomStatePayload
+	if err := json.Unmarshal(payload.Public, &public); err != nil {
+		t.Fatalf("解析 resync 公開狀態失敗：%v", err)
+	}
+	if current, _ := host.RoomState(); !reflect.DeepEqual(public, current) {
+		t.Fatalf("resync 的公開狀態應與房間目前狀態一致：\n%+v\n%+v", public, current)
+	}
+	if payload.Private == nil || len(payload.Private.Snapshot.Hand) == 0 {
+		t.Fatal("入座者的 resync 應附上私人狀態")
+	}
+
+	watcher.Send("resync", nil)
+	if msg, ok := watcher.Last("resync"); !ok || Payload[resync](t, msg).Private != nil {
+		t.Fatal("觀戰者的 resync 只含公開狀態")
+	}
+
+	s.Advance(30 * time.Second)
+	assertSequenceChain(t, bob)
+	assertSequenceChain(t, watcher)
+}
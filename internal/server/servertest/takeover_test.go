@@ -0,0 +1,128 @@
+package servertest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"zombierush/internal/i18n"
+	"zombierush/internal/server"
+)
+
+// 對局中申請接手機器人座位需房主同意；拒絕時申請者留在大廳，同意後座位改由申請者操作
+func TestTakeOverBotSeat(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "接手房"})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+	seat := 5
+
+	carol := s.Connect("carol")
+	carol.Send("seat_claim", server.SeatClaimPayload{RoomID: state.RoomID, Seat: &seat})
+	msg, ok := host.Last("seat_claim_request")
+	if !ok {
+		t.Fatal("房主應收到接手申請")
+	}
+	if req := Payload[server.SeatClaimRequestPayload](t, msg); req.Seat != seat || req.Name != "carol" {
+		t.Fatalf("申請內容應為 carol 接手 %d 號座位，實為 %+v", seat, req)
+	}
+	host.Send("seat_claim_response", server.SeatResponsePayload{Seat: &seat, Accept: false})
+	if _, ok := carol.Last("private_info"); !ok {
+		t.Fatal("被拒絕的申請者應收到通知")
+	}
+	if _, ok := carol.Last("welcome"); ok {
+		t.Fatal("被拒絕的申請者不應入座")
+	}
+
+	carol.Send("seat_claim", server.SeatClaimPayload{RoomID: state.RoomID, Seat: &seat})
+	bob.Send("seat_claim_response", server.SeatResponsePayload{Seat: &seat, Accept: true})
+	if errs := bob.Errors(); !slices.Contains(errs, "host_only") {
+		t.Fatalf("只有房主能審核申請，錯誤為 %v", errs)
+	}
+	host.Send("seat_claim_response", server.SeatResponsePayload{Seat: &seat, Accept: true})
+	if got := welcomeSeat(t, carol); got != seat {
+		t.Fatalf("同意後申請者應坐到 %d 號座位，實為 %d", seat, got)
+	}
+	state, _ = host.RoomState()
+	if taken := state.Seats[seat]; taken.IsBot || taken.Name != "carol" {
+		t.Fatalf("座位應改由 carol 操作，實為 %+v", taken)
+	}
+	if _, ok := carol.Last("private_state"); !ok {
+		t.Fatal("接手者應收到座位的私人狀態")
+	}
+
+	dave := s.Connect("dave")
+	dave.Send("seat_claim", server.SeatClaimPayload{RoomID: state.RoomID, Seat: &seat})
+	if errs := dave.Errors(); !slices.Contains(errs, "seat_not_bot") {
+		t.Fatalf("已由真人操作的座位不能再申請接手，錯誤為 %v", errs)
+	}
+}
+
+// awaitTurnOf 推進時鐘直到輪到 name 出牌，回傳依回合時限 limit 算出的期限
+func awaitTurnOf(t *testing.T, s *Server, observer *Client, name string, limit time.Duration) time.Time {
+	t.Helper()
+	turn := func() bool {
+		state, ok := observer.RoomState()
+		return ok && state.Status == server.RoomStatusRunning && state.PublicGame.PendingType == "" &&
+			state.PublicGame.CurrentTurn == seatNamed(t, state, name).Index
+	}
+	if !s.RunUntil(turn, time.Hour) {
+		t.Fatalf("應輪到 %s 出牌", name)
+	}
+	return s.Clock.Now().Add(limit)
+}
+
+// assertDeadlineHolds 確認 name 的回合在原期限 due 才逾時，沒有因回到座位而延長
+func assertDeadlineHolds(t *testing.T, s *Server, observer *Client, name string, due time.Time) {
+	t.Helper()
+	timeout := i18n.M("log.turn_timeout", "player", name).String()
+	timeouts := func() int {
+		n := 0
+		for _, msg := range observer.OfType("log") {
+			if Payload[server.LogPayload](t, msg).Message == timeout {
+				n++
+			}
+		}
+		return n
+	}
+	before := timeouts()
+	s.Advance(due.Sub(s.Clock.Now()) - time.Millisecond)
+	if timeouts() != before {
+		t.Fatal("不應在原期限前逾時")
+	}
+	s.Advance(time.Millisecond)
+	if timeouts() == before {
+		t.Fatal("回到座位後應沿用原期限，期限到時即逾時")
+	}
+}
+
+// 輪到自己時斷線又重連，只對該座位重送回合提示，回合期限不重新起算
+func TestReconnectKeepsTurnDeadline(t *testing.T) {
+	s := New(t)
+	host := s.Connect("alice")
+	host.Send("room_create", server.CreateRoomPayload{Name: "重連房"})
+	turn, defense, seed := 10, 5, int64(7)
+	host.Send("room_settings", server.RoomSettingsPayload{TurnTimeout: &turn, DefenseTimeout: &defense, Seed: &seed})
+	state, _ := host.RoomState()
+	bob := s.Connect("bob")
+	bob.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	host.Send("start_game", server.StartGamePayload{Force: true})
+
+	due := awaitTurnOf(t, s, host, "bob", time.Duration(turn)*time.Second)
+	s.Advance(4 * time.Second)
+	bob.Disconnect()
+	s.Settle()
+	host.Clear()
+	fresh := s.Reconnect(bob)
+	fresh.Send("room_join", server.JoinRoomPayload{RoomID: state.RoomID})
+	if _, ok := fresh.Last("turn_start"); !ok {
+		t.Fatal("重連後應收到回合提示")
+	}
+	if _, ok := host.Last("turn_start"); ok {
+		t.Fatal("回合提示只應送給重連的座位")
+	}
+	assertDeadlineHolds(t, s, host, "bob", due)
+}
@@ -0,0 +1,160 @@
+package server
+
+import "zombierush/internal/game"
+
+const (
+	BotDifficultyEasy   = "easy"
+	BotDifficultyNormal = "normal"
+	BotDifficultyHard   = "hard"
+
+	minTurnTimeout    = 10
+	maxTurnTimeout    = 600
+	minDefenseTimeout = 5
+	maxDefenseTimeout = 300
+
+	defaultMoveHours = 24
+	maxMoveHours     = 168
+)
+
+// RoomSettings 描述房主可在待機狀態調整的房間規則
+type RoomSettings struct {
+	Variant         game.Variant
+	TurnTimeout     int // 秒，0 表示不限時
+	DefenseTimeout  int // 秒，0 表示不限時
+	BotDifficulty   string
+	AllowSpectators bool
+	Visibility      string
+	Rated           bool
+	AFKLimit        int   // 連續逾時幾次後改由 AI 代打，0 表示停用
+	Seed            int64 // 0 表示每局隨機
+	Correspondence  bool  // 通信對局：每步行動後保存，離線玩家保留座位
+	MoveHours       int   // 通信對局的回合與防守時限（小時）
+}
+
+func defaultRoomSettings() RoomSettings {
+	return RoomSettings{
+		Variant:         game.VariantStandard,
+		BotDifficulty:   BotDifficultyNormal,
+		AllowSpectators: true,
+		Visibility:      RoomVisibilityPublic,
+		AFKLimit:        defaultAFKLimit,
+		MoveHours:       defaultMoveHours,
+	}
+}
+
+// view 轉為對外公開的設定；種子本身不公開以免玩家推算發牌
+func (s RoomSettings) view() RoomSettingsView {
+	rules, _ := game.RulesFor(s.Variant)
+	return RoomSettingsView{
+		Variant:         string(rules.Variant),
+		MaxRounds:       rules.MaxRounds,
+		TurnTimeout:     s.TurnTimeout,
+		DefenseTimeout:  s.DefenseTimeout,
+		BotDifficulty:   s.BotDifficulty,
+		AllowSpectators: s.AllowSpectators,
+		Visibility:      s.Visibility,
+		Rated:           s.Rated,
+		AFKLimit:        s.AFKLimit,
+		Seeded:          s.Seed != 0,
+		Correspondence:  s.Correspondence,
+		MoveHours:       s.MoveHours,
+	}
+}
+
+// merge 套用部分更新並驗證結果
+func (s RoomSettings) merge(p RoomSettingsPayload) (RoomSettings, error) {
+	next := s
+	if p.Variant != nil {
+		rules, err := game.RulesFor(game.Variant(*p.Variant))
+		if err != nil {
+			return s, err
+		}
+		next.Variant = rules.Variant
+	}
+	if p.TurnTimeout != nil {
+		if !validTimeout(*p.TurnTimeout, minTurnTimeout, maxTurnTimeout) {
+			return s, reject("turn_timeout_range", "min", minTurnTimeout, "max", maxTurnTimeout)
+		}
+		next.TurnTimeout = *p.TurnTimeout
+	}
+	if p.DefenseTimeout != nil {
+		if !validTimeout(*p.DefenseTimeout, minDefenseTimeout, maxDefenseTimeout) {
+			return s, reject("defense_timeout_range", "min", minDefenseTimeout, "max", maxDefenseTimeout)
+		}
+		next.DefenseTimeout = *p.DefenseTimeout
+	}
+	if p.BotDifficulty != nil {
+		switch *p.BotDifficulty {
+		case BotDifficultyEasy, BotDifficultyNormal, BotDifficultyHard:
+			next.BotDifficulty = *p.BotDifficulty
+		default:
+			return s, reject("unknown_bot_difficulty", "difficulty", *p.BotDifficulty)
+		}
+	}
+	if p.AllowSpectators != nil {
+		next.AllowSpectators = *p.AllowSpectators
+	}
+	if p.Visibility != nil {
+		switch *p.Visibility {
+		case RoomVisibilityPublic, RoomVisibilityPrivate:
+			next.Visibility = *p.Visibility
+		default:
+			return s, reject("unknown_visibility", "visibility", *p.Visibility)
+		}
+	}
+	if p.Rated != nil {
+		next.Rated = *p.Rated
+	}
+	if p.AFKLimit != nil {
+		if *p.AFKLimit < 0 || *p.AFKLimit > maxAFKLimit {
+			return s, reject("afk_limit_range", "max", maxAFKLimit)
+		}
+		next.AFKLimit = *p.AFKLimit
+	}
+	if p.Seed != nil {
+		next.Seed = *p.Seed
+	}
+	if p.Correspondence != nil {
+		next.Correspondence = *p.Correspondence
+	}
+	if p.MoveHours != nil {
+		if *p.MoveHours < 1 || *p.MoveHours > maxMoveHours {
+			return s, reject("move_hours_range", "max", maxMoveHours)
+		}
+		next.MoveHours = *p.MoveHours
+	}
+	return next, nil
+}
+
+// validTimeout 檢查時限為 0（不限時）或介於上下限之間
+func validTimeout(seconds, min, max int) bool {
+	return seconds == 0 || (seconds >= min && seconds <= max)
+}
+
+// applySettings 由房主在待機狀態更新設定，關閉觀戰時回傳被請離的觀戰者
+func (r *Room) applySettings(host *Client, p RoomSettingsPayload) ([]*Client, error) {
+	return attemptWith(r, func() ([]*Client, error) {
+		if err := r.ensureHostLocked(host, "error.host_only.settings"); err != nil {
+			return nil, err
+		}
+		if r.status != RoomStatusLobby {
+			return nil, reject("settings_lobby_only")
+		}
+		next, err := r.settings.merge(p)
+		if err != nil {
+			return nil, err
+		}
+		if next.Correspondence && r.archive == nil {
+			return nil, reject("correspondence_unavailable")
+		}
+		r.settings = next
+
+		var evicted []*Client
+		if !next.AllowSpectators {
+			evicted = r.evictSpectatorsLocked()
+		}
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return evicted, nil
+	})
+}
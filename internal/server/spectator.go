@@ -0,0 +1,48 @@
+package server
+
+// addSpectator 讓玩家以觀戰者身份進入房間，只接收公開資訊
+func (r *Room) addSpectator(c *Client, opts JoinOptions) error {
+	return r.attempt(func() error {
+		if r.isBannedLocked(c) {
+			return errBanned
+		}
+		if !r.settings.AllowSpectators {
+			return reject("spectators_disabled")
+		}
+		if err := r.checkAccessLocked(opts); err != nil {
+			return err
+		}
+		if !c.reserveRoom(r) {
+			return errAlreadyInRoom
+		}
+
+		r.spectators[c] = struct{}{}
+		c.setRoom(r, -1)
+		r.sendWelcomeLocked(c)
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		return nil
+	})
+}
+
+func (r *Room) isSpectatorLocked(c *Client) bool {
+	_, ok := r.spectators[c]
+	return ok
+}
+
+// evictSpectatorsLocked 清空觀戰者並回傳，由呼叫端送回大廳
+func (r *Room) evictSpectatorsLocked() []*Client {
+	evicted := make([]*Client, 0, len(r.spectators))
+	for c := range r.spectators {
+		c.setRoom(nil, -1)
+		evicted = append(evicted, c)
+	}
+	r.spectators = make(map[*Client]struct{})
+	return evicted
+}
+
+func (r *Room) evictSpectators() []*Client {
+	return query(r, func() []*Client {
+		return r.evictSpectatorsLocked()
+	})
+}
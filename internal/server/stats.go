@@ -0,0 +1,74 @@
+package server
+
+// Stats 為 Hub 的負載概況，供 /debug/stats 與壓力測試參考
+type Stats struct {
+	Connections  int `json:"connections"`
+	LobbyClients int `json:"lobbyClients"`
+	Rooms        int `json:"rooms"`
+	RunningGames int `json:"runningGames"`
+	// QueuedMessages 與 LongestQueue 為各連線送出佇列中尚未寫出的訊息總數與最大值
+	QueuedMessages int `json:"queuedMessages"`
+	LongestQueue   int `json:"longestQueue"`
+	// CoalescedMessages 為被較新狀態取代而未送出的訊息累計數
+	CoalescedMessages int64 `json:"coalescedMessages"`
+	// OverflowEvents 為送出佇列超過軟上限的累計次數；持續壅塞才會關閉連線
+	OverflowEvents int64 `json:"overflowEvents"`
+	// DroppedClients 為送出佇列持續壅塞而被伺服器關閉的連線累計數
+	DroppedClients int64 `json:"droppedClients"`
+	// RequestLogAccounts 為仍保存請求 ID 紀錄的帳號數，過期紀錄清除後應隨之下降
+	RequestLogAccounts int `json:"requestLogAccounts"`
+}
+
+// Stats 回傳目前的連線、房間與對局數量；對局狀態取自房間發布的摘要，不需等候房間迴圈
+func (h *Hub) Stats() Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stats := Stats{
+		Connections:       len(h.accounts),
+		LobbyClients:      len(h.lobbyClients),
+		Rooms:             len(h.rooms),
+		CoalescedMessages: h.coalesced.Load(),
+		OverflowEvents:    h.overflows.Load(),
+		DroppedClients:    h.dropped.Load(),
+
+		RequestLogAccounts: h.requests.users(),
+	}
+	for client := range h.clientsLocked() {
+		queued := client.outbox.len()
+		stats.QueuedMessages += queued
+		stats.LongestQueue = max(stats.LongestQueue, queued)
+	}
+	for _, room := range h.rooms {
+		if room.published().summary.Status == RoomStatusRunning {
+			stats.RunningGames++
+		}
+	}
+	return stats
+}
+
+// clientsLocked 回傳大廳與各帳號目前的連線
+func (h *Hub) clientsLocked() map[*Client]struct{} {
+	clients := make(map[*Client]struct{}, len(h.accounts)+len(h.lobbyClients))
+	for client := range h.lobbyClients {
+		clients[client] = struct{}{}
+	}
+	for _, client := range h.accounts {
+		clients[client] = struct{}{}
+	}
+	return clients
+}
+
+// recordDrop 記錄一條因送出佇列持續壅塞而關閉的連線
+func (h *Hub) recordDrop() {
+	h.dropped.Add(1)
+}
+
+// recordCoalesced 記錄一則被較新狀態取代的訊息
+func (h *Hub) recordCoalesced() {
+	h.coalesced.Add(1)
+}
+
+// recordOverflow 記錄一次送出佇列超過軟上限
+func (h *Hub) recordOverflow() {
+	h.overflows.Add(1)
+}
@@ -0,0 +1,38 @@
+package store
+
+import "zombierush/internal/i18n"
+
+// Error 為帶有穩定代碼的儲存層錯誤：Code 供程式判斷（例如對應 HTTP 狀態），Msg 為訊息目錄中的顯示文字
+type Error struct {
+	Code string
+	Msg  i18n.Message
+}
+
+func newError(code string) *Error {
+	return &Error{Code: code, Msg: i18n.M("error." + code)}
+}
+
+func (e *Error) Error() string { return e.Msg.String() }
+
+// Localize 依語系呈現錯誤訊息
+func (e *Error) Localize(loc i18n.Locale) string { return e.Msg.Localize(loc) }
+
+// ErrorCode 回傳穩定的錯誤代碼
+func (e *Error) ErrorCode() string { return e.Code }
+
+var (
+	// ErrUsernameRequired 表示未提供帳號
+	ErrUsernameRequired = newError("username_required")
+	// ErrPasswordTooShort 表示密碼長度不足
+	ErrPasswordTooShort = newError("password_too_short")
+	// ErrUserExists 表示帳號已被註冊
+	ErrUserExists = newError("user_exists")
+	// ErrBadCredentials 表示帳號或密碼錯誤
+	ErrBadCredentials = newError("bad_credentials")
+	// ErrSessionRequired 表示未提供會話 token
+	ErrSessionRequired = newError("session_required")
+	// ErrSessionExpired 表示會話不存在或已過期
+	ErrSessionExpired = newError("session_expired")
+	// ErrGameNotFound 表示找不到保存的對局
+	ErrGameNotFound = newError("game_not_found")
+)
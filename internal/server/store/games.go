@@ -0,0 +1,140 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SavedGame 為封存中的對局；State 為伺服器序列化的房間與遊戲狀態，
+// Deadline 為通信對局下次需要由系統代為行動的時間，零值表示不需喚醒
+type SavedGame struct {
+	RoomID       string
+	Name         string
+	State        []byte
+	Participants []int64
+	SavedAt      time.Time
+	Deadline     time.Time
+}
+
+// SaveGame 寫入或覆蓋指定房間的封存對局
+func (s *Store) SaveGame(game SavedGame) error {
+	if game.RoomID == "" {
+		return fmt.Errorf("缺少房間 ID")
+	}
+	if game.SavedAt.IsZero() {
+		game.SavedAt = s.clock.Now().UTC()
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("開始交易失敗: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var deadline sql.NullInt64
+	if !game.Deadline.IsZero() {
+		deadline = sql.NullInt64{Int64: game.Deadline.UnixMilli(), Valid: true}
+	}
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO saved_games(room_id, name, state, saved_at, deadline) VALUES(?, ?, ?, ?, ?)`, game.RoomID, game.Name, game.State, game.SavedAt, deadline); err != nil {
+		return fmt.Errorf("保存對局失敗: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM saved_game_players WHERE room_id = ?`, game.RoomID); err != nil {
+		return fmt.Errorf("保存對局參與者失敗: %w", err)
+	}
+	for _, userID := range game.Participants {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO saved_game_players(room_id, user_id) VALUES(?, ?)`, game.RoomID, userID); err != nil {
+			return fmt.Errorf("保存對局參與者失敗: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交交易失敗: %w", err)
+	}
+	return nil
+}
+
+// LoadGame 讀取封存對局與其參與者
+func (s *Store) LoadGame(roomID string) (*SavedGame, error) {
+	game := &SavedGame{RoomID: roomID}
+	var deadline sql.NullInt64
+	row := s.db.QueryRow(`SELECT name, state, saved_at, deadline FROM saved_games WHERE room_id = ?`, roomID)
+	if err := row.Scan(&game.Name, &game.State, &game.SavedAt, &deadline); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGameNotFound
+		}
+		return nil, fmt.Errorf("讀取保存對局失敗: %w", err)
+	}
+	if deadline.Valid {
+		game.Deadline = time.UnixMilli(deadline.Int64)
+	}
+
+	rows, err := s.db.Query(`SELECT user_id FROM saved_game_players WHERE room_id = ?`, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("讀取對局參與者失敗: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("讀取對局參與者失敗: %w", err)
+		}
+		game.Participants = append(game.Participants, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("讀取對局參與者失敗: %w", err)
+	}
+	return game, nil
+}
+
+// ListGames 列出指定使用者參與過的封存對局，依保存時間由新到舊
+func (s *Store) ListGames(userID int64) ([]SavedGame, error) {
+	rows, err := s.db.Query(`SELECT g.room_id, g.name, g.state, g.saved_at FROM saved_games g JOIN saved_game_players p ON p.room_id = g.room_id WHERE p.user_id = ? ORDER BY g.saved_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查詢保存對局失敗: %w", err)
+	}
+	defer rows.Close()
+
+	games := make([]SavedGame, 0)
+	for rows.Next() {
+		var game SavedGame
+		if err := rows.Scan(&game.RoomID, &game.Name, &game.State, &game.SavedAt); err != nil {
+			return nil, fmt.Errorf("查詢保存對局失敗: %w", err)
+		}
+		games = append(games, game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查詢保存對局失敗: %w", err)
+	}
+	return games, nil
+}
+
+// DueGames 列出期限不晚於 before 的對局房間 ID，依期限由早到晚
+func (s *Store) DueGames(before time.Time) ([]string, error) {
+	rows, err := s.db.Query(`SELECT room_id FROM saved_games WHERE deadline IS NOT NULL AND deadline <= ? ORDER BY deadline`, before.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("查詢到期對局失敗: %w", err)
+	}
+	defer rows.Close()
+
+	roomIDs := make([]string, 0)
+	for rows.Next() {
+		var roomID string
+		if err := rows.Scan(&roomID); err != nil {
+			return nil, fmt.Errorf("查詢到期對局失敗: %w", err)
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查詢到期對局失敗: %w", err)
+	}
+	return roomIDs, nil
+}
+
+// DeleteGame 移除封存對局（重新開啟後即不再保留）
+func (s *Store) DeleteGame(roomID string) error {
+	if _, err := s.db.Exec(`DELETE FROM saved_games WHERE room_id = ?`, roomID); err != nil {
+		return fmt.Errorf("刪除保存對局失敗: %w", err)
+	}
+	return nil
+}
@@ -13,10 +13,13 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"golang.org/x/crypto/bcrypt"
+
+	"zombierush/internal/clock"
 )
 
 type Store struct {
-	db *sql.DB
+	db    *sql.DB
+	clock clock.Clock
 }
 
 type User struct {
@@ -33,12 +36,25 @@ func New(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("建立資料目錄失敗: %w", err)
 	}
 
-	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	return open(dbPath+"?_foreign_keys=on", 0)
+}
+
+// NewMemory 建立只存在於記憶體的資料庫，關閉後內容即消失，供測試使用
+func NewMemory() (*Store, error) {
+	// 每條 SQLite 連線各自擁有一份記憶體資料庫，因此限制為單一連線
+	return open(":memory:?_foreign_keys=on", 1)
+}
+
+func open(dsn string, maxConns int) (*Store, error) {
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("開啟資料庫失敗: %w", err)
 	}
+	if maxConns > 0 {
+		db.SetMaxOpenConns(maxConns)
+	}
 
-	store := &Store{db: db}
+	store := &Store{db: db, clock: clock.Real()}
 	if err := store.initSchema(); err != nil {
 		_ = db.Close()
 		return nil, err
@@ -46,6 +62,13 @@ func New(dbPath string) (*Store, error) {
 	return store, nil
 }
 
+// SetClock 更換判斷會話期限與記錄建立時間所用的時鐘，應在開始服務前設定
+func (s *Store) SetClock(clk clock.Clock) {
+	if clk != nil {
+		s.clock = clk
+	}
+}
+
 func (s *Store) Close() error {
 	if s == nil || s.db == nil {
 		return nil
@@ -70,6 +93,22 @@ CREATE TABLE IF NOT EXISTS sessions (
 );
 CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
 CREATE INDEX IF NOT EXISTS idx_sessions_expiry ON sessions(expires_at);
+CREATE TABLE IF NOT EXISTS saved_games (
+  room_id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  state BLOB NOT NULL,
+  saved_at DATETIME NOT NULL,
+  deadline INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_saved_games_deadline ON saved_games(deadline);
+CREATE TABLE IF NOT EXISTS saved_game_players (
+  room_id TEXT NOT NULL,
+  user_id INTEGER NOT NULL,
+  PRIMARY KEY(room_id, user_id),
+  FOREIGN KEY(room_id) REFERENCES saved_games(room_id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_saved_game_players_user ON saved_game_players(user_id);
 `
 	if _, err := s.db.Exec(schema); err != nil {
 		return fmt.Errorf("初始化資料表失敗: %w", err)
@@ -80,10 +119,10 @@ CREATE INDEX IF NOT EXISTS idx_sessions_expiry ON sessions(expires_at);
 func (s *Store) CreateUser(username, password string) (*User, error) {
 	username = strings.TrimSpace(username)
 	if username == "" {
-		return nil, fmt.Errorf("帳號不可為空")
+		return nil, ErrUsernameRequired
 	}
 	if len(password) < 6 {
-		return nil, fmt.Errorf("密碼長度至少 6 碼")
+		return nil, ErrPasswordTooShort
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -91,10 +130,11 @@ func (s *Store) CreateUser(username, password string) (*User, error) {
 		return nil, fmt.Errorf("加密密碼失敗: %w", err)
 	}
 
-	res, err := s.db.Exec(`INSERT INTO users(username, password_hash) VALUES(?, ?)`, username, string(hash))
+	created := s.clock.Now().UTC()
+	res, err := s.db.Exec(`INSERT INTO users(username, password_hash, created_at) VALUES(?, ?, ?)`, username, string(hash), created)
 	if err != nil {
 		if strings.Contains(err.Error(), "UNIQUE") {
-			return nil, fmt.Errorf("帳號已存在")
+			return nil, ErrUserExists
 		}
 		return nil, fmt.Errorf("建立使用者失敗: %w", err)
 	}
@@ -104,14 +144,14 @@ func (s *Store) CreateUser(username, password string) (*User, error) {
 		return nil, fmt.Errorf("取得使用者 ID 失敗: %w", err)
 	}
 
-	user := &User{ID: id, Username: username, Created: time.Now()}
+	user := &User{ID: id, Username: username, Created: created}
 	return user, nil
 }
 
 func (s *Store) Authenticate(username, password string) (*User, error) {
 	username = strings.TrimSpace(username)
 	if username == "" {
-		return nil, fmt.Errorf("帳號不可為空")
+		return nil, ErrUsernameRequired
 	}
 
 	row := s.db.QueryRow(`SELECT id, password_hash, created_at FROM users WHERE username = ?`, username)
@@ -122,13 +162,13 @@ func (s *Store) Authenticate(username, password string) (*User, error) {
 	)
 	if err := row.Scan(&id, &hash, &created); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("帳號或密碼錯誤")
+			return nil, ErrBadCredentials
 		}
 		return nil, fmt.Errorf("查詢使用者失敗: %w", err)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
-		return nil, fmt.Errorf("帳號或密碼錯誤")
+		return nil, ErrBadCredentials
 	}
 
 	return &User{ID: id, Username: username, Created: created}, nil
@@ -144,7 +184,7 @@ func (s *Store) CreateSession(userID int64, ttl time.Duration) (string, error) {
 		return "", err
 	}
 
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
 	exp := now.Add(ttl)
 	if _, err := s.db.Exec(`INSERT INTO sessions(token, user_id, created_at, expires_at) VALUES(?, ?, ?, ?)`, token, userID, now, exp); err != nil {
 		return "", fmt.Errorf("建立會話失敗: %w", err)
@@ -156,10 +196,10 @@ func (s *Store) CreateSession(userID int64, ttl time.Duration) (string, error) {
 func (s *Store) GetUserBySession(token string) (*User, error) {
 	token = strings.TrimSpace(token)
 	if token == "" {
-		return nil, fmt.Errorf("缺少會話 token")
+		return nil, ErrSessionRequired
 	}
 
-	row := s.db.QueryRow(`SELECT u.id, u.username, u.created_at FROM sessions s JOIN users u ON u.id = s.user_id WHERE s.token = ? AND s.expires_at > ?`, token, time.Now().UTC())
+	row := s.db.QueryRow(`SELECT u.id, u.username, u.created_at FROM sessions s JOIN users u ON u.id = s.user_id WHERE s.token = ? AND s.expires_at > ?`, token, s.clock.Now().UTC())
 	var (
 		id       int64
 		username string
@@ -167,7 +207,7 @@ func (s *Store) GetUserBySession(token string) (*User, error) {
 	)
 	if err := row.Scan(&id, &username, &created); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("會話無效或已過期")
+			return nil, ErrSessionExpired
 		}
 		return nil, fmt.Errorf("查詢會話失敗: %w", err)
 	}
@@ -176,7 +216,7 @@ func (s *Store) GetUserBySession(token string) (*User, error) {
 }
 
 func (s *Store) CleanupExpiredSessions() error {
-	_, err := s.db.Exec(`DELETE FROM sessions WHERE expires_at <= ?`, time.Now().UTC())
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE expires_at <= ?`, s.clock.Now().UTC())
 	if err != nil {
 		return fmt.Errorf("清理過期會話失敗: %w", err)
 	}
@@ -0,0 +1,155 @@
+package server
+
+import "zombierush/internal/i18n"
+
+// RequestSeatClaim 申請接手進行中對局的機器人座位，需經房主同意
+func (h *Hub) RequestSeatClaim(roomID string, client *Client, seatIdx int, opts JoinOptions) error {
+	h.mu.Lock()
+	room, ok := h.rooms[roomID]
+	if !ok && roomID == "" {
+		room, ok = h.roomByInviteLocked(opts.InviteCode)
+	}
+	h.mu.Unlock()
+	if !ok {
+		return errRoomNotFound
+	}
+	return room.requestClaim(client, seatIdx, opts)
+}
+
+// requestClaim 暫存接手申請並通知房主；觀戰者已通過存取檢查，其餘玩家需提供憑證
+func (r *Room) requestClaim(c *Client, seatIdx int, opts JoinOptions) error {
+	return r.attempt(func() error {
+		if c.currentRoom() != nil && c.currentRoom() != r {
+			return errAlreadyInRoom
+		}
+		if c.currentRoom() == r && !r.isSpectatorLocked(c) {
+			return reject("already_seated_here")
+		}
+		if r.isBannedLocked(c) {
+			return errBanned
+		}
+		if r.status != RoomStatusRunning {
+			return reject("takeover_not_running")
+		}
+		if !r.isSpectatorLocked(c) {
+			if err := r.checkAccessLocked(opts); err != nil {
+				return err
+			}
+		}
+		if r.seatByUserLocked(c.userID) != nil {
+			return reject("takeover_own_seat")
+		}
+		seat := r.getSeatLocked(seatIdx)
+		if seat == nil {
+			return errInvalidSeat
+		}
+		if seat.Bot == nil || seat.Client != nil {
+			return reject("seat_not_bot", "seat", seatIdx)
+		}
+		if existing, ok := r.claimRequests[seatIdx]; ok && existing != c {
+			return reject("takeover_claimed")
+		}
+		host := r.getSeatLocked(r.hostSeat)
+		if host == nil || host.Client == nil {
+			return reject("takeover_no_host")
+		}
+
+		r.claimRequests[seatIdx] = c
+		r.sendLocked(host.Client, ServerMessage{Type: "seat_claim_request", Payload: SeatClaimRequestPayload{Seat: seatIdx, SeatName: seat.displayName(), Name: c.name}})
+		r.sendPrivateInfoLocked(c, i18n.M("info.takeover_requested"))
+		return nil
+	})
+}
+
+// resolveClaim 由房主審核接手申請，同意時回傳接手的玩家供呼叫端將其移出大廳
+func (r *Room) resolveClaim(host *Client, seatIdx int, accept bool) (*Client, error) {
+	return attemptWith(r, func() (*Client, error) {
+		if err := r.ensureHostLocked(host, "error.host_only.claims"); err != nil {
+			return nil, err
+		}
+		c, ok := r.claimRequests[seatIdx]
+		if !ok {
+			return nil, reject("takeover_expired")
+		}
+		delete(r.claimRequests, seatIdx)
+		if !accept {
+			r.sendPrivateInfoLocked(c, i18n.M("info.takeover_declined"))
+			return nil, nil
+		}
+		seat := r.getSeatLocked(seatIdx)
+		if r.status != RoomStatusRunning || seat == nil || seat.Bot == nil || seat.Client != nil {
+			r.sendPrivateInfoLocked(c, i18n.M("error.takeover_unavailable"))
+			return nil, reject("takeover_unavailable")
+		}
+		if r.seatByUserLocked(c.userID) != nil {
+			return nil, reject("claimant_seated")
+		}
+		if c.currentRoom() != r && !c.reserveRoom(r) {
+			return nil, reject("claimant_elsewhere")
+		}
+
+		delete(r.spectators, c)
+		seat.Bot = nil
+		seat.Client = c
+		seat.Ready = false
+		seat.UserID = c.userID
+		// 換發新 token，原持有者不再能以舊 token 取回座位
+		seat.Token = newSeatToken()
+		c.token = seat.Token
+		c.setRoom(r, seat.Index)
+
+		r.broadcastLogLocked(i18n.M("log.seat_taken_over", "player", c.name, "seat", seat.Index))
+		r.sendWelcomeLocked(c)
+		r.broadcastLobbyLocked()
+		r.broadcastPublicStateLocked()
+		r.resumeSeatLocked(seat)
+		return c, nil
+	})
+}
+
+// resumeSeatLocked 讓重新由真人操作的座位取得私人資訊；若正輪到該座位行動或防守則改由真人處理
+func (r *Room) resumeSeatLocked(seat *Seat) {
+	if r.game == nil {
+		return
+	}
+	r.sendPrivateStateLocked(seat.Index)
+	if r.status != RoomStatusRunning || r.paused {
+		return
+	}
+	if pending := r.pendingChallenge; pending != nil {
+		if pending.DefenderSeat == seat.Index {
+			r.sendPendingPromptLocked(seat)
+		}
+		return
+	}
+	if r.currentTurn != seat.Index {
+		return
+	}
+	// 重新連線、換分頁或取回控制都只對此座位重送提示並沿用本回合的期限，不會延長時限
+	r.sendLocked(seat.Client, ServerMessage{Type: "turn_start", Payload: TurnPromptPayload{PlayerID: seat.Index, Name: seat.displayName()}})
+	if r.turnTimer != nil {
+		return
+	}
+	due := r.turnDue
+	if due.IsZero() {
+		// 暫停後由 AI 接續的回合未記錄期限，此時才重新起算
+		due = r.clock.Now().Add(r.turnTimeoutLocked())
+	}
+	r.armTurnTimerUntilLocked(seat, due)
+	r.broadcastPublicStateLocked()
+}
+
+// dropClaimsByLocked 移除指定玩家尚未審核的接手申請
+func (r *Room) dropClaimsByLocked(c *Client) {
+	for seatIdx, claimant := range r.claimRequests {
+		if claimant == c {
+			delete(r.claimRequests, seatIdx)
+		}
+	}
+}
+
+func (r *Room) dropClaimsBy(c *Client) {
+	r.do(func() {
+		r.dropClaimsByLocked(c)
+	})
+}
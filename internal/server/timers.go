@@ -0,0 +1,167 @@
+package server
+
+import (
+	"time"
+
+	"zombierush/internal/game"
+	"zombierush/internal/i18n"
+)
+
+// turnTimeoutLocked 回傳回合時限；通信對局以小時計
+func (r *Room) turnTimeoutLocked() time.Duration {
+	if r.settings.Correspondence {
+		return time.Duration(r.settings.MoveHours) * time.Hour
+	}
+	return time.Duration(r.settings.TurnTimeout) * time.Second
+}
+
+func (r *Room) defenseTimeoutLocked() time.Duration {
+	if r.settings.Correspondence {
+		return time.Duration(r.settings.MoveHours) * time.Hour
+	}
+	return time.Duration(r.settings.DefenseTimeout) * time.Second
+}
+
+// armTurnTimerLocked 為真人回合啟動時限，逾時由系統代為出牌
+func (r *Room) armTurnTimerLocked(seat *Seat) {
+	r.armTurnTimerUntilLocked(seat, r.clock.Now().Add(r.turnTimeoutLocked()))
+}
+
+// armTurnTimerUntilLocked 以指定期限啟動回合時限，還原通信對局時沿用保存的期限
+func (r *Room) armTurnTimerUntilLocked(seat *Seat, deadline time.Time) {
+	r.stopTurnTimerLocked()
+	r.turnSerial++
+	if r.turnTimeoutLocked() <= 0 {
+		return
+	}
+	r.turnDue = deadline
+	if seat.Bot != nil || seat.Away {
+		return
+	}
+	serial := r.turnSerial
+	r.turnDeadline = deadline
+	r.turnTimer = r.clock.AfterFunc(deadline.Sub(r.clock.Now()), func() {
+		r.onTurnTimeout(serial)
+	})
+}
+
+func (r *Room) stopTurnTimerLocked() {
+	if r.turnTimer != nil {
+		r.turnTimer.Stop()
+		r.turnTimer = nil
+	}
+	r.turnDeadline = time.Time{}
+	r.turnDue = time.Time{}
+}
+
+func (r *Room) onTurnTimeout(serial int) {
+	r.do(func() {
+		if r.turnSerial != serial || r.status != RoomStatusRunning || r.paused || r.pendingChallenge != nil {
+			return
+		}
+		seat := r.getSeatLocked(r.currentTurn)
+		if seat == nil || seat.Bot != nil {
+			return
+		}
+		r.turnTimer = nil
+		r.turnDeadline = time.Time{}
+		r.broadcastLogLocked(i18n.M("log.turn_timeout", "player", seat.displayName()))
+		r.recordMissLocked(seat)
+		r.playBotTurnLocked(standInBot(seat))
+	})
+}
+
+// beginDefenseLocked 暫存挑戰並提示真人防守者出牌；attackerCards 為攻擊牌的牌編號
+func (r *Room) beginDefenseLocked(attacker, defender *Seat, attackerCards []int, suit game.Suit) {
+	r.stopTurnTimerLocked()
+	pending := &pendingChallenge{
+		AttackerSeat:    attacker.Index,
+		DefenderSeat:    defender.Index,
+		AttackerCardIDs: attackerCards,
+		AttackKind:      game.CardKindNumber,
+		AttackSuit:      suit,
+	}
+	r.armDefenseTimerLocked(pending)
+	r.pendingChallenge = pending
+
+	r.sendPendingPromptLocked(defender)
+	r.broadcastPublicStateLocked()
+	r.persistLocked()
+}
+
+// sendPendingPromptLocked 向防守者（含重新連線者）送出目前挑戰的防守提示
+func (r *Room) sendPendingPromptLocked(defender *Seat) {
+	pending := r.pendingChallenge
+	attacker := r.getSeatLocked(pending.AttackerSeat)
+	if attacker == nil || defender.Player == nil {
+		return
+	}
+	suit := pending.AttackSuit
+	r.sendDefensePromptLocked(
+		defender,
+		collectSuitOptions(defender.Player, suit, localeOf(defender.Client)),
+		cardViewsByID(attacker.Player, pending.AttackerCardIDs, localeOf(defender.Client)),
+		&suit,
+	)
+}
+
+func (r *Room) armDefenseTimerLocked(pending *pendingChallenge) {
+	r.armDefenseTimerUntilLocked(pending, r.clock.Now().Add(r.defenseTimeoutLocked()))
+}
+
+func (r *Room) armDefenseTimerUntilLocked(pending *pendingChallenge, deadline time.Time) {
+	if r.defenseTimeoutLocked() <= 0 {
+		return
+	}
+	pending.deadline = deadline
+	pending.timer = r.clock.AfterFunc(deadline.Sub(r.clock.Now()), func() {
+		r.onDefenseTimeout(pending)
+	})
+}
+
+func (r *Room) clearPendingChallengeLocked() {
+	if r.pendingChallenge != nil && r.pendingChallenge.timer != nil {
+		r.pendingChallenge.timer.Stop()
+	}
+	r.pendingChallenge = nil
+}
+
+func (r *Room) onDefenseTimeout(pending *pendingChallenge) {
+	r.do(func() {
+		if r.pendingChallenge != pending || r.status != RoomStatusRunning || r.paused {
+			return
+		}
+		seat := r.getSeatLocked(pending.DefenderSeat)
+		if seat != nil {
+			r.broadcastLogLocked(i18n.M("log.defense_timeout", "player", seat.displayName()))
+			r.recordMissLocked(seat)
+		}
+		r.autoDefendLocked()
+	})
+}
+
+// autoDefendLocked 以機器人策略替目前的防守者出牌
+func (r *Room) autoDefendLocked() {
+	pending := r.pendingChallenge
+	if pending == nil {
+		return
+	}
+	seat := r.getSeatLocked(pending.DefenderSeat)
+	if seat == nil || seat.Player == nil {
+		return
+	}
+	defense := r.selectBotDefenseLocked(seat.Player, pending.AttackSuit, len(pending.AttackerCardIDs))
+	_ = r.resolveChallengeLocked(pending.AttackerSeat, pending.DefenderSeat, pending.AttackerCardIDs, cardIDsAt(seat.Player, defense))
+}
+
+func (r *Room) stopTimersLocked() {
+	r.stopTurnTimerLocked()
+	r.clearPendingChallengeLocked()
+}
+
+func deadlineMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
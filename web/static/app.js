@@ -1,6 +1,12 @@
 const TOKEN_KEY = 'zombiehunt-token';
 const SESSION_KEY = 'zombiehunt-session';
 const DISPLAY_KEY = 'zombiehunt-display';
+// 伺服器依此語系呈現紀錄與錯誤訊息，與頁面語系一致
+const UI_LOCALE = document.documentElement.lang || 'zh-Hant';
+// 連線時宣告的協定版本；版本 2 起房間訊息附帶序號，漏收時可要求 resync，
+// 版本 3 起公開狀態與大廳列表改以差異送達
+const PROTOCOL_VERSION = 3;
+const MAX_STATE_VERSIONS = 64;
 
 const CARD_KIND = Object.freeze({
   NUMBER: 0,
@@ -51,8 +57,27 @@ const state = {
   reconnectTimer: null,
   authMode: 'login',
   postGameMessage: '',
+  inviteCode: '',
+  spectator: false,
+  pendingInvite: readInitialInvite(),
+  queueStatus: null,
+  savedGames: [],
+  inbox: [],
+  superseded: false,
+  pendingRequests: new Map(),
+  lastSeq: null,
+  resyncPending: false,
+  stateVersions: { public_state: new Map(), lobby_rooms: new Map() },
 };
 
+function readInitialInvite() {
+  try {
+    return new URL(window.location.href).searchParams.get('invite') || '';
+  } catch (err) {
+    return '';
+  }
+}
+
 const elements = {
   loginOverlay: document.getElementById('login-overlay'),
   authForm: document.getElementById('auth-form'),
@@ -74,6 +99,22 @@ const elements = {
 
   createRoomForm: document.getElementById('create-room-form'),
   createRoomName: document.getElementById('create-room-name'),
+  createRoomPassword: document.getElementById('create-room-password'),
+  createRoomPrivate: document.getElementById('create-room-private'),
+  btnRefreshSaved: document.getElementById('btn-refresh-saved'),
+  savedGameList: document.getElementById('saved-game-list'),
+  btnRefreshInbox: document.getElementById('btn-refresh-inbox'),
+  inboxList: document.getElementById('inbox-list'),
+  quickPlayForm: document.getElementById('quick-play-form'),
+  quickPlayVariant: document.getElementById('quick-play-variant'),
+  quickPlayRated: document.getElementById('quick-play-rated'),
+  quickPlayBots: document.getElementById('quick-play-bots'),
+  btnQuickPlay: document.getElementById('btn-quick-play'),
+  btnQueueCancel: document.getElementById('btn-queue-cancel'),
+  queueStatus: document.getElementById('queue-status'),
+  joinCodeForm: document.getElementById('join-code-form'),
+  joinCode: document.getElementById('join-code'),
+  inviteCode: document.getElementById('invite-code'),
 
   roomTitle: document.getElementById('room-title'),
   roomStatusBadge: document.getElementById('room-status-badge'),
@@ -84,6 +125,22 @@ const elements = {
   botSeatSelect: document.getElementById('bot-seat-select'),
   btnRemoveBot: document.getElementById('btn-remove-bot'),
   btnStartGame: document.getElementById('btn-start-game'),
+  btnForceStart: document.getElementById('btn-force-start'),
+  btnToggleReady: document.getElementById('btn-toggle-ready'),
+  btnLockRoom: document.getElementById('btn-lock-room'),
+  btnShuffleSeats: document.getElementById('btn-shuffle-seats'),
+  roomSettingsForm: document.getElementById('room-settings-form'),
+  settingVariant: document.getElementById('setting-variant'),
+  settingTurnTimeout: document.getElementById('setting-turn-timeout'),
+  settingDefenseTimeout: document.getElementById('setting-defense-timeout'),
+  settingBotDifficulty: document.getElementById('setting-bot-difficulty'),
+  settingVisibility: document.getElementById('setting-visibility'),
+  settingSeed: document.getElementById('setting-seed'),
+  settingAfkLimit: document.getElementById('setting-afk-limit'),
+  settingCorrespondence: document.getElementById('setting-correspondence'),
+  settingMoveHours: document.getElementById('setting-move-hours'),
+  settingSpectators: document.getElementById('setting-spectators'),
+  minHumans: document.getElementById('min-humans'),
   btnCopyInvite: document.getElementById('btn-copy-invite'),
   btnLeaveRoom: document.getElementById('btn-leave-room'),
 
@@ -93,9 +150,17 @@ const elements = {
   maxRounds: document.getElementById('max-rounds'),
   infoTurn: document.getElementById('info-turn'),
   btnLeaveGame: document.getElementById('btn-leave-game'),
+  btnTogglePause: document.getElementById('btn-toggle-pause'),
+  btnAdjourn: document.getElementById('btn-adjourn'),
   boardSeats: document.getElementById('board-seats'),
   factionSummary: document.getElementById('faction-summary'),
   turnBanner: document.getElementById('turn-banner'),
+  postGamePanel: document.getElementById('postgame-panel'),
+  postGameStatus: document.getElementById('postgame-status'),
+  btnVoteRematch: document.getElementById('btn-vote-rematch'),
+  btnVoteLeave: document.getElementById('btn-vote-leave'),
+  btnRematchStart: document.getElementById('btn-rematch-start'),
+  btnRematchLobby: document.getElementById('btn-rematch-lobby'),
   identityDisplay: document.getElementById('identity-display'),
   handContainer: document.getElementById('hand-cards'),
   handHint: document.getElementById('hand-hint'),
@@ -121,15 +186,15 @@ function resolveKind(kind) {
   return Number.isNaN(parsed) ? kind : parsed;
 }
 
-function getHandCardByIndex(index) {
+// 選牌一律以牌編號記錄，手牌重新排序或被抽走時不會誤選其他牌
+function getHandCardById(id) {
   const hand = state.privateSnapshot?.hand || [];
-  return hand.find((card) => card.index === index) || null;
+  return hand.find((card) => card.id === id) || null;
 }
 
 function getSelectedHandCards() {
-  const hand = state.privateSnapshot?.hand || [];
   const selected = Array.from(state.selectedCards)
-    .map((idx) => hand.find((card) => card.index === idx))
+    .map((id) => getHandCardById(id))
     .filter(Boolean);
   selected.sort((a, b) => a.index - b.index);
   return selected;
@@ -237,27 +302,36 @@ function setDisplayName(name) {
 async function requestAuth(endpoint, payload) {
   const response = await fetch(endpoint, {
     method: 'POST',
-    headers: { 'Content-Type': 'application/json' },
+    headers: { 'Content-Type': 'application/json', 'Accept-Language': UI_LOCALE },
     body: JSON.stringify(payload),
   });
   const data = await response.json().catch(() => ({}));
   if (!response.ok) {
-    throw new Error(data.error || '操作失敗');
+    throw apiError(data, '操作失敗');
   }
   return data;
 }
 
 async function fetchProfile(token) {
   const response = await fetch('/api/profile', {
-    headers: { Authorization: `Bearer ${token}` },
+    headers: { Authorization: `Bearer ${token}`, 'Accept-Language': UI_LOCALE },
   });
   const data = await response.json().catch(() => ({}));
   if (!response.ok) {
-    throw new Error(data.error || '取得會話資訊失敗');
+    throw apiError(data, '取得會話資訊失敗');
   }
   return data;
 }
 
+// 伺服器錯誤附帶穩定的 code，判斷時以 code 為準，error 文字僅供顯示
+function apiError(data, fallback) {
+  const error = new Error(data.error || fallback);
+  error.code = data.code || '';
+  return error;
+}
+
+const SESSION_ERROR_CODES = new Set(['session_required', 'session_expired']);
+
 function connect(displayName) {
   if (!state.sessionToken) {
     elements.loginOverlay.classList.remove('hidden');
@@ -285,17 +359,27 @@ function connect(displayName) {
   params.set('name', resolvedName);
   params.set('token', state.token);
   params.set('auth', state.sessionToken);
+  params.set('lang', UI_LOCALE);
+  params.set('protocol', String(PROTOCOL_VERSION));
   if (state.roomId) {
     params.set('room', state.roomId);
+  } else if (state.pendingInvite) {
+    params.set('invite', state.pendingInvite);
   }
   const ws = new WebSocket(`${proto}://${window.location.host}/ws?${params.toString()}`);
 
   ws.onopen = () => {
     state.ws = ws;
     state.roomId = state.roomId || null;
+    state.lastSeq = null;
+    state.resyncPending = false;
+    Object.values(state.stateVersions).forEach((versions) => versions.clear());
     elements.loginOverlay.classList.add('hidden');
     setView('lobby');
     sendMessage({ type: 'lobby_list', payload: {} });
+    sendMessage({ type: 'saved_games_list', payload: {} });
+    sendMessage({ type: 'inbox_list', payload: {} });
+    resendPendingRequests();
   };
 
   ws.onmessage = handleMessage;
@@ -308,6 +392,8 @@ function connect(displayName) {
       elements.loginOverlay.classList.remove('hidden');
       return;
     }
+    // 已由其他分頁接手時不自動重連，避免兩個分頁互相搶線
+    if (state.superseded) return;
     if (state.reconnectTimer) return;
     showToast('連線中斷，將嘗試重新連線…', 2600);
     state.reconnectTimer = setTimeout(() => {
@@ -329,6 +415,31 @@ function sendMessage(message) {
   state.ws.send(JSON.stringify(message));
 }
 
+// 對局指令帶上請求 ID，斷線重連後可安全重送；伺服器會忽略已處理過的相同 ID
+function sendRequest(message) {
+  const key = message.type;
+  if (Array.from(state.pendingRequests.values()).some((pending) => pending.type === key)) {
+    return;
+  }
+  const requestId = `${Date.now().toString(36)}-${Math.random().toString(36).slice(2, 10)}`;
+  const request = { ...message, requestId };
+  state.pendingRequests.set(requestId, request);
+  sendMessage(request);
+}
+
+function resendPendingRequests() {
+  state.pendingRequests.forEach((request) => sendMessage(request));
+}
+
+function handleRequestReply(payload, ok) {
+  const requestId = payload?.requestId;
+  if (!requestId || !state.pendingRequests.has(requestId)) return;
+  state.pendingRequests.delete(requestId);
+  if (!ok && payload.message) {
+    showToast(payload.message, 3600);
+  }
+}
+
 function handleMessage(event) {
   let message;
   try {
@@ -338,20 +449,29 @@ function handleMessage(event) {
     return;
   }
 
+  trackSequence(message);
   const { type, payload } = message;
   switch (type) {
     case 'welcome':
       handleWelcome(payload || {});
       break;
     case 'lobby_rooms':
+      rememberState(type, message.version, payload);
       handleLobbyRooms(payload || {});
       break;
     case 'lobby_state':
       handleLobbyState(payload || {});
       break;
     case 'public_state':
+      rememberState(type, message.version, payload);
       handlePublicState(payload || {});
       break;
+    case 'state_patch':
+      handleStatePatch(message.version, payload || {});
+      break;
+    case 'resync':
+      handleResync(payload || {});
+      break;
     case 'private_state':
       handlePrivateState(payload || {});
       break;
@@ -371,23 +491,173 @@ function handleMessage(event) {
         showToast(payload.message, 3600);
       }
       break;
+    case 'ack':
+      handleRequestReply(payload, true);
+      break;
+    case 'nack':
+      handleRequestReply(payload, false);
+      break;
+    case 'superseded':
+      state.superseded = true;
+      showToast(`${payload?.message || '此帳號已在其他分頁連線'}，重新整理本頁即可取回連線`, 8000);
+      break;
     case 'private_info':
       if (payload?.message) {
         showToast(payload.message, 3600);
       }
       break;
+    case 'room_left':
+      handleRoomLeft(payload || {});
+      break;
+    case 'saved_games':
+      state.savedGames = Array.isArray(payload?.games) ? payload.games : [];
+      renderSavedGames();
+      break;
+    case 'inbox':
+      state.inbox = Array.isArray(payload?.items) ? payload.items : [];
+      renderInbox();
+      break;
+    case 'saved_game_reopened':
+      if (payload?.roomId && window.confirm(`保存對局「${payload.name || payload.roomId}」已重新開啟，是否回到座位？`)) {
+        sendMessage({ type: 'saved_game_resume', payload: { roomId: payload.roomId } });
+      }
+      break;
+    case 'seat_claim_request':
+      handleSeatClaimRequest(payload || {});
+      break;
+    case 'seat_swap_request':
+      handleSeatSwapRequest(payload || {});
+      break;
+    case 'queue_status':
+      handleQueueStatus(payload || {});
+      break;
     default:
       console.debug('未處理訊息', message);
       break;
   }
 }
 
+// 房間訊息的 prev 應等於上一則收到的序號；不符表示中間有訊息漏收，向伺服器要求完整狀態
+function trackSequence(message) {
+  if (typeof message.seq !== 'number') return;
+  const prev = message.prev || 0;
+  const restart = message.type === 'welcome' || message.type === 'resync';
+  if (!restart && state.lastSeq !== null && prev !== state.lastSeq && !state.resyncPending) {
+    state.resyncPending = true;
+    sendMessage({ type: 'resync', payload: {} });
+  }
+  state.lastSeq = message.seq;
+}
+
+function handleResync(payload) {
+  state.resyncPending = false;
+  if (payload.public) {
+    rememberState('public_state', payload.version, payload.public);
+    handlePublicState(payload.public);
+  }
+  if (payload.private) {
+    handlePrivateState(payload.private);
+  }
+}
+
+// 保存收到的狀態版本並向伺服器確認，之後的差異以確認的版本為基準
+function rememberState(target, version, doc) {
+  const versions = state.stateVersions[target];
+  if (!versions || typeof version !== 'number' || !doc) return;
+  versions.set(version, JSON.parse(JSON.stringify(doc)));
+  while (versions.size > MAX_STATE_VERSIONS) {
+    versions.delete(versions.keys().next().value);
+  }
+  sendMessage({ type: 'state_ack', payload: { target, version } });
+}
+
+function requestFullState(target) {
+  if (target === 'public_state') {
+    if (state.resyncPending) return;
+    state.resyncPending = true;
+    sendMessage({ type: 'resync', payload: {} });
+    return;
+  }
+  sendMessage({ type: 'lobby_list', payload: {} });
+}
+
+function handleStatePatch(version, payload) {
+  const target = payload.target;
+  const versions = state.stateVersions[target];
+  if (!versions) return;
+  const base = versions.get(payload.base);
+  if (base === undefined) {
+    requestFullState(target);
+    return;
+  }
+  let doc;
+  try {
+    doc = applyJsonPatch(base, Array.isArray(payload.ops) ? payload.ops : []);
+  } catch (err) {
+    console.warn('套用狀態差異失敗', err);
+    requestFullState(target);
+    return;
+  }
+  // 伺服器只會以已確認的版本為基準，更早的版本不再需要
+  Array.from(versions.keys()).forEach((known) => {
+    if (known < payload.base) versions.delete(known);
+  });
+  rememberState(target, version, doc);
+  if (target === 'public_state') {
+    handlePublicState(doc);
+  } else {
+    handleLobbyRooms(doc);
+  }
+}
+
+// 套用 RFC 6902 JSON Patch（add、remove、replace），回傳新文件而不修改 doc
+function applyJsonPatch(doc, ops) {
+  let root = JSON.parse(JSON.stringify(doc));
+  ops.forEach((op) => {
+    const tokens = op.path === ''
+      ? []
+      : op.path.slice(1).split('/').map((token) => token.replace(/~1/g, '/').replace(/~0/g, '~'));
+    if (tokens.length === 0) {
+      root = op.op === 'remove' ? null : op.value;
+      return;
+    }
+    let parent = root;
+    for (let i = 0; i < tokens.length - 1; i += 1) {
+      parent = parent?.[Array.isArray(parent) ? Number(tokens[i]) : tokens[i]];
+      if (parent === undefined || parent === null) {
+        throw new Error(`路徑不存在：${op.path}`);
+      }
+    }
+    const key = tokens[tokens.length - 1];
+    if (Array.isArray(parent)) {
+      const index = key === '-' ? parent.length : Number(key);
+      if (op.op === 'add') {
+        parent.splice(index, 0, op.value);
+      } else if (op.op === 'remove') {
+        parent.splice(index, 1);
+      } else {
+        parent[index] = op.value;
+      }
+    } else if (op.op === 'remove') {
+      delete parent[key];
+    } else {
+      parent[key] = op.value;
+    }
+  });
+  return root;
+}
+
 function handleWelcome(payload) {
+  state.stateVersions.public_state.clear();
   state.roomId = payload.roomId || null;
   state.roomName = payload.roomName || '';
   state.roomStatus = payload.status || 'lobby';
   state.seatIndex = typeof payload.seatIndex === 'number' ? payload.seatIndex : -1;
   state.hostSeat = typeof payload.hostSeat === 'number' ? payload.hostSeat : state.hostSeat;
+  state.inviteCode = payload.inviteCode || '';
+  state.spectator = Boolean(payload.spectator);
+  state.pendingInvite = '';
+  handleQueueStatus({ queued: false });
   if (payload.token) {
     state.token = String(payload.token);
     try {
@@ -403,6 +673,7 @@ function handleWelcome(payload) {
 
   try {
     const url = new URL(window.location.href);
+    url.searchParams.delete('invite');
     if (state.roomId) {
       url.searchParams.set('room', state.roomId);
     } else {
@@ -421,6 +692,111 @@ function handleWelcome(payload) {
   }
 }
 
+function handleRoomLeft(payload) {
+  if (payload.roomId && payload.roomId !== state.roomId) return;
+  if (payload.reason) {
+    showToast(payload.reason, 4000);
+  }
+  resetRoomState();
+  setView('lobby');
+  sendMessage({ type: 'saved_games_list', payload: {} });
+  sendMessage({ type: 'inbox_list', payload: {} });
+}
+
+function handleSeatClaimRequest(payload) {
+  const accept = window.confirm(`${payload.name || '有玩家'} 申請接手 ${payload.seatName || `座位 #${payload.seat}`}，是否同意？`);
+  sendMessage({ type: 'seat_claim_response', payload: { seat: payload.seat, accept } });
+}
+
+function handleSeatSwapRequest(payload) {
+  const accept = window.confirm(`${payload.name || '有玩家'} 想與你交換座位（座位 #${payload.fromSeat}），是否同意？`);
+  sendMessage({ type: 'seat_swap_response', payload: { seat: payload.fromSeat, accept } });
+}
+
+function renderInbox() {
+  if (!elements.inboxList) return;
+  elements.inboxList.innerHTML = '';
+  if (state.inbox.length === 0) {
+    const hint = document.createElement('div');
+    hint.className = 'hint';
+    hint.textContent = '目前沒有等待你處理的通信對局。';
+    elements.inboxList.append(hint);
+    return;
+  }
+  state.inbox.forEach((item) => {
+    const card = document.createElement('div');
+    card.className = 'room-card';
+    const title = document.createElement('h3');
+    title.textContent = item.roomName || '未命名房間';
+    const meta = document.createElement('div');
+    meta.className = 'meta';
+    const action = item.kind === 'challenged' ? `${item.attacker || '有玩家'} 向你發起挑戰` : '輪到你行動';
+    const deadline = item.deadline ? new Date(item.deadline).toLocaleString() : '不限';
+    meta.innerHTML = `${action}<br>第 ${item.round || '-'} 回合<br>期限：${deadline}`;
+    const btn = document.createElement('button');
+    btn.type = 'button';
+    btn.textContent = '前往';
+    btn.addEventListener('click', () => {
+      sendMessage({ type: 'room_join', payload: { roomId: item.roomId } });
+    });
+    card.append(title, meta, btn);
+    elements.inboxList.append(card);
+  });
+}
+
+function renderSavedGames() {
+  if (!elements.savedGameList) return;
+  elements.savedGameList.innerHTML = '';
+  if (state.savedGames.length === 0) {
+    const hint = document.createElement('div');
+    hint.className = 'hint';
+    hint.textContent = '沒有保存中的對局。';
+    elements.savedGameList.append(hint);
+    return;
+  }
+  state.savedGames.forEach((saved) => {
+    const card = document.createElement('div');
+    card.className = 'room-card';
+    const title = document.createElement('h3');
+    title.textContent = saved.name || '未命名房間';
+    const meta = document.createElement('div');
+    meta.className = 'meta';
+    const savedAt = saved.savedAt ? new Date(saved.savedAt).toLocaleString() : '-';
+    meta.innerHTML = `第 ${saved.round || '-'} 回合<br>保存於：${savedAt}<br>玩家：${(saved.players || []).join('、')}`;
+    const btn = document.createElement('button');
+    btn.type = 'button';
+    btn.textContent = '重新開啟';
+    btn.addEventListener('click', () => {
+      sendMessage({ type: 'saved_game_resume', payload: { roomId: saved.roomId } });
+    });
+    card.append(title, meta, btn);
+    elements.savedGameList.append(card);
+  });
+}
+
+function handleQueueStatus(payload) {
+  state.queueStatus = payload.queued ? payload : null;
+  renderQueueStatus();
+}
+
+function renderQueueStatus() {
+  const status = state.queueStatus;
+  elements.btnQuickPlay?.classList.toggle('hidden', Boolean(status));
+  elements.btnQueueCancel?.classList.toggle('hidden', !status);
+  if (!elements.queueStatus) return;
+  elements.queueStatus.classList.toggle('hidden', !status);
+  if (!status) {
+    elements.queueStatus.textContent = '';
+    return;
+  }
+  let text = `配對中：第 ${status.position} 位，相同條件 ${status.compatible} 人`;
+  if (status.topUpAt) {
+    const seconds = Math.max(0, Math.ceil((status.topUpAt - Date.now()) / 1000));
+    text += `，約 ${seconds} 秒後以機器人補滿`;
+  }
+  elements.queueStatus.textContent = text;
+}
+
 function handleLobbyRooms(payload) {
   state.lobbyRooms = Array.isArray(payload.rooms) ? payload.rooms : [];
   renderLobby();
@@ -449,7 +825,7 @@ function handlePublicState(payload) {
 
   if (nextStatus === 'finished') {
     if (previousStatus !== 'finished' && !state.postGameMessage) {
-      state.postGameMessage = '對局結束，稍後返回大廳';
+      state.postGameMessage = '對局結束';
     }
   } else if (state.postGameMessage) {
     state.postGameMessage = '';
@@ -489,7 +865,8 @@ function handleDefensePrompt(payload) {
   const suit = payload.suit ? `花色 ${payload.suit}` : '特殊牌';
   const maxSelectable = payload.maxSelectable || 5;
   elements.defenseTitle.textContent = '防守選擇';
-  elements.defenseDescription.textContent = `${attackerName} 的出牌：${describeCards(payload.attackCards || [])}。可選擇最多 ${maxSelectable} 張 ${suit}。`;
+  const timeLimit = payload.deadline ? `請於 ${Math.max(0, Math.round((payload.deadline - Date.now()) / 1000))} 秒內回應。` : '';
+  elements.defenseDescription.textContent = `${attackerName} 的出牌：${describeCards(payload.attackCards || [])}。可選擇最多 ${maxSelectable} 張 ${suit}。${timeLimit}`;
   renderDefenseOptions(payload.options || [], maxSelectable);
   elements.defenseModal.classList.remove('hidden');
 }
@@ -504,27 +881,27 @@ function renderDefenseOptions(options, maxSelectable) {
   options.forEach((card) => {
     const div = document.createElement('div');
     div.className = 'card';
-    div.dataset.index = card.index;
+    div.dataset.id = card.id;
     div.dataset.kind = String(card.kind);
     div.textContent = translateCard(card);
     div.addEventListener('click', () => {
-      toggleDefenseSelection(card.index, maxSelectable, div);
+      toggleDefenseSelection(card.id, maxSelectable, div);
     });
     elements.defenseOptions.append(div);
   });
   updateDefenseButtons(maxSelectable);
 }
 
-function toggleDefenseSelection(index, maxSelectable, element) {
-  if (state.defenseSelection.has(index)) {
-    state.defenseSelection.delete(index);
+function toggleDefenseSelection(id, maxSelectable, element) {
+  if (state.defenseSelection.has(id)) {
+    state.defenseSelection.delete(id);
     element.classList.remove('selected');
   } else {
     if (state.defenseSelection.size >= maxSelectable) {
       showToast(`最多只能選 ${maxSelectable} 張牌`, 1800);
       return;
     }
-    state.defenseSelection.add(index);
+    state.defenseSelection.add(id);
     element.classList.add('selected');
   }
   updateDefenseButtons(maxSelectable);
@@ -562,20 +939,72 @@ function renderLobby() {
     title.textContent = room.name || '未命名房間';
     const meta = document.createElement('div');
     meta.className = 'meta';
-    meta.innerHTML = `房主：${room.host || '未知'}<br>狀態：${translateStatus(room.status)}<br>人數：${room.players || 0} / ${room.capacity || 8}`;
+    const rules = describeSettings(room.settings);
+    const lockLabel = room.locked ? '（已鎖定）' : room.hasPassword ? '（需密碼）' : '';
+    meta.innerHTML = `房主：${room.host || '未知'}<br>狀態：${translateStatus(room.status)}${lockLabel}<br>人數：${room.players || 0} / ${room.capacity || 8}${room.spectators ? `（觀戰 ${room.spectators}）` : ''}<br>規則：${rules}`;
     const btn = document.createElement('button');
     btn.type = 'button';
-    const canJoin = room.status === 'lobby' && (room.players || 0) < (room.capacity || 8);
+    const canJoin = room.status === 'lobby' && !room.locked && (room.players || 0) < (room.capacity || 8);
     btn.textContent = canJoin ? '加入房間' : '無法加入';
     btn.disabled = !canJoin;
     btn.addEventListener('click', () => {
-      sendMessage({ type: 'room_join', payload: { roomId: room.roomId } });
+      const payload = { roomId: room.roomId };
+      if (room.hasPassword) {
+        const password = window.prompt('請輸入房間密碼');
+        if (password === null) return;
+        payload.password = password;
+      }
+      sendMessage({ type: 'room_join', payload });
     });
     card.append(title, meta, btn);
+    if (room.settings?.allowSpectators) {
+      const watch = document.createElement('button');
+      watch.type = 'button';
+      watch.className = 'ghost';
+      watch.textContent = '觀戰';
+      watch.addEventListener('click', () => {
+        const payload = { roomId: room.roomId };
+        if (room.hasPassword) {
+          const password = window.prompt('請輸入房間密碼');
+          if (password === null) return;
+          payload.password = password;
+        }
+        sendMessage({ type: 'room_spectate', payload });
+      });
+      card.append(watch);
+    }
     elements.roomList.append(card);
   });
 }
 
+function describeSettings(settings) {
+  if (!settings) return '標準';
+  const parts = [settings.variant === 'quick' ? '快速' : '標準'];
+  if (settings.correspondence) {
+    parts.push(`通信對局 ${settings.moveHours} 小時`);
+  } else {
+    if (settings.turnTimeout) parts.push(`回合 ${settings.turnTimeout} 秒`);
+    if (settings.defenseTimeout) parts.push(`防守 ${settings.defenseTimeout} 秒`);
+  }
+  const difficulty = { easy: '簡單', normal: '普通', hard: '困難' }[settings.botDifficulty];
+  if (difficulty) parts.push(`機器人${difficulty}`);
+  if (settings.seeded) parts.push('固定種子');
+  return parts.join('・');
+}
+
+function renderRoomSettings(settings) {
+  if (!settings || !elements.roomSettingsForm || elements.roomSettingsForm.contains(document.activeElement)) return;
+  elements.settingVariant.value = settings.variant || 'standard';
+  elements.settingTurnTimeout.value = settings.turnTimeout || 0;
+  elements.settingDefenseTimeout.value = settings.defenseTimeout || 0;
+  elements.settingAfkLimit.value = settings.afkLimit ?? 2;
+  elements.settingCorrespondence.checked = Boolean(settings.correspondence);
+  elements.settingMoveHours.value = settings.moveHours || 24;
+  elements.settingBotDifficulty.value = settings.botDifficulty || 'normal';
+  elements.settingVisibility.value = settings.visibility || 'public';
+  elements.settingSpectators.checked = Boolean(settings.allowSpectators);
+}
+
 function renderRoom() {
   if (!state.roomState) return;
   elements.roomTitle.textContent = state.roomName || '-';
@@ -604,13 +1033,73 @@ function renderRoom() {
       status.textContent = '狀態：空位';
     } else if (seat.alive === false) {
       status.textContent = '狀態：淘汰';
+    } else if (seat.isBot) {
+      status.textContent = '狀態：機器人';
+    } else if (seat.index === state.roomState.hostSeat) {
+      status.textContent = '狀態：房主';
     } else {
-      status.textContent = seat.isBot ? '狀態：機器人' : '狀態：待命';
+      status.textContent = seat.ready ? '狀態：已準備' : '狀態：未準備';
     }
+    if (seat.ready) card.classList.add('ready');
 
     card.append(label, name, status);
+    if (state.seatIndex === state.hostSeat && seat.filled && !seat.isBot && seat.index !== state.seatIndex) {
+      card.append(buildModerationActions(seat));
+    }
+    if (state.roomStatus === 'lobby' && state.seatIndex >= 0 && seat.index !== state.seatIndex) {
+      card.append(buildSeatingAction(seat));
+    }
     elements.seatGrid.append(card);
   });
+
+  elements.btnLockRoom.textContent = state.roomState.locked ? '解除鎖定' : '鎖定房間';
+  elements.inviteCode.textContent = `邀請碼：${state.inviteCode || '-'}`;
+  renderRoomSettings(state.roomState.settings);
+
+  const mySeat = seats.find((seat) => seat.index === state.seatIndex);
+  elements.btnToggleReady.textContent = mySeat?.ready ? '取消準備' : '準備';
+  if (elements.minHumans && document.activeElement !== elements.minHumans) {
+    elements.minHumans.value = state.roomState.minHumans || 1;
+  }
+}
+
+function buildSeatingAction(seat) {
+  const btn = document.createElement('button');
+  btn.type = 'button';
+  btn.className = 'ghost';
+  if (!seat.filled) {
+    btn.textContent = '坐這裡';
+    btn.addEventListener('click', () => sendMessage({ type: 'seat_choose', payload: { seat: seat.index } }));
+  } else {
+    btn.textContent = '請求換位';
+    btn.addEventListener('click', () => sendMessage({ type: 'seat_swap_request', payload: { seat: seat.index } }));
+  }
+  const actions = document.createElement('div');
+  actions.className = 'seat-actions';
+  actions.append(btn);
+  return actions;
+}
+
+function buildModerationActions(seat) {
+  const actions = document.createElement('div');
+  actions.className = 'seat-actions';
+  const commands = [
+    { type: 'room_transfer_host', label: '轉讓房主' },
+    { type: 'room_kick', label: '請離' },
+    { type: 'room_ban', label: '封鎖', confirm: `確定封鎖 ${seat.name}？對方將無法再加入此房間。` },
+  ];
+  commands.forEach((command) => {
+    const btn = document.createElement('button');
+    btn.type = 'button';
+    btn.className = 'ghost';
+    btn.textContent = command.label;
+    btn.addEventListener('click', () => {
+      if (command.confirm && !window.confirm(command.confirm)) return;
+      sendMessage({ type: command.type, payload: { seat: seat.index } });
+    });
+    actions.append(btn);
+  });
+  return actions;
 }
 
 function renderGame() {
@@ -627,13 +1116,25 @@ function renderGame() {
     elements.infoRound.textContent = state.publicGame.snapshot.round ?? '-';
     elements.maxRounds.textContent = state.publicGame.snapshot.maxRounds ?? '12';
     const turnIdx = state.publicGame.currentTurn;
-    elements.infoTurn.textContent = turnIdx === state.seatIndex ? '你' : seatName(turnIdx);
+    const turnLabel = turnIdx === state.seatIndex ? '你' : seatName(turnIdx);
+    const deadline = state.publicGame.turnDeadline;
+    const remaining = deadline ? Math.max(0, Math.round((deadline - Date.now()) / 1000)) : null;
+    elements.infoTurn.textContent = remaining === null ? turnLabel : `${turnLabel}（${remaining} 秒）`;
+    if (state.publicGame.paused) {
+      elements.infoTurn.textContent = `${turnLabel}（已暫停）`;
+    }
+    const votes = state.publicGame.pauseVotes ? `（${state.publicGame.pauseVotes} 票）` : '';
+    elements.btnTogglePause.textContent = `${state.publicGame.paused ? '繼續' : '暫停'}${votes}`;
+    elements.btnTogglePause.classList.toggle('hidden', state.spectator || state.roomStatus !== 'running');
+    elements.btnAdjourn.classList.toggle('hidden', state.seatIndex !== state.hostSeat || state.roomStatus !== 'running');
   } else {
     elements.infoRound.textContent = '-';
     elements.infoTurn.textContent = '-';
   }
 
-  if (state.roomStatus === 'running') {
+  if (state.spectator) {
+    elements.factionSummary.textContent = '觀戰中';
+  } else if (state.roomStatus === 'running') {
     elements.factionSummary.textContent = '陣營情報保密中';
   } else if (state.roomStatus === 'finished') {
     elements.factionSummary.textContent = '對局已結束';
@@ -643,13 +1144,30 @@ function renderGame() {
 
   const myTurn = state.publicGame && state.publicGame.currentTurn === state.seatIndex;
   if (state.roomStatus === 'finished') {
-    const message = state.postGameMessage || '對局已結束，稍後返回大廳';
+    const message = state.postGameMessage || '對局已結束';
     elements.turnBanner.textContent = message;
     elements.turnBanner.classList.remove('hidden');
   } else {
     elements.turnBanner.textContent = '輪到你行動';
     elements.turnBanner.classList.toggle('hidden', !myTurn);
   }
+  renderPostGame();
+}
+
+function renderPostGame() {
+  const postGame = state.roomStatus === 'finished' ? state.roomState?.postGame : null;
+  elements.postGamePanel.classList.toggle('hidden', !postGame);
+  if (!postGame) return;
+  const myVote = postGame.votes?.[state.seatIndex] || '';
+  const remaining = Math.max(0, Math.round((postGame.deadline - Date.now()) / 1000));
+  const voteText = myVote === 'rematch' ? '你已投票再戰' : myVote === 'leave' ? '你已選擇離開' : '請選擇是否再戰';
+  elements.postGameStatus.textContent = `${voteText}｜再戰 ${postGame.rematchVotes}/${postGame.required} 票，約 ${remaining} 秒後返回待機`;
+  elements.btnVoteRematch.disabled = myVote === 'rematch';
+  elements.btnVoteLeave.disabled = myVote === 'leave';
+  const isHost = state.seatIndex === state.hostSeat;
+  elements.btnRematchStart.classList.toggle('hidden', !isHost);
+  elements.btnRematchLobby.classList.toggle('hidden', !isHost);
+  elements.btnRematchStart.disabled = !postGame.quorumReached;
 }
 
 function renderBoard() {
@@ -682,6 +1200,10 @@ function renderBoard() {
       status.textContent = '空位';
     } else if (seat.alive === false) {
       status.textContent = '淘汰';
+    } else if (seat.away) {
+      status.textContent = 'AI 代打中';
+    } else if (seat.offline) {
+      status.textContent = '離線（保留座位）';
     } else {
       status.textContent = seat.isBot ? '機器人' : '存活';
     }
@@ -692,6 +1214,17 @@ function renderBoard() {
     info.textContent = `手牌：${seat.alive === false ? 0 : handSize}`;
 
     card.append(name, status, info);
+    if (state.spectator && state.roomStatus === 'running' && seat.isBot && seat.alive !== false) {
+      const claim = document.createElement('button');
+      claim.type = 'button';
+      claim.className = 'ghost';
+      claim.textContent = '申請接手';
+      claim.addEventListener('click', (evt) => {
+        evt.stopPropagation();
+        sendMessage({ type: 'seat_claim', payload: { seat: seat.index } });
+      });
+      card.append(claim);
+    }
     elements.boardSeats.append(card);
   });
   updateBoardSelection();
@@ -708,14 +1241,14 @@ function renderHand() {
   cards.forEach((card) => {
     const div = document.createElement('div');
     div.className = 'card';
-    div.dataset.index = card.index;
+    div.dataset.id = card.id;
     div.dataset.kind = String(card.kind);
     div.textContent = translateCard(card);
-    if (state.selectedCards.has(card.index)) {
+    if (state.selectedCards.has(card.id)) {
       div.classList.add('selected');
     }
     div.addEventListener('click', () => {
-      toggleCardSelection(card.index, div);
+      toggleCardSelection(card.id, div);
     });
     elements.handContainer.append(div);
   });
@@ -772,13 +1305,13 @@ function updateBoardSelection() {
   });
 }
 
-function toggleCardSelection(index, element) {
-  const card = getHandCardByIndex(index);
+function toggleCardSelection(id, element) {
+  const card = getHandCardById(id);
   if (!card) {
     return;
   }
-  if (state.selectedCards.has(index)) {
-    state.selectedCards.delete(index);
+  if (state.selectedCards.has(id)) {
+    state.selectedCards.delete(id);
     element.classList.remove('selected');
   } else {
     const prospective = [...getSelectedHandCards(), card];
@@ -787,7 +1320,7 @@ function toggleCardSelection(index, element) {
       showToast(error, 2400);
       return;
     }
-    state.selectedCards.add(index);
+    state.selectedCards.add(id);
     element.classList.add('selected');
   }
   updateSelectionUI();
@@ -852,10 +1385,10 @@ function submitChallenge() {
     showToast(error, 3200);
     return;
   }
-  const cards = selectedCards.map((card) => card.index).sort((a, b) => a - b);
-  sendMessage({
+  const cardIds = selectedCards.map((card) => card.id);
+  sendRequest({
     type: 'action_challenge',
-    payload: { targetId: state.challengeTarget, cards },
+    payload: { targetId: state.challengeTarget, cardIds },
   });
   state.selectedCards.clear();
   updateSelectionUI();
@@ -943,6 +1476,12 @@ function restoreSession() {
     })
     .catch((err) => {
       console.warn('恢復登入狀態失敗', err);
+      // 僅在會話確定失效時登出；伺服器暫時無法回應則保留會話，交由重連流程處理
+      if (!SESSION_ERROR_CODES.has(err.code)) {
+        showToast(err.message, 3600);
+        connect(state.playerName);
+        return;
+      }
       clearSession();
       elements.loginOverlay.classList.remove('hidden');
       if (elements.authUsername) elements.authUsername.focus();
@@ -993,8 +1532,38 @@ function registerEventListeners() {
   elements.createRoomForm?.addEventListener('submit', (evt) => {
     evt.preventDefault();
     const name = elements.createRoomName.value.trim() || '未命名房間';
-    sendMessage({ type: 'room_create', payload: { name } });
+    const password = elements.createRoomPassword.value;
+    const isPrivate = elements.createRoomPrivate.checked;
+    sendMessage({ type: 'room_create', payload: { name, password, private: isPrivate } });
     elements.createRoomName.value = '';
+    elements.createRoomPassword.value = '';
+  });
+
+  elements.quickPlayForm?.addEventListener('submit', (evt) => {
+    evt.preventDefault();
+    sendMessage({
+      type: 'queue_join',
+      payload: {
+        variant: elements.quickPlayVariant.value,
+        rated: elements.quickPlayRated.checked,
+        allowBots: elements.quickPlayBots.checked,
+      },
+    });
+  });
+
+  elements.btnQueueCancel?.addEventListener('click', () => {
+    sendMessage({ type: 'queue_cancel', payload: {} });
+  });
+
+  elements.joinCodeForm?.addEventListener('submit', (evt) => {
+    evt.preventDefault();
+    const inviteCode = elements.joinCode.value.trim().toUpperCase();
+    if (!inviteCode) {
+      showToast('請輸入邀請碼');
+      return;
+    }
+    sendMessage({ type: 'room_join', payload: { inviteCode } });
+    elements.joinCode.value = '';
   });
 
   elements.addBotForm?.addEventListener('submit', (evt) => {
@@ -1017,14 +1586,63 @@ function registerEventListeners() {
     sendMessage({ type: 'start_game', payload: {} });
   });
 
+  elements.btnForceStart?.addEventListener('click', () => {
+    sendMessage({ type: 'start_game', payload: { force: true } });
+  });
+
+  elements.roomSettingsForm?.addEventListener('submit', (evt) => {
+    evt.preventDefault();
+    const payload = {
+      variant: elements.settingVariant.value,
+      turnTimeout: Number(elements.settingTurnTimeout.value) || 0,
+      defenseTimeout: Number(elements.settingDefenseTimeout.value) || 0,
+      afkLimit: Number(elements.settingAfkLimit.value) || 0,
+      correspondence: elements.settingCorrespondence.checked,
+      moveHours: Number(elements.settingMoveHours.value) || 24,
+      botDifficulty: elements.settingBotDifficulty.value,
+      visibility: elements.settingVisibility.value,
+      allowSpectators: elements.settingSpectators.checked,
+    };
+    const seed = elements.settingSeed.value.trim();
+    if (seed !== '') {
+      payload.seed = Number(seed) || 0;
+    }
+    sendMessage({ type: 'room_settings', payload });
+  });
+
+  elements.btnLockRoom?.addEventListener('click', () => {
+    sendMessage({ type: 'room_lock', payload: { locked: !state.roomState?.locked } });
+  });
+
+  elements.btnShuffleSeats?.addEventListener('click', () => {
+    sendMessage({ type: 'seat_shuffle', payload: {} });
+  });
+
+  elements.btnToggleReady?.addEventListener('click', () => {
+    const seats = Array.isArray(state.roomState?.seats) ? state.roomState.seats : [];
+    const mySeat = seats.find((seat) => seat.index === state.seatIndex);
+    sendMessage({ type: 'room_ready', payload: { ready: !mySeat?.ready } });
+  });
+
+  elements.minHumans?.addEventListener('change', () => {
+    const value = Number(elements.minHumans.value);
+    if (!Number.isInteger(value)) return;
+    sendMessage({ type: 'room_min_humans', payload: { minHumans: value } });
+  });
+
   elements.btnCopyInvite?.addEventListener('click', async () => {
     if (!state.roomId) {
       showToast('尚未進入房間');
       return;
     }
+    if (!state.inviteCode) {
+      showToast('入座後才能分享邀請連結');
+      return;
+    }
     try {
       const url = new URL(window.location.href);
-      url.searchParams.set('room', state.roomId);
+      url.searchParams.delete('room');
+      url.searchParams.set('invite', state.inviteCode);
       await navigator.clipboard.writeText(url.toString());
       showToast('已複製邀請連結。');
     } catch (err) {
@@ -1033,8 +1651,35 @@ function registerEventListeners() {
     }
   });
 
+  elements.btnVoteRematch?.addEventListener('click', () => {
+    sendMessage({ type: 'rematch_vote', payload: { vote: 'rematch' } });
+  });
+  elements.btnVoteLeave?.addEventListener('click', () => {
+    sendMessage({ type: 'rematch_vote', payload: { vote: 'leave' } });
+  });
+  elements.btnRematchStart?.addEventListener('click', () => {
+    sendMessage({ type: 'rematch_start', payload: {} });
+  });
+  elements.btnRematchLobby?.addEventListener('click', () => {
+    sendMessage({ type: 'rematch_lobby', payload: {} });
+  });
+
   elements.btnLeaveRoom?.addEventListener('click', leaveRoom);
   elements.btnLeaveGame?.addEventListener('click', leaveRoom);
+  elements.btnAdjourn?.addEventListener('click', () => {
+    if (!window.confirm('確定封存對局？所有玩家會回到大廳，之後可由「我的保存對局」重新開啟。')) return;
+    sendMessage({ type: 'game_adjourn', payload: {} });
+  });
+  elements.btnRefreshInbox?.addEventListener('click', () => {
+    sendMessage({ type: 'inbox_list', payload: {} });
+  });
+  elements.btnRefreshSaved?.addEventListener('click', () => {
+    sendMessage({ type: 'saved_games_list', payload: {} });
+  });
+  elements.btnTogglePause?.addEventListener('click', () => {
+    const type = state.publicGame?.paused ? 'game_resume' : 'game_pause';
+    sendMessage({ type, payload: {} });
+  });
 
   elements.btnSubmitChallenge?.addEventListener('click', submitChallenge);
   elements.btnClearSelection?.addEventListener('click', () => {
@@ -1047,8 +1692,8 @@ function registerEventListeners() {
       closeDefenseModal();
       return;
     }
-    const cards = Array.from(state.defenseSelection).sort((a, b) => a - b);
-    sendMessage({ type: 'action_defense', payload: { cards } });
+    const cardIds = Array.from(state.defenseSelection);
+    sendRequest({ type: 'action_defense', payload: { cardIds } });
     closeDefenseModal();
   });
 
@@ -1057,7 +1702,7 @@ function registerEventListeners() {
       closeDefenseModal();
       return;
     }
-    sendMessage({ type: 'action_defense', payload: { cards: [] } });
+    sendRequest({ type: 'action_defense', payload: { cardIds: [] } });
     closeDefenseModal();
   });
 
@@ -1074,13 +1719,19 @@ function leaveRoom() {
     return;
   }
   sendMessage({ type: 'room_leave', payload: {} });
+  resetRoomState();
+  setView('lobby');
+  sendMessage({ type: 'lobby_list', payload: {} });
+}
+
+function resetRoomState() {
   state.roomId = null;
+  state.lastSeq = null;
+  state.resyncPending = false;
   state.roomState = null;
   state.publicGame = null;
   state.privateSnapshot = null;
   state.selectedCards.clear();
-  setView('lobby');
-  sendMessage({ type: 'lobby_list', payload: {} });
 }
 
 setView('lobby');